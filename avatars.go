@@ -0,0 +1,230 @@
+package steam
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/steamid"
+)
+
+const (
+	avatarBaseURL    = "https://avatars.steamstatic.com/"
+	communityBaseURL = "https://steamcommunity.com"
+)
+
+// AvatarSize is the size of an avatar image.
+type AvatarSize int
+
+const (
+	// 32x32
+	AvatarSmall AvatarSize = iota
+	// 64x64
+	AvatarMedium
+	// 184x184
+	AvatarFull
+)
+
+func (s AvatarSize) suffix() string {
+	switch s {
+	case AvatarMedium:
+		return "_medium"
+	case AvatarFull:
+		return "_full"
+	default:
+		return ""
+	}
+}
+
+// AvatarURL returns the URL of the image of an avatar hash, as returned by Social.GetAvatar or found
+// in socialcache.Friend.Avatar. Empty or all-zero hashes are replaced by protocol.DefaultAvatar.
+func AvatarURL(hash string, size AvatarSize) string {
+	hash = strings.ToLower(hash)
+
+	if !protocol.ValidAvatar(hash) {
+		hash = protocol.DefaultAvatar
+	}
+
+	return avatarBaseURL + hash + size.suffix() + ".jpg"
+}
+
+// ProfileURL returns the URL of the community profile of a user.
+func ProfileURL(id steamid.SteamID) string {
+	return communityBaseURL + "/profiles/" + id.FormatString()
+}
+
+// VanityProfileURL returns the URL of the community profile of a user with the given custom URL
+// name.
+func VanityProfileURL(name string) string {
+	return communityBaseURL + "/id/" + url.PathEscape(name)
+}
+
+// DefaultAvatarCacheSize is the default maximum number of images cached by Avatars.
+const DefaultAvatarCacheSize = 500
+
+// Avatars downloads avatar images and caches them in memory.
+//
+// Avatar images never change for a given hash, so cached images don't expire, but the least
+// recently used ones are evicted when MaxCached is exceeded. Concurrent downloads of the same
+// image are made once.
+type Avatars struct {
+	// The HTTP client used to download images
+	HTTPClient *http.Client
+	// Maximum number of cached images, unlimited if 0
+	MaxCached int
+
+	mutex    sync.Mutex
+	cache    map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*avatarDownload
+}
+
+type cachedAvatar struct {
+	url   string
+	image []byte
+}
+
+// avatarDownload is a download shared by the concurrent Download calls of the same image.
+type avatarDownload struct {
+	done  chan struct{}
+	image []byte
+	err   error
+}
+
+func NewAvatars() *Avatars {
+	return &Avatars{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxCached:  DefaultAvatarCacheSize,
+		cache:      make(map[string]*list.Element),
+		lru:        list.New(),
+		inflight:   make(map[string]*avatarDownload),
+	}
+}
+
+// Download returns the JPEG image of an avatar hash, see AvatarURL, fetching it if it's not cached.
+// The returned slice is a copy and can be modified.
+func (a *Avatars) Download(ctx context.Context, hash string, size AvatarSize) ([]byte, error) {
+	u := AvatarURL(hash, size)
+
+	for {
+		a.mutex.Lock()
+
+		if image, ok := a.cached(u); ok {
+			a.mutex.Unlock()
+			return append([]byte(nil), image...), nil
+		}
+
+		d, ok := a.inflight[u]
+
+		if !ok {
+			d = &avatarDownload{done: make(chan struct{})}
+			a.inflight[u] = d
+			a.mutex.Unlock()
+
+			return a.download(ctx, u, d)
+		}
+
+		a.mutex.Unlock()
+
+		select {
+		case <-d.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if d.err == nil {
+			return append([]byte(nil), d.image...), nil
+		}
+
+		// the download was canceled by the context of another call, so it's retried with this one
+		if ctx.Err() == nil && (errors.Is(d.err, context.Canceled) || errors.Is(d.err, context.DeadlineExceeded)) {
+			continue
+		}
+
+		return nil, d.err
+	}
+}
+
+// download fetches an image, caching it and sharing the result with the calls waiting for d.
+func (a *Avatars) download(ctx context.Context, u string, d *avatarDownload) ([]byte, error) {
+	d.image, d.err = a.fetch(ctx, u)
+
+	a.mutex.Lock()
+	delete(a.inflight, u)
+
+	if d.err == nil {
+		a.add(u, d.image)
+	}
+
+	a.mutex.Unlock()
+	close(d.done)
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return append([]byte(nil), d.image...), nil
+}
+
+func (a *Avatars) fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("steam/avatars: GET %s: status code %d", u, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// cached returns a cached image, marking it as recently used. The mutex must be held.
+func (a *Avatars) cached(u string) ([]byte, bool) {
+	elem, ok := a.cache[u]
+
+	if !ok {
+		return nil, false
+	}
+
+	a.lru.MoveToFront(elem)
+
+	return elem.Value.(*cachedAvatar).image, true
+}
+
+// add caches an image, evicting the least recently used ones if MaxCached is exceeded. The mutex
+// must be held.
+func (a *Avatars) add(u string, image []byte) {
+	a.cache[u] = a.lru.PushFront(&cachedAvatar{url: u, image: image})
+
+	for a.MaxCached > 0 && a.lru.Len() > a.MaxCached {
+		oldest := a.lru.Back()
+		a.lru.Remove(oldest)
+		delete(a.cache, oldest.Value.(*cachedAvatar).url)
+	}
+}
+
+// Clear removes all cached images.
+func (a *Avatars) Clear() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.cache = make(map[string]*list.Element)
+	a.lru.Init()
+}
@@ -0,0 +1,178 @@
+package steam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+func TestAvatarURL(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		hash     string
+		size     AvatarSize
+		expected string
+	}{
+		{hash, AvatarSmall, avatarBaseURL + hash + ".jpg"},
+		{hash, AvatarMedium, avatarBaseURL + hash + "_medium.jpg"},
+		{"0123456789ABCDEF0123456789ABCDEF01234567", AvatarFull, avatarBaseURL + hash + "_full.jpg"},
+		{"", AvatarSmall, avatarBaseURL + protocol.DefaultAvatar + ".jpg"},
+		{"0000000000000000000000000000000000000000", AvatarFull, avatarBaseURL + protocol.DefaultAvatar + "_full.jpg"},
+	}
+
+	for _, test := range tests {
+		if u := AvatarURL(test.hash, test.size); u != test.expected {
+			t.Errorf("AvatarURL(%q, %d): expected %q, got %q", test.hash, test.size, test.expected, u)
+		}
+	}
+}
+
+func TestProfileURL(t *testing.T) {
+	if u := ProfileURL(testFriendID); u != "https://steamcommunity.com/profiles/76561197960287930" {
+		t.Errorf("unexpected profile URL %q", u)
+	}
+}
+
+func TestVanityProfileURL(t *testing.T) {
+	tests := map[string]string{
+		"gabelogannewell": "https://steamcommunity.com/id/gabelogannewell",
+		"a b/c":           "https://steamcommunity.com/id/a%20b%2Fc",
+	}
+
+	for name, expected := range tests {
+		if u := VanityProfileURL(name); u != expected {
+			t.Errorf("VanityProfileURL(%q): expected %q, got %q", name, expected, u)
+		}
+	}
+}
+
+// newTestAvatars returns Avatars whose downloads are served by handler.
+func newTestAvatars(t *testing.T, handler http.HandlerFunc) *Avatars {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	avatars := NewAvatars()
+	avatars.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	return avatars
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAvatarsDownload(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests = make(map[string]int)
+	)
+
+	release := make(chan struct{})
+
+	avatars := newTestAvatars(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests[r.URL.Path]++
+		mutex.Unlock()
+
+		<-release
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	path := "/" + hash + ".jpg"
+
+	var wg sync.WaitGroup
+
+	images := make([][]byte, 5)
+
+	for i := range images {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			image, err := avatars.Download(context.Background(), hash, AvatarSmall)
+
+			if err != nil {
+				t.Errorf("Download: %v", err)
+			}
+
+			images[i] = image
+		}(i)
+	}
+
+	close(release)
+	wg.Wait()
+
+	for _, image := range images {
+		if string(image) != path {
+			t.Fatalf("unexpected image %q", image)
+		}
+	}
+
+	mutex.Lock()
+	n := requests[path]
+	mutex.Unlock()
+
+	if n != 1 {
+		t.Errorf("expected concurrent downloads to be made once, got %d requests", n)
+	}
+
+	// returned images are copies
+	images[0][0] = 'x'
+
+	if image, _ := avatars.Download(context.Background(), hash, AvatarSmall); string(image) != path {
+		t.Errorf("expected cached image not to be modified, got %q", image)
+	}
+
+	// the least recently used image is evicted
+	avatars.MaxCached = 1
+
+	if _, err := avatars.Download(context.Background(), hash, AvatarFull); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	if _, err := avatars.Download(context.Background(), hash, AvatarSmall); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if n := requests[path]; n != 2 {
+		t.Errorf("expected evicted image to be downloaded again, got %d requests", n)
+	}
+}
+
+func TestAvatarsDownloadError(t *testing.T) {
+	avatars := newTestAvatars(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	if _, err := avatars.Download(context.Background(), "", AvatarSmall); err == nil {
+		t.Error("expected error for missing image")
+	}
+
+	if avatars.lru.Len() != 0 {
+		t.Errorf("expected failed download not to be cached, got %d images", avatars.lru.Len())
+	}
+}
@@ -0,0 +1,145 @@
+// Package bbcode parses the BBCode used by Steam chat messages.
+//
+// Besides the usual formatting tags ([b], [i], [url=...], [quote=...], [spoiler], [code]), Steam
+// messages use BBCode for emoticons ([emoticon]steamhappy[/emoticon]), stickers
+// ([sticker type="Delighted"][/sticker]), invites, mentions and dice rolls.
+package bbcode
+
+import (
+	"strings"
+)
+
+// Tags used by Steam chat messages.
+const (
+	TagEmoticon    = "emoticon"
+	TagSticker     = "sticker"
+	TagURL         = "url"
+	TagImage       = "img"
+	TagQuote       = "quote"
+	TagCode        = "code"
+	TagSpoiler     = "spoiler"
+	TagMention     = "mention"
+	TagGameInvite  = "gameinvite"
+	TagLobbyInvite = "lobbyinvite"
+	TagRandom      = "random"
+	TagFlip        = "flip"
+)
+
+type NodeType int
+
+const (
+	TextNode NodeType = iota
+	TagNode
+)
+
+// Node is a text or a tag of a parsed message.
+type Node struct {
+	Type NodeType
+	// Text of text nodes
+	Text string
+	// Lowercase name of tag nodes
+	Tag string
+	// Default argument of tag nodes, as in [url=https://example.com]
+	Value string
+	// Named arguments of tag nodes, as in [sticker type="Delighted"]
+	Attrs    map[string]string
+	Children []*Node
+}
+
+// PlainText returns the text of the node and its children, with emoticons formatted as ":name:"
+// and without stickers.
+func (n *Node) PlainText() string {
+	b := &strings.Builder{}
+	n.writePlainText(b)
+	return b.String()
+}
+
+func (n *Node) writePlainText(b *strings.Builder) {
+	if n.Type == TextNode {
+		b.WriteString(n.Text)
+		return
+	}
+
+	switch n.Tag {
+	case TagEmoticon:
+		b.WriteString(":")
+		writePlainText(b, n.Children)
+		b.WriteString(":")
+	case TagSticker, TagImage:
+	case TagURL:
+		if len(n.Children) == 0 {
+			b.WriteString(n.Value)
+		} else {
+			writePlainText(b, n.Children)
+		}
+	default:
+		writePlainText(b, n.Children)
+	}
+}
+
+// PlainText returns the text of the nodes, as Node.PlainText.
+func PlainText(nodes []*Node) string {
+	b := &strings.Builder{}
+	writePlainText(b, nodes)
+	return b.String()
+}
+
+func writePlainText(b *strings.Builder, nodes []*Node) {
+	for _, n := range nodes {
+		n.writePlainText(b)
+	}
+}
+
+// Walk calls fn for each node in depth-first order. Children of a node are skipped if fn returns
+// false.
+func Walk(nodes []*Node, fn func(*Node) bool) {
+	for _, n := range nodes {
+		if fn(n) {
+			Walk(n.Children, fn)
+		}
+	}
+}
+
+// Find returns the tag nodes with the given name.
+func Find(nodes []*Node, tag string) []*Node {
+	var found []*Node
+
+	Walk(nodes, func(n *Node) bool {
+		if n.Type == TagNode && n.Tag == tag {
+			found = append(found, n)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// Emoticons returns the names of the emoticons in the nodes.
+func Emoticons(nodes []*Node) []string {
+	var names []string
+
+	for _, n := range Find(nodes, TagEmoticon) {
+		names = append(names, PlainText(n.Children))
+	}
+
+	return names
+}
+
+// Stickers returns the types of the stickers in the nodes.
+func Stickers(nodes []*Node) []string {
+	var types []string
+
+	for _, n := range Find(nodes, TagSticker) {
+		types = append(types, n.Attrs["type"])
+	}
+
+	return types
+}
+
+// Escape escapes the characters of text that would be parsed as BBCode.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
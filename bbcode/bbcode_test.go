@@ -0,0 +1,137 @@
+package bbcode_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/13k/go-steam/bbcode"
+)
+
+func text(s string) *bbcode.Node {
+	return &bbcode.Node{Type: bbcode.TextNode, Text: s}
+}
+
+func tag(name, value string, attrs map[string]string, children ...*bbcode.Node) *bbcode.Node {
+	return &bbcode.Node{Type: bbcode.TagNode, Tag: name, Value: value, Attrs: attrs, Children: children}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		Subject  string
+		Expected []*bbcode.Node
+	}{
+		{
+			Subject:  "",
+			Expected: nil,
+		},
+		{
+			Subject:  "hello",
+			Expected: []*bbcode.Node{text("hello")},
+		},
+		{
+			Subject: "hi [b]there[/b]!",
+			Expected: []*bbcode.Node{
+				text("hi "),
+				tag("b", "", nil, text("there")),
+				text("!"),
+			},
+		},
+		{
+			Subject: "[URL=https://example.com/?a=1]link[/url]",
+			Expected: []*bbcode.Node{
+				tag("url", "https://example.com/?a=1", nil, text("link")),
+			},
+		},
+		{
+			Subject: `[sticker type="Delighted" limit="0"][/sticker]`,
+			Expected: []*bbcode.Node{
+				tag("sticker", "", map[string]string{"type": "Delighted", "limit": "0"}),
+			},
+		},
+		{
+			Subject: `[quote author="a \"b\"" time=10]q[/quote] [emoticon]steamhappy[/emoticon]`,
+			Expected: []*bbcode.Node{
+				tag("quote", "", map[string]string{"author": `a "b"`, "time": "10"}, text("q")),
+				text(" "),
+				tag("emoticon", "", nil, text("steamhappy")),
+			},
+		},
+		{
+			Subject:  `\[b\] is bold, \\ is a backslash`,
+			Expected: []*bbcode.Node{text(`[b] is bold, \ is a backslash`)},
+		},
+		{
+			Subject:  "[1] [lol] [/i] [b",
+			Expected: []*bbcode.Node{text("[1] [lol] [/i] [b")},
+		},
+		{
+			Subject: "[b]bold [i]both[/b] no",
+			Expected: []*bbcode.Node{
+				tag("b", "", nil, text("bold [i]both")),
+				text(" no"),
+			},
+		},
+		{
+			Subject: "[code][b]x[/b][/code]",
+			Expected: []*bbcode.Node{
+				tag("code", "", nil, text("[b]x[/b]")),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := bbcode.Parse(testCase.Subject)
+
+		if !reflect.DeepEqual(actual, testCase.Expected) {
+			t.Errorf("Parse(%q): expected %s, got %s", testCase.Subject, dump(testCase.Expected), dump(actual))
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	nodes := bbcode.Parse(
+		`[b]hi[/b] [emoticon]steamhappy[/emoticon] [url=https://example.com][/url] ` +
+			`[url=https://example.com]site[/url][sticker type="Delighted"][/sticker]`,
+	)
+
+	expected := "hi :steamhappy: https://example.com site"
+
+	if actual := bbcode.PlainText(nodes); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	if emoticons := bbcode.Emoticons(nodes); !reflect.DeepEqual(emoticons, []string{"steamhappy"}) {
+		t.Errorf("unexpected emoticons %v", emoticons)
+	}
+
+	if stickers := bbcode.Stickers(nodes); !reflect.DeepEqual(stickers, []string{"Delighted"}) {
+		t.Errorf("unexpected stickers %v", stickers)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	subject := `[b]not bold[/b] \o/`
+
+	if nodes := bbcode.Parse(bbcode.Escape(subject)); !reflect.DeepEqual(nodes, []*bbcode.Node{text(subject)}) {
+		t.Errorf("expected escaped text to be parsed as text, got %s", dump(nodes))
+	}
+}
+
+func dump(nodes []*bbcode.Node) string {
+	s := "["
+
+	for i, n := range nodes {
+		if i > 0 {
+			s += " "
+		}
+
+		if n.Type == bbcode.TextNode {
+			s += "text(" + n.Text + ")"
+			continue
+		}
+
+		s += n.Tag + "(" + n.Value + ")" + dump(n.Children)
+	}
+
+	return s + "]"
+}
@@ -0,0 +1,266 @@
+package bbcode
+
+import (
+	"strings"
+)
+
+// Parse parses a message into a tree of nodes.
+//
+// Parsing never fails: brackets that don't form a tag, closing tags without an opening tag and
+// tags that are never closed are kept as text. Characters escaped with a backslash (`\[`, `\]` and
+// `\\`) are always text. The content of [code] tags is not parsed.
+func Parse(text string) []*Node {
+	p := &parser{stack: []*frame{{node: &Node{Type: TagNode}}}}
+	p.parse(text)
+	return p.stack[0].node.Children
+}
+
+// frame is an open tag, along with its source text, which is restored if it's never closed.
+type frame struct {
+	node *Node
+	raw  string
+}
+
+type parser struct {
+	stack []*frame
+	text  strings.Builder
+}
+
+func (p *parser) top() *frame {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) parse(text string) {
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		if c == '\\' && i+1 < len(text) && strings.IndexByte(`\[]`, text[i+1]) >= 0 {
+			p.text.WriteByte(text[i+1])
+			i += 2
+			continue
+		}
+
+		if c == '[' {
+			if t, n := parseTag(text[i:]); n > 0 && p.accepts(t) {
+				p.flushText()
+				p.handleTag(t, text[i:i+n])
+				i += n
+				continue
+			}
+		}
+
+		p.text.WriteByte(c)
+		i++
+	}
+
+	p.flushText()
+
+	for len(p.stack) > 1 {
+		p.unwind()
+	}
+}
+
+// accepts reports whether the tag is parsed in the current context, since [code] tags can only be
+// closed.
+func (p *parser) accepts(t *tag) bool {
+	if top := p.top().node; top.Tag == TagCode {
+		return t.closing && t.name == TagCode
+	}
+
+	return true
+}
+
+func (p *parser) handleTag(t *tag, raw string) {
+	if !t.closing {
+		node := &Node{Type: TagNode, Tag: t.name, Value: t.value, Attrs: t.attrs}
+		p.stack = append(p.stack, &frame{node: node, raw: raw})
+		return
+	}
+
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].node.Tag != t.name {
+			continue
+		}
+
+		// tags opened after the one being closed were never closed
+		for len(p.stack) > i+1 {
+			p.unwind()
+		}
+
+		closed := p.top().node
+		p.stack = p.stack[:len(p.stack)-1]
+		p.appendNode(closed)
+
+		return
+	}
+
+	appendText(p.top().node, raw)
+}
+
+// unwind pops the top tag, which was never closed, keeping its source and children as text.
+func (p *parser) unwind() {
+	f := p.top()
+	p.stack = p.stack[:len(p.stack)-1]
+
+	appendText(p.top().node, f.raw)
+
+	for _, child := range f.node.Children {
+		p.appendNode(child)
+	}
+}
+
+func (p *parser) flushText() {
+	if p.text.Len() == 0 {
+		return
+	}
+
+	appendText(p.top().node, p.text.String())
+	p.text.Reset()
+}
+
+func (p *parser) appendNode(n *Node) {
+	if n.Type == TextNode {
+		appendText(p.top().node, n.Text)
+		return
+	}
+
+	parent := p.top().node
+	parent.Children = append(parent.Children, n)
+}
+
+// appendText appends text to the children of parent, merging it with the last child if it's text.
+func appendText(parent *Node, text string) {
+	if text == "" {
+		return
+	}
+
+	if n := len(parent.Children); n > 0 && parent.Children[n-1].Type == TextNode {
+		parent.Children[n-1].Text += text
+		return
+	}
+
+	parent.Children = append(parent.Children, &Node{Type: TextNode, Text: text})
+}
+
+type tag struct {
+	name    string
+	closing bool
+	value   string
+	attrs   map[string]string
+}
+
+// parseTag parses the tag at the start of s, returning its length or 0 if s doesn't start with a
+// tag.
+func parseTag(s string) (*tag, int) {
+	t := &tag{}
+	i := 1
+
+	if i < len(s) && s[i] == '/' {
+		t.closing = true
+		i++
+	}
+
+	name, i := scanName(s, i)
+
+	if name == "" {
+		return nil, 0
+	}
+
+	t.name = strings.ToLower(name)
+
+	if t.closing {
+		if i < len(s) && s[i] == ']' {
+			return t, i + 1
+		}
+
+		return nil, 0
+	}
+
+	if i < len(s) && s[i] == '=' {
+		var ok bool
+
+		if t.value, i, ok = scanValue(s, i+1); !ok {
+			return nil, 0
+		}
+	}
+
+	for i < len(s) {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+
+		if i < len(s) && s[i] == ']' {
+			return t, i + 1
+		}
+
+		var key, value string
+
+		if key, i = scanName(s, i); key == "" {
+			return nil, 0
+		}
+
+		if i < len(s) && s[i] == '=' {
+			var ok bool
+
+			if value, i, ok = scanValue(s, i+1); !ok {
+				return nil, 0
+			}
+		}
+
+		if t.attrs == nil {
+			t.attrs = make(map[string]string)
+		}
+
+		t.attrs[strings.ToLower(key)] = value
+	}
+
+	return nil, 0
+}
+
+// scanName scans a tag or argument name, a letter followed by letters, digits, '_' or '-'.
+func scanName(s string, i int) (string, int) {
+	start := i
+
+	for i < len(s) {
+		c := s[i]
+		letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+
+		if !letter && (i == start || !(c >= '0' && c <= '9' || c == '_' || c == '-')) {
+			break
+		}
+
+		i++
+	}
+
+	return s[start:i], i
+}
+
+// scanValue scans an argument value, either quoted (with backslash escapes) or ending at a space
+// or at the end of the tag.
+func scanValue(s string, i int) (string, int, bool) {
+	if i < len(s) && s[i] == '"' {
+		b := &strings.Builder{}
+
+		for i++; i < len(s); i++ {
+			switch c := s[i]; {
+			case c == '\\' && i+1 < len(s):
+				i++
+				b.WriteByte(s[i])
+			case c == '"':
+				return b.String(), i + 1, true
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+		return "", i, false
+	}
+
+	start := i
+
+	for i < len(s) && s[i] != ' ' && s[i] != ']' {
+		i++
+	}
+
+	return s[start:i], i, true
+}
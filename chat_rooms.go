@@ -0,0 +1,714 @@
+package steam
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+// ChatInviteLinkBaseURL is the base URL of chat room group invite links.
+const ChatInviteLinkBaseURL = "https://s.team/chat/"
+
+// ChatRooms provides access to the ChatRoom service, which is used by the current Steam chat for
+// group chats. A chat room group has one or more chat rooms (channels), where messages are sent.
+//
+// The groups the account is a member of are cached in Groups. The cache is filled by
+// GetMyChatRoomGroups and kept up to date with the notifications sent by Steam.
+type ChatRooms struct {
+	Groups *socialcache.ChatRoomGroupsList
+
+	client *Client
+}
+
+var _ protocol.PacketHandler = (*ChatRooms)(nil)
+
+func NewChatRooms(client *Client) *ChatRooms {
+	return &ChatRooms{
+		Groups: socialcache.NewChatRoomGroupsList(),
+		client: client,
+	}
+}
+
+// GetMyChatRoomGroups fetches the chat room groups the account is a member of, replacing the
+// cached ones.
+func (r *ChatRooms) GetMyChatRoomGroups(ctx context.Context) ([]socialcache.ChatRoomGroup, error) {
+	req := &pbc.CChatRoom_GetMyChatRoomGroups_Request{}
+	resp := &pbc.CChatRoom_GetMyChatRoomGroups_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetMyChatRoomGroups#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	groups := make([]socialcache.ChatRoomGroup, 0, len(resp.GetChatRoomGroups()))
+
+	r.Groups.Clear()
+
+	for _, pair := range resp.GetChatRoomGroups() {
+		group := newChatRoomGroup(pair.GetGroupSummary())
+		r.Groups.Set(*group)
+		groups = append(groups, *group)
+	}
+
+	return groups, nil
+}
+
+// GetChatRoomGroupState fetches the state of a chat room group, including its members, and updates
+// the cache if it's a group the account is a member of.
+func (r *ChatRooms) GetChatRoomGroupState(ctx context.Context, chatGroupID uint64) (*socialcache.ChatRoomGroup, error) {
+	req := &pbc.CChatRoom_GetChatRoomGroupState_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+	resp := &pbc.CChatRoom_GetChatRoomGroupState_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetChatRoomGroupState#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	group, err := r.Groups.ByID(chatGroupID)
+	cached := err == nil
+
+	if !cached {
+		group = socialcache.ChatRoomGroup{ChatGroupID: chatGroupID}
+	}
+
+	applyChatRoomGroupState(&group, resp.GetState())
+
+	if cached {
+		r.Groups.Set(group)
+	}
+
+	return &group, nil
+}
+
+// JoinChatRoomGroup joins a chat room group, with an invite code if the account was not invited. A
+// ChatRoomGroupUserStateEvent is also emitted when Steam notifies the change.
+func (r *ChatRooms) JoinChatRoomGroup(
+	ctx context.Context,
+	chatGroupID uint64,
+	inviteCode string,
+) (*socialcache.ChatRoomGroup, error) {
+	req := &pbc.CChatRoom_JoinChatRoomGroup_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+
+	if inviteCode != "" {
+		req.InviteCode = proto.String(inviteCode)
+	}
+
+	resp := &pbc.CChatRoom_JoinChatRoomGroup_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.JoinChatRoomGroup#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	group := &socialcache.ChatRoomGroup{ChatGroupID: chatGroupID}
+	applyChatRoomGroupState(group, resp.GetState())
+	r.Groups.Set(*group)
+
+	return group, nil
+}
+
+// LeaveChatRoomGroup leaves a chat room group.
+func (r *ChatRooms) LeaveChatRoomGroup(ctx context.Context, chatGroupID uint64) error {
+	req := &pbc.CChatRoom_LeaveChatRoomGroup_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+
+	if err := r.client.CallService(ctx, "ChatRoom.LeaveChatRoomGroup#1", req, nil); err != nil {
+		return err
+	}
+
+	r.Groups.Remove(chatGroupID)
+
+	return nil
+}
+
+// SentChatRoomMessage is the response to sending a message to a chat room.
+type SentChatRoomMessage struct {
+	ModifiedMessage      string
+	MessageWithoutBBCode string
+	ServerTimestamp      time.Time
+	Ordinal              uint32
+}
+
+// SendChatMessage sends a BBCode formatted message to a chat room of a chat room group, echoing it
+// to the other sessions of the account.
+func (r *ChatRooms) SendChatMessage(
+	ctx context.Context,
+	chatGroupID, chatID uint64,
+	message string,
+) (*SentChatRoomMessage, error) {
+	req := &pbc.CChatRoom_SendChatMessage_Request{
+		ChatGroupId:  proto.Uint64(chatGroupID),
+		ChatId:       proto.Uint64(chatID),
+		Message:      proto.String(message),
+		EchoToSender: proto.Bool(true),
+	}
+
+	resp := &pbc.CChatRoom_SendChatMessage_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.SendChatMessage#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return &SentChatRoomMessage{
+		ModifiedMessage:      resp.GetModifiedMessage(),
+		MessageWithoutBBCode: resp.GetMessageWithoutBbCode(),
+		ServerTimestamp:      time.Unix(int64(resp.GetServerTimestamp()), 0),
+		Ordinal:              resp.GetOrdinal(),
+	}, nil
+}
+
+// AckChatMessage marks the messages of a chat room received up to the given timestamp as read.
+func (r *ChatRooms) AckChatMessage(chatGroupID, chatID uint64, timestamp time.Time) {
+	r.client.SendServiceNotification("ChatRoom.AckChatMessage#1", &pbc.CChatRoom_AckChatMessage_Notification{
+		ChatGroupId: proto.Uint64(chatGroupID),
+		ChatId:      proto.Uint64(chatID),
+		Timestamp:   proto.Uint32(uint32(timestamp.Unix())),
+	})
+}
+
+// ChatRoomMessage is a message of the history of a chat room.
+type ChatRoomMessage struct {
+	Sender    steamid.SteamID `json:",string"`
+	Timestamp time.Time
+	Ordinal   uint32
+	Message   string
+	// Set for messages generated by Steam, like members joining, in which case Message is empty
+	ServerMessage *ChatRoomServerMessage
+	Deleted       bool
+}
+
+// ChatRoomServerMessage is a message generated by Steam in a chat room.
+type ChatRoomServerMessage struct {
+	Message      pbc.EChatRoomServerMessage
+	StringParam  string
+	SteamIDParam steamid.SteamID `json:",string"`
+}
+
+func newChatRoomServerMessage(msg *pbc.ServerMessage) *ChatRoomServerMessage {
+	if msg == nil {
+		return nil
+	}
+
+	return &ChatRoomServerMessage{
+		Message:      msg.GetMessage(),
+		StringParam:  msg.GetStringParam(),
+		SteamIDParam: accountSteamID(msg.GetAccountidParam()),
+	}
+}
+
+// ChatRoomHistoryOptions are the options of ChatRooms.GetMessageHistory.
+type ChatRoomHistoryOptions struct {
+	// Maximum number of messages, Steam's default if 0
+	MaxCount uint32
+	// Only messages sent after this time, if not zero
+	StartTime time.Time
+	// Only messages sent before this one, to fetch further pages
+	Before *ChatRoomMessage
+}
+
+// ChatRoomHistory is a page of the history of a chat room, newest messages first.
+type ChatRoomHistory struct {
+	Messages      []*ChatRoomMessage
+	MoreAvailable bool
+}
+
+// GetMessageHistory fetches the history of a chat room.
+func (r *ChatRooms) GetMessageHistory(
+	ctx context.Context,
+	chatGroupID, chatID uint64,
+	opts *ChatRoomHistoryOptions,
+) (*ChatRoomHistory, error) {
+	if opts == nil {
+		opts = &ChatRoomHistoryOptions{}
+	}
+
+	req := &pbc.CChatRoom_GetMessageHistory_Request{
+		ChatGroupId: proto.Uint64(chatGroupID),
+		ChatId:      proto.Uint64(chatID),
+	}
+
+	if opts.MaxCount != 0 {
+		req.MaxCount = proto.Uint32(opts.MaxCount)
+	}
+
+	if !opts.StartTime.IsZero() {
+		req.StartTime = proto.Uint32(uint32(opts.StartTime.Unix()))
+	}
+
+	if opts.Before != nil {
+		req.LastTime = proto.Uint32(uint32(opts.Before.Timestamp.Unix()))
+		req.LastOrdinal = proto.Uint32(opts.Before.Ordinal)
+	}
+
+	resp := &pbc.CChatRoom_GetMessageHistory_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetMessageHistory#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	history := &ChatRoomHistory{MoreAvailable: resp.GetMoreAvailable()}
+
+	for _, m := range resp.GetMessages() {
+		history.Messages = append(history.Messages, &ChatRoomMessage{
+			Sender:        accountSteamID(m.GetSender()),
+			Timestamp:     time.Unix(int64(m.GetServerTimestamp()), 0),
+			Ordinal:       m.GetOrdinal(),
+			Message:       m.GetMessage(),
+			ServerMessage: newChatRoomServerMessage(m.GetServerMessage()),
+			Deleted:       m.GetDeleted(),
+		})
+	}
+
+	return history, nil
+}
+
+// GetRoles fetches the roles of a chat room group.
+func (r *ChatRooms) GetRoles(ctx context.Context, chatGroupID uint64) ([]socialcache.ChatRole, error) {
+	req := &pbc.CChatRoom_GetRoles_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+	resp := &pbc.CChatRoom_GetRoles_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetRoles#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return newChatRoles(resp.GetRoles()), nil
+}
+
+// ChatRoleActions are the permissions granted by a role of a chat room group.
+type ChatRoleActions struct {
+	RoleID                       uint64 `json:",string"`
+	CanCreateRenameDeleteChannel bool
+	CanKick                      bool
+	CanBan                       bool
+	CanInvite                    bool
+	CanChangeTaglineAvatarName   bool
+	CanChat                      bool
+	CanViewHistory               bool
+	CanChangeGroupRoles          bool
+	CanChangeUserRoles           bool
+	CanMentionAll                bool
+	CanSetWatchingBroadcast      bool
+}
+
+// GetRoleActions fetches the permissions of a role of a chat room group, or of all roles if roleID
+// is 0.
+func (r *ChatRooms) GetRoleActions(ctx context.Context, chatGroupID, roleID uint64) ([]*ChatRoleActions, error) {
+	req := &pbc.CChatRoom_GetRoleActions_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+
+	if roleID != 0 {
+		req.RoleId = proto.Uint64(roleID)
+	}
+
+	resp := &pbc.CChatRoom_GetRoleActions_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetRoleActions#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	actions := make([]*ChatRoleActions, 0, len(resp.GetActions()))
+
+	for _, a := range resp.GetActions() {
+		actions = append(actions, &ChatRoleActions{
+			RoleID:                       a.GetRoleId(),
+			CanCreateRenameDeleteChannel: a.GetCanCreateRenameDeleteChannel(),
+			CanKick:                      a.GetCanKick(),
+			CanBan:                       a.GetCanBan(),
+			CanInvite:                    a.GetCanInvite(),
+			CanChangeTaglineAvatarName:   a.GetCanChangeTaglineAvatarName(),
+			CanChat:                      a.GetCanChat(),
+			CanViewHistory:               a.GetCanViewHistory(),
+			CanChangeGroupRoles:          a.GetCanChangeGroupRoles(),
+			CanChangeUserRoles:           a.GetCanChangeUserRoles(),
+			CanMentionAll:                a.GetCanMentionAll(),
+			CanSetWatchingBroadcast:      a.GetCanSetWatchingBroadcast(),
+		})
+	}
+
+	return actions, nil
+}
+
+// GetRolesForUser fetches the IDs of the roles of a member of a chat room group.
+func (r *ChatRooms) GetRolesForUser(ctx context.Context, chatGroupID uint64, user steamid.SteamID) ([]uint64, error) {
+	req := &pbc.CChatRoom_GetRolesForUser_Request{
+		ChatGroupId: proto.Uint64(chatGroupID),
+		Steamid:     proto.Uint64(user.Uint64()),
+	}
+
+	resp := &pbc.CChatRoom_GetRolesForUser_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetRolesForUser#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.GetRoleIds(), nil
+}
+
+// ChatInviteLink is an invite link of a chat room group.
+type ChatInviteLink struct {
+	Code string
+	// Zero if the link is for the group's default chat room
+	ChatID  uint64          `json:",string"`
+	Creator steamid.SteamID `json:",string"`
+	// Zero if the link never expires
+	Expires time.Time
+}
+
+// URL returns the URL of the invite link.
+func (l *ChatInviteLink) URL() string {
+	return ChatInviteLinkBaseURL + l.Code
+}
+
+// CreateInviteLink creates an invite link of a chat room group, optionally for a specific chat room.
+// The link never expires if validFor is 0.
+func (r *ChatRooms) CreateInviteLink(
+	ctx context.Context,
+	chatGroupID, chatID uint64,
+	validFor time.Duration,
+) (*ChatInviteLink, error) {
+	req := &pbc.CChatRoom_CreateInviteLink_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+
+	if chatID != 0 {
+		req.ChatId = proto.Uint64(chatID)
+	}
+
+	if validFor != 0 {
+		req.SecondsValid = proto.Uint32(uint32(validFor / time.Second))
+	}
+
+	resp := &pbc.CChatRoom_CreateInviteLink_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.CreateInviteLink#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	link := &ChatInviteLink{
+		Code:    resp.GetInviteCode(),
+		ChatID:  chatID,
+		Creator: r.client.SteamID(),
+	}
+
+	if seconds := resp.GetSecondsValid(); seconds != 0 {
+		link.Expires = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	return link, nil
+}
+
+// GetInviteLinks fetches the invite links of a chat room group.
+func (r *ChatRooms) GetInviteLinks(ctx context.Context, chatGroupID uint64) ([]*ChatInviteLink, error) {
+	req := &pbc.CChatRoom_GetInviteLinksForGroup_Request{ChatGroupId: proto.Uint64(chatGroupID)}
+	resp := &pbc.CChatRoom_GetInviteLinksForGroup_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetInviteLinksForGroup#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	links := make([]*ChatInviteLink, 0, len(resp.GetInviteLinks()))
+
+	for _, l := range resp.GetInviteLinks() {
+		links = append(links, &ChatInviteLink{
+			Code:    l.GetInviteCode(),
+			ChatID:  l.GetChatId(),
+			Creator: steamid.SteamID(l.GetSteamidCreator()),
+			Expires: unixTimeOrZero(l.GetTimeExpires()),
+		})
+	}
+
+	return links, nil
+}
+
+// ChatInviteLinkInfo describes the chat room group of an invite link.
+type ChatInviteLinkInfo struct {
+	Sender  steamid.SteamID `json:",string"`
+	Expires time.Time
+	ChatID  uint64 `json:",string"`
+	Group   *socialcache.ChatRoomGroup
+	// Whether the account is banned from the group, or kicked until KickExpires
+	Banned      bool
+	KickExpires time.Time
+}
+
+// GetInviteLinkInfo fetches the chat room group of an invite link, given its code or URL.
+func (r *ChatRooms) GetInviteLinkInfo(ctx context.Context, code string) (*ChatInviteLinkInfo, error) {
+	code = strings.TrimPrefix(code, ChatInviteLinkBaseURL)
+	req := &pbc.CChatRoom_GetInviteLinkInfo_Request{InviteCode: proto.String(code)}
+	resp := &pbc.CChatRoom_GetInviteLinkInfo_Response{}
+
+	if err := r.client.CallService(ctx, "ChatRoom.GetInviteLinkInfo#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return &ChatInviteLinkInfo{
+		Sender:      steamid.SteamID(resp.GetSteamidSender()),
+		Expires:     unixTimeOrZero(resp.GetTimeExpires()),
+		ChatID:      resp.GetChatId(),
+		Group:       newChatRoomGroup(resp.GetGroupSummary()),
+		Banned:      resp.GetBanned(),
+		KickExpires: unixTimeOrZero(resp.GetTimeKickExpire()),
+	}, nil
+}
+
+func (r *ChatRooms) HandlePacket(packet *protocol.Packet) {
+	if packet.EMsg() != steamlang.EMsg_ServiceMethod {
+		return
+	}
+
+	switch packet.TargetJobName() {
+	case "ChatRoomClient.NotifyIncomingChatMessage#1":
+		r.handleIncomingChatMessage(packet)
+	case "ChatRoomClient.NotifyMemberStateChange#1":
+		r.handleMemberStateChange(packet)
+	case "ChatRoomClient.NotifyChatRoomHeaderStateChange#1":
+		r.handleHeaderStateChange(packet)
+	case "ChatRoomClient.NotifyChatRoomGroupRoomsChange#1":
+		r.handleGroupRoomsChange(packet)
+	case "ChatRoomClient.NotifyChatGroupUserStateChanged#1":
+		r.handleGroupUserStateChanged(packet)
+	}
+}
+
+func (r *ChatRooms) handleIncomingChatMessage(packet *protocol.Packet) {
+	body := &pbc.CChatRoom_IncomingChatMessage_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		r.client.Errorf("chatrooms/IncomingChatMessage: error reading message: %v", err)
+		return
+	}
+
+	event := &ChatRoomMessageEvent{
+		ChatGroupID:     body.GetChatGroupId(),
+		ChatID:          body.GetChatId(),
+		ChatName:        body.GetChatName(),
+		Sender:          steamid.SteamID(body.GetSteamidSender()),
+		Message:         body.GetMessage(),
+		MessageNoBBCode: body.GetMessageNoBbcode(),
+		Timestamp:       time.Unix(int64(body.GetTimestamp()), 0),
+		Ordinal:         body.GetOrdinal(),
+		ServerMessage:   newChatRoomServerMessage(body.GetServerMessage()),
+	}
+
+	if mentions := body.GetMentions(); mentions != nil {
+		event.MentionAll = mentions.GetMentionAll()
+		event.MentionHere = mentions.GetMentionHere()
+
+		for _, id := range mentions.GetMentionAccountids() {
+			event.Mentions = append(event.Mentions, accountSteamID(id))
+		}
+	}
+
+	r.Groups.SetLastMessage(event.ChatGroupID, event.ChatID, event.Sender, event.Timestamp)
+	r.client.Emit(event)
+}
+
+func (r *ChatRooms) handleMemberStateChange(packet *protocol.Packet) {
+	body := &pbc.CChatRoom_MemberStateChange_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		r.client.Errorf("chatrooms/MemberStateChange: error reading message: %v", err)
+		return
+	}
+
+	member := newChatRoomMember(body.GetMember())
+	change := body.GetChange()
+
+	switch change {
+	case pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Parted,
+		pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Kicked,
+		pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Banned:
+		r.Groups.RemoveMember(body.GetChatGroupId(), member.SteamID)
+	default:
+		r.Groups.SetMember(body.GetChatGroupId(), member)
+	}
+
+	r.client.Emit(&ChatRoomMemberStateEvent{
+		ChatGroupID: body.GetChatGroupId(),
+		Member:      member,
+		Change:      change,
+	})
+}
+
+func (r *ChatRooms) handleHeaderStateChange(packet *protocol.Packet) {
+	body := &pbc.CChatRoom_ChatRoomHeaderState_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		r.client.Errorf("chatrooms/ChatRoomHeaderState: error reading message: %v", err)
+		return
+	}
+
+	state := body.GetHeaderState()
+	header := newChatRoomGroupHeader(state)
+
+	r.Groups.SetHeader(state.GetChatGroupId(), header)
+	r.client.Emit(&ChatRoomGroupHeaderEvent{ChatGroupID: state.GetChatGroupId(), Header: header})
+}
+
+func (r *ChatRooms) handleGroupRoomsChange(packet *protocol.Packet) {
+	body := &pbc.CChatRoom_ChatRoomGroupRoomsChange_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		r.client.Errorf("chatrooms/ChatRoomGroupRoomsChange: error reading message: %v", err)
+		return
+	}
+
+	rooms := newChatRooms(body.GetChatRooms())
+
+	r.Groups.SetChatRooms(body.GetChatGroupId(), body.GetDefaultChatId(), rooms)
+	r.client.Emit(&ChatRoomsChangedEvent{
+		ChatGroupID:   body.GetChatGroupId(),
+		DefaultChatID: body.GetDefaultChatId(),
+		ChatRooms:     rooms,
+	})
+}
+
+func (r *ChatRooms) handleGroupUserStateChanged(packet *protocol.Packet) {
+	body := &pbc.ChatRoomClient_NotifyChatGroupUserStateChanged_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		r.client.Errorf("chatrooms/ChatGroupUserStateChanged: error reading message: %v", err)
+		return
+	}
+
+	action := body.GetUserAction()
+
+	switch action {
+	case pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Joined:
+		if summary := body.GetGroupSummary(); summary != nil {
+			r.Groups.Set(*newChatRoomGroup(summary))
+		}
+	case pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Parted,
+		pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Kicked,
+		pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Banned:
+		r.Groups.Remove(body.GetChatGroupId())
+	}
+
+	r.client.Emit(&ChatRoomGroupUserStateEvent{ChatGroupID: body.GetChatGroupId(), Action: action})
+}
+
+func newChatRoomGroup(summary *pbc.CChatRoom_GetChatRoomGroupSummary_Response) *socialcache.ChatRoomGroup {
+	group := &socialcache.ChatRoomGroup{
+		ChatGroupID: summary.GetChatGroupId(),
+		ChatRoomGroupHeader: socialcache.ChatRoomGroupHeader{
+			Name:          summary.GetChatGroupName(),
+			Tagline:       summary.GetChatGroupTagline(),
+			ClanID:        clanSteamID(summary.GetClanid()),
+			OwnerID:       accountSteamID(summary.GetAccountidOwner()),
+			AppID:         summary.GetAppid(),
+			AvatarSHA:     summary.GetChatGroupAvatarSha(),
+			DefaultRoleID: summary.GetDefaultRoleId(),
+		},
+		DefaultChatID: summary.GetDefaultChatId(),
+		ChatRooms:     make(map[uint64]socialcache.ChatRoom),
+		Rank:          summary.GetRank(),
+		RoleIDs:       summary.GetRoleIds(),
+	}
+
+	for _, room := range newChatRooms(summary.GetChatRooms()) {
+		group.ChatRooms[room.ChatID] = room
+	}
+
+	return group
+}
+
+func applyChatRoomGroupState(group *socialcache.ChatRoomGroup, state *pbc.CChatRoomGroupState) {
+	if header := state.GetHeaderState(); header != nil {
+		group.ChatRoomGroupHeader = newChatRoomGroupHeader(header)
+	}
+
+	group.DefaultChatID = state.GetDefaultChatId()
+	group.ChatRooms = make(map[uint64]socialcache.ChatRoom)
+
+	for _, room := range newChatRooms(state.GetChatRooms()) {
+		group.ChatRooms[room.ChatID] = room
+	}
+
+	group.Members = make(map[steamid.SteamID]socialcache.ChatRoomMember)
+
+	for _, m := range state.GetMembers() {
+		member := newChatRoomMember(m)
+		group.Members[member.SteamID] = member
+	}
+}
+
+func newChatRoomGroupHeader(state *pbc.CChatRoomGroupHeaderState) socialcache.ChatRoomGroupHeader {
+	return socialcache.ChatRoomGroupHeader{
+		Name:          state.GetChatName(),
+		Tagline:       state.GetTagline(),
+		ClanID:        clanSteamID(state.GetClanid()),
+		OwnerID:       accountSteamID(state.GetAccountidOwner()),
+		AppID:         state.GetAppid(),
+		AvatarSHA:     state.GetAvatarSha(),
+		DefaultRoleID: state.GetDefaultRoleId(),
+		Roles:         newChatRoles(state.GetRoles()),
+	}
+}
+
+func newChatRooms(states []*pbc.CChatRoomState) []socialcache.ChatRoom {
+	rooms := make([]socialcache.ChatRoom, 0, len(states))
+
+	for _, s := range states {
+		rooms = append(rooms, socialcache.ChatRoom{
+			ChatID:            s.GetChatId(),
+			Name:              s.GetChatName(),
+			VoiceAllowed:      s.GetVoiceAllowed(),
+			SortOrder:         s.GetSortOrder(),
+			TimeLastMessage:   unixTimeOrZero(s.GetTimeLastMessage()),
+			LastMessageSender: accountSteamID(s.GetAccountidLastMessage()),
+		})
+	}
+
+	return rooms
+}
+
+func newChatRoomMember(m *pbc.CChatRoomMember) socialcache.ChatRoomMember {
+	return socialcache.ChatRoomMember{
+		SteamID:     accountSteamID(m.GetAccountid()),
+		State:       m.GetState(),
+		Rank:        m.GetRank(),
+		RoleIDs:     m.GetRoleIds(),
+		KickExpires: unixTimeOrZero(m.GetTimeKickExpire()),
+	}
+}
+
+func newChatRoles(roles []*pbc.CChatRole) []socialcache.ChatRole {
+	result := make([]socialcache.ChatRole, 0, len(roles))
+
+	for _, role := range roles {
+		result = append(result, socialcache.ChatRole{
+			RoleID:  role.GetRoleId(),
+			Name:    role.GetName(),
+			Ordinal: role.GetOrdinal(),
+		})
+	}
+
+	return result
+}
+
+// accountSteamID returns the individual SteamID of an account ID, or zero.
+func accountSteamID(accountID uint32) steamid.SteamID {
+	if accountID == 0 {
+		return 0
+	}
+
+	return steamid.AccountID(accountID).SteamID()
+}
+
+// clanSteamID returns the SteamID of a clan account ID, or zero.
+func clanSteamID(clanID uint32) steamid.SteamID {
+	if clanID == 0 {
+		return 0
+	}
+
+	return steamid.New(steamlang.EAccountType_Clan, steamlang.EUniverse_Public, steamid.AccountID(clanID), 0)
+}
+
+func unixTimeOrZero(t uint32) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t), 0)
+}
@@ -0,0 +1,64 @@
+package steam
+
+import (
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+
+	"github.com/13k/go-steam/bbcode"
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+// Fired when a message is sent to a chat room of a chat room group the account is a member of,
+// including the messages sent by the account itself.
+type ChatRoomMessageEvent struct {
+	ChatGroupID uint64 `json:",string"`
+	ChatID      uint64 `json:",string"`
+	ChatName    string
+	Sender      steamid.SteamID `json:",string"`
+	// BBCode formatted message
+	Message         string
+	MessageNoBBCode string
+	Timestamp       time.Time
+	// Orders messages with the same Timestamp
+	Ordinal uint32
+	// Set for messages generated by Steam, like members joining
+	ServerMessage *ChatRoomServerMessage
+	MentionAll    bool
+	MentionHere   bool
+	Mentions      []steamid.SteamID
+}
+
+// Parse parses the BBCode of the message.
+func (e *ChatRoomMessageEvent) Parse() []*bbcode.Node {
+	return bbcode.Parse(e.Message)
+}
+
+// Fired when a member joins, leaves or changes state in a chat room group the account is a member
+// of.
+type ChatRoomMemberStateEvent struct {
+	ChatGroupID uint64 `json:",string"`
+	Member      socialcache.ChatRoomMember
+	Change      pbc.EChatRoomMemberStateChange
+}
+
+// Fired when the name, tagline, avatar or roles of a chat room group change.
+type ChatRoomGroupHeaderEvent struct {
+	ChatGroupID uint64 `json:",string"`
+	Header      socialcache.ChatRoomGroupHeader
+}
+
+// Fired when chat rooms are created, renamed, reordered or deleted in a chat room group.
+type ChatRoomsChangedEvent struct {
+	ChatGroupID   uint64 `json:",string"`
+	DefaultChatID uint64 `json:",string"`
+	ChatRooms     []socialcache.ChatRoom
+}
+
+// Fired when the account joins, leaves or is invited to, kicked or banned from a chat room group,
+// from this or another session.
+type ChatRoomGroupUserStateEvent struct {
+	ChatGroupID uint64 `json:",string"`
+	Action      pbc.EChatRoomMemberStateChange
+}
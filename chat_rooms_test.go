@@ -0,0 +1,230 @@
+package steam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+)
+
+const testChatGroupID = 1000
+
+func testChatRoomGroupSummary() *pbc.CChatRoom_GetChatRoomGroupSummary_Response {
+	return &pbc.CChatRoom_GetChatRoomGroupSummary_Response{
+		ChatGroupId:    proto.Uint64(testChatGroupID),
+		ChatGroupName:  proto.String("Group"),
+		DefaultChatId:  proto.Uint64(1),
+		AccountidOwner: proto.Uint32(uint32(testFriendID.AccountID())),
+		ChatRooms: []*pbc.CChatRoomState{
+			{ChatId: proto.Uint64(1), ChatName: proto.String("General")},
+		},
+	}
+}
+
+func TestChatRoomsGetMyChatRoomGroups(t *testing.T) {
+	client := newTestClient(t)
+	client.ChatRooms.Groups.Set(*newChatRoomGroup(&pbc.CChatRoom_GetChatRoomGroupSummary_Response{
+		ChatGroupId: proto.Uint64(2000),
+	}))
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := client.ChatRooms.GetMyChatRoomGroups(context.Background())
+		done <- err
+	}()
+
+	packet := client.next()
+
+	if name := packet.TargetJobName(); name != "ChatRoom.GetMyChatRoomGroups#1" {
+		t.Fatalf("unexpected target job name %q", name)
+	}
+
+	client.respond(packet, steamlang.EResult_OK, &pbc.CChatRoom_GetMyChatRoomGroups_Response{
+		ChatRoomGroups: []*pbc.CChatRoomSummaryPair{{GroupSummary: testChatRoomGroupSummary()}},
+	})
+
+	if err := <-done; err != nil {
+		t.Fatalf("GetMyChatRoomGroups: %v", err)
+	}
+
+	if n := client.ChatRooms.Groups.Count(); n != 1 {
+		t.Fatalf("expected the cache to be replaced, got %d groups", n)
+	}
+
+	group, err := client.ChatRooms.Groups.ByID(testChatGroupID)
+
+	if err != nil {
+		t.Fatalf("ByID: %v", err)
+	}
+
+	if group.Name != "Group" || group.OwnerID != testFriendID || group.ChatRooms[1].Name != "General" {
+		t.Errorf("unexpected group %+v", group)
+	}
+}
+
+func TestChatRoomsJoinAndLeave(t *testing.T) {
+	client := newTestClient(t)
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := client.ChatRooms.JoinChatRoomGroup(context.Background(), testChatGroupID, "code")
+		done <- err
+	}()
+
+	packet := client.next()
+
+	client.respond(packet, steamlang.EResult_OK, &pbc.CChatRoom_JoinChatRoomGroup_Response{
+		State: &pbc.CChatRoomGroupState{
+			HeaderState: &pbc.CChatRoomGroupHeaderState{ChatName: proto.String("Group")},
+			Members:     []*pbc.CChatRoomMember{{Accountid: proto.Uint32(uint32(testFriendID.AccountID()))}},
+		},
+	})
+
+	if err := <-done; err != nil {
+		t.Fatalf("JoinChatRoomGroup: %v", err)
+	}
+
+	group, err := client.ChatRooms.Groups.ByID(testChatGroupID)
+
+	if err != nil || group.Name != "Group" || len(group.Members) != 1 {
+		t.Fatalf("unexpected cached group %+v, %v", group, err)
+	}
+
+	go func() {
+		done <- client.ChatRooms.LeaveChatRoomGroup(context.Background(), testChatGroupID)
+	}()
+
+	client.respond(client.next(), steamlang.EResult_OK, &pbc.CChatRoom_LeaveChatRoomGroup_Response{})
+
+	if err := <-done; err != nil {
+		t.Fatalf("LeaveChatRoomGroup: %v", err)
+	}
+
+	if _, err := client.ChatRooms.Groups.ByID(testChatGroupID); err == nil {
+		t.Error("expected left group to be removed from the cache")
+	}
+}
+
+func TestChatRoomsNotifications(t *testing.T) {
+	client := newTestClient(t)
+	client.ChatRooms.Groups.Set(*newChatRoomGroup(testChatRoomGroupSummary()))
+
+	accountID := uint32(testFriendID.AccountID())
+
+	client.notify("ChatRoomClient.NotifyIncomingChatMessage#1", &pbc.CChatRoom_IncomingChatMessage_Notification{
+		ChatGroupId:   proto.Uint64(testChatGroupID),
+		ChatId:        proto.Uint64(1),
+		SteamidSender: proto.Uint64(testFriendID.Uint64()),
+		Message:       proto.String("hi"),
+		Timestamp:     proto.Uint32(1600000000),
+		Mentions:      &pbc.CChatMentions{MentionAccountids: []uint32{accountID}},
+	})
+
+	message, ok := client.event().(*ChatRoomMessageEvent)
+
+	if !ok || message.Sender != testFriendID || message.Message != "hi" ||
+		len(message.Mentions) != 1 || message.Mentions[0] != testFriendID {
+		t.Fatalf("unexpected message event %+v", message)
+	}
+
+	group, _ := client.ChatRooms.Groups.ByID(testChatGroupID)
+
+	if room := group.ChatRooms[1]; !room.TimeLastMessage.Equal(time.Unix(1600000000, 0)) ||
+		room.LastMessageSender != testFriendID {
+		t.Errorf("expected last message to be cached, got %+v", room)
+	}
+
+	memberChange := func(change pbc.EChatRoomMemberStateChange) {
+		client.notify("ChatRoomClient.NotifyMemberStateChange#1", &pbc.CChatRoom_MemberStateChange_Notification{
+			ChatGroupId: proto.Uint64(testChatGroupID),
+			Member:      &pbc.CChatRoomMember{Accountid: proto.Uint32(accountID)},
+			Change:      change.Enum(),
+		})
+
+		if event, ok := client.event().(*ChatRoomMemberStateEvent); !ok || event.Member.SteamID != testFriendID ||
+			event.Change != change {
+			t.Fatalf("unexpected member state event %+v", event)
+		}
+	}
+
+	memberChange(pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Joined)
+
+	if group, _ := client.ChatRooms.Groups.ByID(testChatGroupID); len(group.Members) != 1 {
+		t.Errorf("expected joined member to be cached, got %+v", group.Members)
+	}
+
+	memberChange(pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Kicked)
+
+	if group, _ := client.ChatRooms.Groups.ByID(testChatGroupID); len(group.Members) != 0 {
+		t.Errorf("expected kicked member to be removed, got %+v", group.Members)
+	}
+
+	client.notify("ChatRoomClient.NotifyChatRoomHeaderStateChange#1", &pbc.CChatRoom_ChatRoomHeaderState_Notification{
+		HeaderState: &pbc.CChatRoomGroupHeaderState{
+			ChatGroupId: proto.Uint64(testChatGroupID),
+			ChatName:    proto.String("Renamed"),
+			Roles:       []*pbc.CChatRole{{RoleId: proto.Uint64(5), Name: proto.String("Admin")}},
+		},
+	})
+
+	if event, ok := client.event().(*ChatRoomGroupHeaderEvent); !ok || event.Header.Name != "Renamed" {
+		t.Fatalf("unexpected header event %+v", event)
+	}
+
+	if group, _ := client.ChatRooms.Groups.ByID(testChatGroupID); group.Name != "Renamed" ||
+		len(group.Roles) != 1 || group.Roles[0].Name != "Admin" {
+		t.Errorf("expected header to be cached, got %+v", group.ChatRoomGroupHeader)
+	}
+
+	client.notify("ChatRoomClient.NotifyChatRoomGroupRoomsChange#1", &pbc.CChatRoom_ChatRoomGroupRoomsChange_Notification{
+		ChatGroupId:   proto.Uint64(testChatGroupID),
+		DefaultChatId: proto.Uint64(2),
+		ChatRooms: []*pbc.CChatRoomState{
+			{ChatId: proto.Uint64(2), ChatName: proto.String("Lobby")},
+			{ChatId: proto.Uint64(3), ChatName: proto.String("Trading")},
+		},
+	})
+
+	if event, ok := client.event().(*ChatRoomsChangedEvent); !ok || event.DefaultChatID != 2 || len(event.ChatRooms) != 2 {
+		t.Fatalf("unexpected rooms event %+v", event)
+	}
+
+	if group, _ := client.ChatRooms.Groups.ByID(testChatGroupID); group.DefaultChatID != 2 ||
+		len(group.ChatRooms) != 2 || group.ChatRooms[3].Name != "Trading" {
+		t.Errorf("expected rooms to be cached, got %+v", group.ChatRooms)
+	}
+}
+
+func TestChatRoomsUserStateChanged(t *testing.T) {
+	client := newTestClient(t)
+
+	userState := func(action pbc.EChatRoomMemberStateChange) {
+		client.notify("ChatRoomClient.NotifyChatGroupUserStateChanged#1",
+			&pbc.ChatRoomClient_NotifyChatGroupUserStateChanged_Notification{
+				ChatGroupId:  proto.Uint64(testChatGroupID),
+				GroupSummary: testChatRoomGroupSummary(),
+				UserAction:   action.Enum(),
+			})
+
+		if event, ok := client.event().(*ChatRoomGroupUserStateEvent); !ok || event.ChatGroupID != testChatGroupID ||
+			event.Action != action {
+			t.Fatalf("unexpected user state event %+v", event)
+		}
+	}
+
+	userState(pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Joined)
+
+	if group, err := client.ChatRooms.Groups.ByID(testChatGroupID); err != nil || group.Name != "Group" {
+		t.Fatalf("expected joined group to be cached, got %+v, %v", group, err)
+	}
+
+	userState(pbc.EChatRoomMemberStateChange_k_EChatRoomMemberStateChange_Parted)
+
+	if n := client.ChatRooms.Groups.Count(); n != 0 {
+		t.Errorf("expected left group to be removed, got %d groups", n)
+	}
+}
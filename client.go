@@ -37,16 +37,22 @@ type Client struct {
 	steamID      uint64
 	currentJobID uint64
 
-	Auth          *Auth
-	Social        *Social
-	Web           *Web
-	Notifications *Notifications
-	Trading       *Trading
-	GC            *GameCoordinator
+	Auth           *Auth
+	Social         *Social
+	Web            *Web
+	Notifications  *Notifications
+	Trading        *Trading
+	GC             *GameCoordinator
+	Store          *Store
+	FriendMessages *FriendMessages
+	ChatRooms      *ChatRooms
+	Personas       *Personas
+	Avatars        *Avatars
 
 	events      chan interface{}
 	handlers    []protocol.PacketHandler
 	handlersMtx sync.RWMutex
+	jobs        *jobs
 
 	tempSessionKey []byte
 
@@ -61,6 +67,7 @@ func NewClient() *Client {
 	client := &Client{
 		events:   make(chan interface{}, 30),
 		writeBuf: &bytes.Buffer{},
+		jobs:     newJobs(),
 	}
 
 	client.Auth = NewAuth(client)
@@ -69,6 +76,11 @@ func NewClient() *Client {
 	client.Notifications = NewNotifications(client)
 	client.Trading = NewTrading(client)
 	client.GC = NewGC(client)
+	client.Store = NewStore(client)
+	client.FriendMessages = NewFriendMessages(client)
+	client.ChatRooms = NewChatRooms(client)
+	client.Personas = NewPersonas(client)
+	client.Avatars = NewAvatars()
 
 	client.RegisterPacketHandler(client.Auth)
 	client.RegisterPacketHandler(client.Social)
@@ -76,6 +88,9 @@ func NewClient() *Client {
 	client.RegisterPacketHandler(client.Notifications)
 	client.RegisterPacketHandler(client.Trading)
 	client.RegisterPacketHandler(client.GC)
+	client.RegisterPacketHandler(client.Store)
+	client.RegisterPacketHandler(client.FriendMessages)
+	client.RegisterPacketHandler(client.ChatRooms)
 
 	return client
 }
@@ -215,6 +230,9 @@ func (c *Client) Disconnect() {
 
 	close(c.writeChan)
 
+	c.jobs.cancelAll()
+	c.Store.reset()
+
 	c.Emit(&DisconnectedEvent{})
 }
 
@@ -330,6 +348,8 @@ func (c *Client) handlePacket(packet *protocol.Packet) {
 		c.handleMulti(packet)
 	case steamlang.EMsg_ClientCMList:
 		c.handleClientCMList(packet)
+	case steamlang.EMsg_ServiceMethodResponse:
+		c.handleServiceMethodResponse(packet)
 	}
 
 	c.handlersMtx.RLock()
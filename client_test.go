@@ -0,0 +1,172 @@
+package steam
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+// fakeConnection hands the messages written by the client to the test. Nothing is ever read from
+// it, tests feed the packets to be handled to Client.handlePacket instead.
+type fakeConnection struct {
+	written   chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ connection = (*fakeConnection)(nil)
+
+func (c *fakeConnection) Write(data []byte) (int, error) {
+	c.written <- append([]byte(nil), data...)
+	return len(data), nil
+}
+
+func (c *fakeConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakeConnection) Read() (*protocol.Packet, error) {
+	<-c.closed
+	return nil, io.EOF
+}
+
+func (c *fakeConnection) SetEncryptionKey([]byte) error { return nil }
+func (c *fakeConnection) IsEncrypted() bool             { return true }
+
+// testClient is a connected Client whose written messages are received with next.
+type testClient struct {
+	*Client
+
+	t    *testing.T
+	conn *fakeConnection
+}
+
+func newTestClient(t *testing.T) *testClient {
+	client := NewClient()
+	conn := &fakeConnection{written: make(chan []byte, 10), closed: make(chan struct{})}
+
+	client.conn = conn
+	client.writeChan = make(chan protocol.Message, 5)
+
+	go client.writeLoop()
+
+	t.Cleanup(func() {
+		done := make(chan struct{})
+		defer close(done)
+
+		// Disconnect emits an event
+		go func() {
+			for {
+				select {
+				case <-client.Events():
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		client.Disconnect()
+	})
+
+	return &testClient{Client: client, t: t, conn: conn}
+}
+
+// next returns the next message written by the client as a packet.
+func (c *testClient) next() *protocol.Packet {
+	c.t.Helper()
+
+	select {
+	case data := <-c.conn.written:
+		packet, err := protocol.NewPacket(data)
+
+		if err != nil {
+			c.t.Fatalf("NewPacket: %v", err)
+		}
+
+		return packet
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timeout waiting for a written message")
+		return nil
+	}
+}
+
+// handle makes the client handle a protobuf message sent in response to the given job.
+func (c *testClient) handle(emsg steamlang.EMsg, body proto.Message, target protocol.JobID) {
+	c.t.Helper()
+
+	msg := protocol.NewProtoMessage(emsg, body)
+	msg.SetTargetJobID(target)
+
+	c.handleMessage(msg)
+}
+
+// notify makes the client handle a service method notification.
+func (c *testClient) notify(method string, body proto.Message) {
+	c.t.Helper()
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ServiceMethod, body)
+	msg.Header.SetTargetJobName(method)
+
+	c.handleMessage(msg)
+}
+
+// respond makes the client handle the response of the service method call written in packet.
+func (c *testClient) respond(packet *protocol.Packet, result steamlang.EResult, body proto.Message) {
+	c.t.Helper()
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ServiceMethodResponse, body)
+	msg.SetTargetJobID(packet.SourceJobID())
+	msg.Header.MsgHdrProtoBuf.Proto.Eresult = proto.Int32(int32(result))
+
+	c.handleMessage(msg)
+}
+
+func (c *testClient) handleMessage(msg protocol.Message) {
+	c.t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	if err := msg.Serialize(buf); err != nil {
+		c.t.Fatalf("Serialize: %v", err)
+	}
+
+	packet, err := protocol.NewPacket(buf.Bytes())
+
+	if err != nil {
+		c.t.Fatalf("NewPacket: %v", err)
+	}
+
+	c.handlePacket(packet)
+}
+
+// event returns the next emitted event.
+func (c *testClient) event() interface{} {
+	c.t.Helper()
+
+	select {
+	case event := <-c.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timeout waiting for an event")
+		return nil
+	}
+}
+
+// noEvent fails if an event is emitted in the next few milliseconds.
+func (c *testClient) noEvent() {
+	c.t.Helper()
+
+	select {
+	case event := <-c.Events():
+		c.t.Fatalf("unexpected event %#v", event)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,247 @@
+// Package community implements a steamcommunity.com client, authenticated with the cookies of a
+// web session.
+package community
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/13k/go-steam/netutil"
+	"github.com/13k/go-steam/steamid"
+)
+
+const baseURL = "https://steamcommunity.com"
+
+// Client is a steamcommunity.com client authenticated with the cookies of a web session.
+//
+// The cookies can be obtained from steam.Web after receiving a steam.WebLoggedOnEvent.
+type Client struct {
+	client    *http.Client
+	steamID   steamid.SteamID
+	sessionID string
+	baseURL   string
+}
+
+// NewClient creates a new Client for the user with the given SteamID, authenticated with the
+// `sessionid`, `steamLogin` and `steamLoginSecure` cookies.
+func NewClient(steamID steamid.SteamID, sessionID, steamLogin, steamLoginSecure string) (*Client, error) {
+	c := &Client{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		steamID:   steamID,
+		sessionID: sessionID,
+		baseURL:   baseURL,
+	}
+
+	if err := SetCookies(c.client, sessionID, steamLogin, steamLoginSecure); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SteamID returns the SteamID of the authenticated user.
+func (c *Client) SteamID() steamid.SteamID {
+	return c.steamID
+}
+
+// HTTPClient returns the underlying http.Client, which holds the session cookies.
+func (c *Client) HTTPClient() *http.Client {
+	return c.client
+}
+
+func (c *Client) profileURL(id steamid.SteamID) string {
+	return c.baseURL + "/profiles/" + id.FormatString()
+}
+
+func (c *Client) groupURL(id steamid.SteamID) string {
+	return c.baseURL + "/gid/" + id.FormatString()
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("community: %s %s: status code %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+func (c *Client) get(u string) ([]byte, error) {
+	req, err := http.NewRequest("GET", u, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+// post sends a form, always including the `sessionid` parameter for CSRF protection. The session
+// ID parameter is named differently by some endpoints, hence sessionParam.
+func (c *Client) post(u, referer, sessionParam string, data map[string]string) ([]byte, error) {
+	values := netutil.ToURLValues(data)
+	values.Set(sessionParam, c.sessionID)
+
+	return c.postValues(u, referer, values)
+}
+
+// postValues is like post, but for forms with repeated keys. The session ID must already be set.
+func (c *Client) postValues(u, referer string, values url.Values) ([]byte, error) {
+	req, err := newPostForm(u, referer, values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+var formErrorRE = regexp.MustCompile(`<div[^>]+class="[^"]*error[^"]*"[^>]*>\s*([^<]*?)\s*<`)
+
+// postRedirect sends a form to one of the endpoints that redirect to a result page on success and
+// render the form again, with an error message, on failure.
+func (c *Client) postRedirect(u, referer, sessionParam string, data map[string]string) error {
+	values := netutil.ToURLValues(data)
+	values.Set(sessionParam, c.sessionID)
+
+	req, err := newPostForm(u, referer, values)
+
+	if err != nil {
+		return err
+	}
+
+	return c.doRedirect(req)
+}
+
+// doRedirect sends a request to an endpoint that redirects on success. A response that isn't a
+// redirect is an error, described by the page's error message if it has one.
+func (c *Client) doRedirect(req *http.Request) error {
+	client := *c.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		if strings.Contains(resp.Header.Get("Location"), "/login") {
+			return fmt.Errorf("community: %s %s: not logged in", req.Method, req.URL.Path)
+		}
+
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("community: %s %s: status code %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	if m := formErrorRE.FindSubmatch(body); m != nil && len(m[1]) > 0 {
+		return fmt.Errorf("community: %s %s: %s", req.Method, req.URL.Path, html.UnescapeString(string(m[1])))
+	}
+
+	return fmt.Errorf("community: %s %s: form was not accepted", req.Method, req.URL.Path)
+}
+
+func (c *Client) postMultipart(u, referer, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest("POST", u, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	if referer != "" {
+		req.Header.Add("Referer", referer)
+	}
+
+	return c.do(req)
+}
+
+// getXML fetches one of the `?xml=1` pages, which report errors with a `<response><error>` document
+// and a 200 status code.
+func (c *Client) getXML(u string, v interface{}) error {
+	body, err := c.get(u)
+
+	if err != nil {
+		return err
+	}
+
+	xmlErr := &struct {
+		XMLName xml.Name
+		Error   string `xml:"error"`
+	}{}
+
+	if err := xml.Unmarshal(body, xmlErr); err != nil {
+		return fmt.Errorf("community: invalid XML response from %s: %v", u, err)
+	}
+
+	if xmlErr.XMLName.Local == "response" && xmlErr.Error != "" {
+		return fmt.Errorf("community: %s", strings.TrimSpace(xmlErr.Error))
+	}
+
+	return xml.Unmarshal(body, v)
+}
+
+func newPostForm(u, referer string, values url.Values) (*http.Request, error) {
+	req, err := netutil.NewPostForm(u, values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if referer != "" {
+		req.Header.Add("Referer", referer)
+	}
+
+	return req, nil
+}
+
+func decodeJSON(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("community: invalid JSON response: %v", err)
+	}
+
+	return nil
+}
+
+func formatQuery(u string, query url.Values) string {
+	if len(query) == 0 {
+		return u
+	}
+
+	return u + "?" + query.Encode()
+}
@@ -0,0 +1,27 @@
+package community
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const testSteamID steamid.SteamID = 76561197960287930
+
+// newTestClient returns a Client that sends its requests to a test server running handler.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(testSteamID, "session", "login", "secure")
+
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	client.baseURL = server.URL
+
+	return client
+}
@@ -0,0 +1,126 @@
+package community
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// Comment is a comment of a profile or group comment thread.
+type Comment struct {
+	ID         uint64
+	Author     steamid.SteamID
+	AuthorName string
+	Time       time.Time
+	// HTML
+	Text string
+}
+
+// CommentsPage is a page of a comment thread, newest first.
+type CommentsPage struct {
+	Start      uint32
+	TotalCount uint32
+	Comments   []*Comment
+}
+
+var (
+	commentSplitRE  = regexp.MustCompile(`<div class="commentthread_comment[^"]*" id="comment_(\d+)"`)
+	commentAuthorRE = regexp.MustCompile(
+		`commentthread_author_link"[^>]*data-miniprofile="(\d+)"[^>]*>\s*(?:<bdi>)?(.*?)(?:</bdi>)?\s*</a>`,
+	)
+	commentTimeRE    = regexp.MustCompile(`data-timestamp="(\d+)"`)
+	commentContentRE = regexp.MustCompile(`(?s)id="comment_content_\d+">\s*(.*?)\s*</div>`)
+)
+
+func (c *Client) getComments(
+	kind string,
+	id steamid.SteamID,
+	referer string,
+	start, count uint32,
+) (*CommentsPage, error) {
+	u := fmt.Sprintf("%s/comment/%s/render/%s/-1/", c.baseURL, kind, id.FormatString())
+
+	body, err := c.post(u, referer, "sessionid", map[string]string{
+		"start": strconv.FormatUint(uint64(start), 10),
+		"count": strconv.FormatUint(uint64(count), 10),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeCommentResult(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := parseComments(result.CommentsHTML)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentsPage{
+		Start:      result.Start,
+		TotalCount: result.TotalCount,
+		Comments:   comments,
+	}, nil
+}
+
+func parseComments(data string) ([]*Comment, error) {
+	locs := commentSplitRE.FindAllStringSubmatchIndex(data, -1)
+	comments := make([]*Comment, 0, len(locs))
+
+	for i, loc := range locs {
+		end := len(data)
+
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+
+		chunk := data[loc[1]:end]
+
+		id, err := strconv.ParseUint(data[loc[2]:loc[3]], 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("community: invalid comment ID: %v", err)
+		}
+
+		comment := &Comment{ID: id}
+
+		if m := commentAuthorRE.FindStringSubmatch(chunk); m != nil {
+			accountID, err := strconv.ParseUint(m[1], 10, 32)
+
+			if err != nil {
+				return nil, fmt.Errorf("community: invalid comment author: %v", err)
+			}
+
+			comment.Author = steamid.AccountID(accountID).SteamID()
+			comment.AuthorName = html.UnescapeString(strings.TrimSpace(m[2]))
+		}
+
+		if m := commentTimeRE.FindStringSubmatch(chunk); m != nil {
+			ts, err := strconv.ParseInt(m[1], 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("community: invalid comment timestamp: %v", err)
+			}
+
+			comment.Time = time.Unix(ts, 0)
+		}
+
+		if m := commentContentRE.FindStringSubmatch(chunk); m != nil {
+			comment.Text = m[1]
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
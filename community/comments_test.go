@@ -0,0 +1,112 @@
+package community
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Rendered comment thread, as found in `comments_html`.
+//
+//nolint:lll
+const commentsHTML = `<div class="commentthread_comments">
+	<div class="commentthread_comment responsive_body_text   " id="comment_1234567890123456789" style="">
+		<div class="commentthread_comment_avatar playerAvatar online">
+			<a href="https://steamcommunity.com/id/rabscuttle" data-miniprofile="22202">
+				<img src="https://avatars.steamstatic.com/abc.jpg" srcset="https://avatars.steamstatic.com/abc.jpg 1x">
+			</a>
+		</div>
+		<div class="commentthread_comment_content">
+			<div class="commentthread_comment_author">
+				<a class="hoverunderline commentthread_author_link" href="https://steamcommunity.com/id/rabscuttle" data-miniprofile="22202">
+					<bdi>Rabscuttle &amp; Co</bdi></a>
+				<span class="commentthread_comment_timestamp" title="17 May, 2020 @ 3:30:00 pm" data-timestamp="1589729400">
+					17 May, 2020 @ 3:30pm</span>
+			</div>
+			<div class="commentthread_comment_text" id="comment_content_1234567890123456789">
+				Hello <b>world</b>
+			</div>
+		</div>
+	</div>
+	<div class="commentthread_comment responsive_body_text   " id="comment_1234567890123456788" style="">
+		<div class="commentthread_comment_content">
+			<div class="commentthread_comment_author">
+				<a class="hoverunderline commentthread_author_link" href="https://steamcommunity.com/profiles/76561197960287931" data-miniprofile="1">
+					Someone</a>
+				<span class="commentthread_comment_timestamp" data-timestamp="1589729000">17 May, 2020 @ 3:23pm</span>
+			</div>
+			<div class="commentthread_comment_text" id="comment_content_1234567890123456788">
+				First
+			</div>
+		</div>
+	</div>
+</div>`
+
+func TestParseComments(t *testing.T) {
+	comments, err := parseComments(commentsHTML)
+
+	if err != nil {
+		t.Fatalf("parseComments: %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+
+	first := comments[0]
+
+	if first.ID != 1234567890123456789 || first.Author != testSteamID || first.AuthorName != "Rabscuttle & Co" {
+		t.Errorf("unexpected comment %+v", first)
+	}
+
+	if !first.Time.Equal(time.Unix(1589729400, 0)) || first.Text != "Hello <b>world</b>" {
+		t.Errorf("unexpected comment %+v", first)
+	}
+
+	second := comments[1]
+
+	if second.ID != 1234567890123456788 || second.Author != 76561197960265729 || second.AuthorName != "Someone" {
+		t.Errorf("unexpected comment %+v", second)
+	}
+
+	if second.Text != "First" {
+		t.Errorf("unexpected comment text %q", second.Text)
+	}
+
+	if comments, err := parseComments(""); err != nil || len(comments) != 0 {
+		t.Errorf("expected no comments, got %v, %v", comments, err)
+	}
+}
+
+func TestGetGroupComments(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/comment/Clan/render/103582791429521412/-1/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("start") != "10" || r.PostForm.Get("sessionid") != "session" {
+			_, _ = w.Write([]byte(`{"success": false, "error": "bad request"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"success": true, "start": 10, "total_count": 12, "comments_html": ` +
+			`"<div class=\"commentthread_comment\" id=\"comment_5\"><div id=\"comment_content_5\">Hi</div>"}`))
+	}))
+
+	page, err := client.GetGroupComments(testGroupID, 10, 2)
+
+	if err != nil {
+		t.Fatalf("GetGroupComments: %v", err)
+	}
+
+	if page.Start != 10 || page.TotalCount != 12 || len(page.Comments) != 1 || page.Comments[0].Text != "Hi" {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	_, err = client.GetGroupComments(testGroupID, 0, 2)
+
+	if err == nil || err.Error() != "community: comment error: bad request" {
+		t.Errorf("expected comment error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package community
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/13k/go-steam-resources/steamlang"
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+// GroupDetails is the summary of a group as included in its member list.
+type GroupDetails struct {
+	Name          string `xml:"groupName"`
+	URL           string `xml:"groupURL"`
+	Headline      string `xml:"headline"`
+	Summary       string `xml:"summary"`
+	AvatarIcon    string `xml:"avatarIcon"`
+	AvatarMedium  string `xml:"avatarMedium"`
+	AvatarFull    string `xml:"avatarFull"`
+	MemberCount   uint32 `xml:"memberCount"`
+	MembersInChat uint32 `xml:"membersInChat"`
+	MembersInGame uint32 `xml:"membersInGame"`
+	MembersOnline uint32 `xml:"membersOnline"`
+}
+
+// GroupMembersPage is a page of a group member list.
+type GroupMembersPage struct {
+	GroupID      steamid.SteamID   `xml:"groupID64"`
+	Details      GroupDetails      `xml:"groupDetails"`
+	MemberCount  uint32            `xml:"memberCount"`
+	TotalPages   uint32            `xml:"totalPages"`
+	CurrentPage  uint32            `xml:"currentPage"`
+	NextPageLink string            `xml:"nextPageLink"`
+	Members      []steamid.SteamID `xml:"members>steamID64"`
+}
+
+// HasNextPage returns whether there are more pages after this one.
+func (p *GroupMembersPage) HasNextPage() bool {
+	return p.NextPageLink != "" || p.CurrentPage < p.TotalPages
+}
+
+// GetGroupMembers fetches a page of the member list of the given group. Pages start at 1.
+func (c *Client) GetGroupMembers(group steamid.SteamID, page uint32) (*GroupMembersPage, error) {
+	query := url.Values{"xml": {"1"}}
+
+	if page > 1 {
+		query.Set("p", strconv.FormatUint(uint64(page), 10))
+	}
+
+	result := &GroupMembersPage{}
+
+	if err := c.getXML(formatQuery(c.groupURL(group)+"/memberslistxml/", query), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetAllGroupMembers fetches all pages of the member list of the given group.
+func (c *Client) GetAllGroupMembers(group steamid.SteamID) ([]steamid.SteamID, error) {
+	var members []steamid.SteamID
+
+	for page := uint32(1); ; page++ {
+		result, err := c.GetGroupMembers(group, page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, result.Members...)
+
+		if !result.HasNextPage() || len(result.Members) == 0 {
+			break
+		}
+	}
+
+	return members, nil
+}
+
+// MemberGroups returns the clan SteamIDs of the groups the user is a member of, as tracked by the
+// socialcache.GroupsList of a logged on steam.Client (`client.Social.Groups`), sorted.
+func MemberGroups(groups *socialcache.GroupsList) []steamid.SteamID {
+	var ids []steamid.SteamID
+
+	for id, group := range groups.GetCopy() {
+		if group.Relationship == steamlang.EClanRelationship_Member {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
@@ -0,0 +1,224 @@
+package community
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+	"github.com/13k/go-steam/steamid"
+)
+
+// The functions in this file require the authenticated user to be an officer or moderator of the
+// group. Groups are identified by their clan SteamID, see MemberGroups.
+
+// PostAnnouncement posts an announcement to the given group.
+func (c *Client) PostAnnouncement(group steamid.SteamID, headline, body string) error {
+	return c.saveAnnouncement(group, map[string]string{
+		"action": "post",
+	}, headline, body)
+}
+
+// EditAnnouncement edits an announcement of the given group.
+//
+// Announcement IDs are also found in `ClanEventDetails.ID` of the announcements of a
+// steam.ClanStateEvent.
+func (c *Client) EditAnnouncement(group steamid.SteamID, announcementID uint64, headline, body string) error {
+	return c.saveAnnouncement(group, map[string]string{
+		"action": "update",
+		"gid":    strconv.FormatUint(announcementID, 10),
+	}, headline, body)
+}
+
+func (c *Client) saveAnnouncement(group steamid.SteamID, data map[string]string, headline, body string) error {
+	data["headline"] = headline
+	data["body"] = body
+	data["languages[0][headline]"] = headline
+	data["languages[0][body]"] = body
+
+	u := c.groupURL(group) + "/announcements"
+
+	return c.postRedirect(u, u, "sessionID", data)
+}
+
+// DeleteAnnouncement deletes an announcement of the given group.
+func (c *Client) DeleteAnnouncement(group steamid.SteamID, announcementID uint64) error {
+	u := fmt.Sprintf("%s/announcements/delete/%d", c.groupURL(group), announcementID)
+
+	req, err := http.NewRequest("GET", formatQuery(u, url.Values{"sessionID": {c.sessionID}}), nil)
+
+	if err != nil {
+		return err
+	}
+
+	return c.doRedirect(req)
+}
+
+// GroupEventType is the type of a group event.
+type GroupEventType string
+
+const (
+	GroupEventTypeChat        GroupEventType = "ChatEvent"
+	GroupEventTypeGame        GroupEventType = "GameEvent"
+	GroupEventTypeParty       GroupEventType = "PartyEvent"
+	GroupEventTypeMeeting     GroupEventType = "MeetingEvent"
+	GroupEventTypeSpecial     GroupEventType = "SpecialCauseEvent"
+	GroupEventTypeMusicAndArt GroupEventType = "MusicAndArtsEvent"
+	GroupEventTypeSports      GroupEventType = "SportsEvent"
+	GroupEventTypeTrip        GroupEventType = "TripEvent"
+	GroupEventTypeBroadcast   GroupEventType = "BroadcastEvent"
+	GroupEventTypeOther       GroupEventType = "OtherEvent"
+)
+
+// GroupEvent describes a group event to be created.
+type GroupEvent struct {
+	Name string
+	Type GroupEventType
+	// App ID of the game for GroupEventTypeGame events.
+	AppID          uint32
+	ServerIP       string
+	ServerPassword string
+	Notes          string
+	// Start time of the event, in the time zone it should be displayed in. Zero starts it now.
+	Start time.Time
+}
+
+// CreateEvent creates an event in the given group.
+func (c *Client) CreateEvent(group steamid.SteamID, event *GroupEvent) error {
+	data := map[string]string{
+		"action":         "newEvent",
+		"name":           event.Name,
+		"type":           string(event.Type),
+		"appID":          "",
+		"serverIP":       event.ServerIP,
+		"serverPassword": event.ServerPassword,
+		"notes":          event.Notes,
+	}
+
+	if event.AppID != 0 {
+		data["appID"] = strconv.FormatUint(uint64(event.AppID), 10)
+	}
+
+	if event.Start.IsZero() {
+		_, offset := time.Now().Zone()
+		data["tzOffset"] = strconv.Itoa(offset)
+		data["timeChoice"] = "quick"
+		data["eventQuickTime"] = "now"
+	} else {
+		_, offset := event.Start.Zone()
+		data["tzOffset"] = strconv.Itoa(offset)
+		data["timeChoice"] = "specific"
+		data["startDate"] = event.Start.Format("01/02/06")
+		data["startHour"] = event.Start.Format("3")
+		data["startMinute"] = event.Start.Format("04")
+		data["startAMPM"] = event.Start.Format("PM")
+	}
+
+	u := c.groupURL(group) + "/eventEdit"
+
+	return c.postRedirect(u, u, "sessionid", data)
+}
+
+// InviteToGroup invites a user to the given group.
+func (c *Client) InviteToGroup(group, user steamid.SteamID) error {
+	body, err := c.post(c.baseURL+"/actions/GroupInvite", c.profileURL(user), "sessionID", map[string]string{
+		"json":    "1",
+		"type":    "groupInvite",
+		"group":   group.FormatString(),
+		"invitee": user.FormatString(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	result := &struct {
+		Results string
+		Error   string
+	}{}
+
+	if err := decodeJSON(body, result); err != nil {
+		return err
+	}
+
+	if result.Results != "OK" {
+		return fmt.Errorf("community: group invite error: %s", result.Error)
+	}
+
+	return nil
+}
+
+// RespondToJoinRequests approves or denies pending requests to join the given group.
+func (c *Client) RespondToJoinRequests(group steamid.SteamID, approve bool, users ...steamid.SteamID) error {
+	values := url.Values{
+		"json":      {"1"},
+		"sessionID": {c.sessionID},
+		"bapprove":  {"0"},
+	}
+
+	if approve {
+		values.Set("bapprove", "1")
+	}
+
+	for _, user := range users {
+		values.Add("rgAccounts[]", user.FormatString())
+	}
+
+	u := c.groupURL(group) + "/joinRequestsManage"
+
+	body, err := c.postValues(u, u, values)
+
+	if err != nil {
+		return err
+	}
+
+	var result steamlang.EResult
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("community: invalid JSON response: %v", err)
+	}
+
+	if result != steamlang.EResult_OK {
+		return fmt.Errorf("community: join requests error: %v", result)
+	}
+
+	return nil
+}
+
+// KickMember kicks a member from the given group.
+func (c *Client) KickMember(group, member steamid.SteamID) error {
+	u := c.groupURL(group) + "/membersManage"
+
+	return c.postRedirect(u, u, "sessionID", map[string]string{
+		"action":      "kick",
+		"memberId":    member.FormatString(),
+		"queryString": "",
+	})
+}
+
+// GetGroupComments fetches a page of the comment thread of the given group.
+func (c *Client) GetGroupComments(group steamid.SteamID, start, count uint32) (*CommentsPage, error) {
+	return c.getComments("Clan", group, c.groupURL(group), start, count)
+}
+
+// DeleteGroupComment deletes a comment from the comment thread of the given group.
+func (c *Client) DeleteGroupComment(group steamid.SteamID, commentID uint64) error {
+	u := fmt.Sprintf("%s/comment/Clan/delete/%s/-1/", c.baseURL, group.FormatString())
+
+	body, err := c.post(u, c.groupURL(group), "sessionid", map[string]string{
+		"gidcomment": strconv.FormatUint(commentID, 10),
+		"start":      "0",
+		"count":      "6",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeCommentResult(body)
+
+	return err
+}
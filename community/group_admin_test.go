@@ -0,0 +1,194 @@
+package community
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+const announcementErrorPage = `<html><body>
+<form method="post">
+	<div class="formRowTitle error">You must enter a headline.</div>
+	<input type="text" name="headline" value="">
+</form>
+</body></html>`
+
+func TestPostAnnouncement(t *testing.T) {
+	var form map[string]string
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/gid/103582791429521412/announcements" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+
+		form = map[string]string{}
+
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+
+		switch r.PostForm.Get("headline") {
+		case "":
+			_, _ = w.Write([]byte(announcementErrorPage))
+		case "expired":
+			http.Redirect(w, r, "https://steamcommunity.com/login/home/?goto=", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/gid/103582791429521412/announcements/listing", http.StatusFound)
+		}
+	}))
+
+	if err := client.PostAnnouncement(testGroupID, "Headline", "Body"); err != nil {
+		t.Fatalf("PostAnnouncement: %v", err)
+	}
+
+	if form["action"] != "post" || form["sessionID"] != "session" || form["languages[0][body]"] != "Body" {
+		t.Errorf("unexpected form %v", form)
+	}
+
+	if err := client.EditAnnouncement(testGroupID, 123, "Headline", "Body"); err != nil {
+		t.Fatalf("EditAnnouncement: %v", err)
+	}
+
+	if form["action"] != "update" || form["gid"] != "123" {
+		t.Errorf("unexpected form %v", form)
+	}
+
+	err := client.PostAnnouncement(testGroupID, "", "Body")
+
+	if err == nil || err.Error() != "community: POST /gid/103582791429521412/announcements: You must enter a headline." {
+		t.Errorf("expected the form error, got %v", err)
+	}
+
+	err = client.PostAnnouncement(testGroupID, "expired", "Body")
+
+	if err == nil || err.Error() != "community: POST /gid/103582791429521412/announcements: not logged in" {
+		t.Errorf("expected a login error, got %v", err)
+	}
+}
+
+func TestDeleteAnnouncement(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sessionID") != "session" {
+			http.Error(w, "missing session", http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/gid/103582791429521412/announcements/delete/123":
+			http.Redirect(w, r, "/gid/103582791429521412/announcements/listing", http.StatusFound)
+		case "/gid/103582791429521412/announcements/delete/456":
+			http.Redirect(w, r, "https://steamcommunity.com/login/home/?goto=", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	if err := client.DeleteAnnouncement(testGroupID, 123); err != nil {
+		t.Fatalf("DeleteAnnouncement: %v", err)
+	}
+
+	err := client.DeleteAnnouncement(testGroupID, 456)
+
+	if err == nil || err.Error() != "community: GET /gid/103582791429521412/announcements/delete/456: not logged in" {
+		t.Errorf("expected a login error, got %v", err)
+	}
+
+	err = client.DeleteAnnouncement(testGroupID, 789)
+
+	if err == nil || err.Error() != "community: GET /gid/103582791429521412/announcements/delete/789: status code 404" {
+		t.Errorf("expected a status code error, got %v", err)
+	}
+}
+
+func TestCreateEvent(t *testing.T) {
+	var form map[string]string
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+
+		form = map[string]string{}
+
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+
+		if r.URL.Path != "/gid/103582791429521412/eventEdit" {
+			_, _ = w.Write([]byte("<html></html>"))
+			return
+		}
+
+		http.Redirect(w, r, "/gid/103582791429521412/events", http.StatusFound)
+	}))
+
+	start := time.Date(2020, 5, 17, 15, 30, 0, 0, time.FixedZone("", 3600))
+
+	err := client.CreateEvent(testGroupID, &GroupEvent{
+		Name:  "Event",
+		Type:  GroupEventTypeGame,
+		AppID: 440,
+		Start: start,
+	})
+
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	expected := map[string]string{
+		"sessionid":  "session",
+		"type":       "GameEvent",
+		"appID":      "440",
+		"tzOffset":   "3600",
+		"timeChoice": "specific",
+		"startDate":  "05/17/20",
+		"startHour":  "3",
+		"startAMPM":  "PM",
+	}
+
+	for key, value := range expected {
+		if form[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, form[key])
+		}
+	}
+
+	// a page without an error message is still a failure
+	if err := client.KickMember(testGroupID, testSteamID); err == nil {
+		t.Errorf("expected error for a form that was not accepted")
+	}
+}
+
+func TestRespondToJoinRequests(t *testing.T) {
+	var accounts []string
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+
+		accounts = r.PostForm["rgAccounts[]"]
+
+		if r.PostForm.Get("bapprove") == "1" {
+			_, _ = w.Write([]byte("1"))
+		} else {
+			_, _ = w.Write([]byte("2"))
+		}
+	}))
+
+	if err := client.RespondToJoinRequests(testGroupID, true, testSteamID, testSteamID+1); err != nil {
+		t.Fatalf("RespondToJoinRequests: %v", err)
+	}
+
+	if len(accounts) != 2 || accounts[1] != "76561197960287931" {
+		t.Errorf("unexpected accounts %q", accounts)
+	}
+
+	if err := client.RespondToJoinRequests(testGroupID, false, testSteamID); err == nil {
+		t.Errorf("expected error for EResult_Fail")
+	}
+}
@@ -0,0 +1,107 @@
+package community
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/13k/go-steam-resources/steamlang"
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+const testGroupID steamid.SteamID = 103582791429521412
+
+// memberListXML renders the given page of a member list of 3 members, 2 per page.
+func memberListXML(page int) string {
+	members := map[int]string{
+		1: "<steamID64>76561197960287930</steamID64><steamID64>76561197960287931</steamID64>",
+		2: "<steamID64>76561197960287932</steamID64>",
+	}[page]
+
+	next := ""
+
+	if page == 1 {
+		next = "<nextPageLink><![CDATA[" +
+			"https://steamcommunity.com/gid/103582791429521412/memberslistxml/?p=2" +
+			"]]></nextPageLink>"
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<memberList>
+	<groupID64>103582791429521412</groupID64>
+	<groupDetails>
+		<groupName><![CDATA[Valve]]></groupName>
+		<groupURL><![CDATA[valve]]></groupURL>
+		<memberCount>3</memberCount>
+		<membersOnline>1</membersOnline>
+	</groupDetails>
+	<memberCount>3</memberCount>
+	<totalPages>2</totalPages>
+	<currentPage>%d</currentPage>
+	<startingMember>0</startingMember>
+	%s
+	<members>%s</members>
+</memberList>`, page, next, members)
+}
+
+func TestGetGroupMembers(t *testing.T) {
+	var pages []string
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gid/103582791429521412/memberslistxml/" || r.URL.Query().Get("xml") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		pages = append(pages, r.URL.Query().Get("p"))
+
+		if r.URL.Query().Get("p") == "2" {
+			_, _ = w.Write([]byte(memberListXML(2)))
+		} else {
+			_, _ = w.Write([]byte(memberListXML(1)))
+		}
+	}))
+
+	page, err := client.GetGroupMembers(testGroupID, 1)
+
+	if err != nil {
+		t.Fatalf("GetGroupMembers: %v", err)
+	}
+
+	if page.GroupID != testGroupID || page.Details.Name != "Valve" || page.Details.MembersOnline != 1 {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	if len(page.Members) != 2 || page.Members[0] != testSteamID || !page.HasNextPage() {
+		t.Errorf("unexpected first page %+v", page)
+	}
+
+	members, err := client.GetAllGroupMembers(testGroupID)
+
+	if err != nil {
+		t.Fatalf("GetAllGroupMembers: %v", err)
+	}
+
+	if len(members) != 3 || members[2] != 76561197960287932 {
+		t.Errorf("unexpected members %v", members)
+	}
+
+	// the first page is requested without `p`
+	if len(pages) != 3 || pages[0] != "" || pages[1] != "" || pages[2] != "2" {
+		t.Errorf("unexpected requested pages %q", pages)
+	}
+}
+
+func TestMemberGroups(t *testing.T) {
+	groups := socialcache.NewGroupsList()
+	groups.Add(socialcache.Group{SteamID: testGroupID + 1, Relationship: steamlang.EClanRelationship_Member})
+	groups.Add(socialcache.Group{SteamID: testGroupID, Relationship: steamlang.EClanRelationship_Member})
+	groups.Add(socialcache.Group{SteamID: testGroupID + 2, Relationship: steamlang.EClanRelationship_Invited})
+
+	ids := MemberGroups(groups)
+
+	if len(ids) != 2 || ids[0] != testGroupID || ids[1] != testGroupID+1 {
+		t.Errorf("unexpected member groups %v", ids)
+	}
+}
@@ -0,0 +1,384 @@
+package community
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// Profile is a profile summary as returned by the profile XML endpoint.
+//
+// Most fields are empty if the profile is not visible to the authenticated user.
+type Profile struct {
+	SteamID          steamid.SteamID `xml:"steamID64"`
+	PersonaName      string          `xml:"steamID"`
+	OnlineState      string          `xml:"onlineState"`
+	StateMessage     string          `xml:"stateMessage"`
+	PrivacyState     string          `xml:"privacyState"`
+	VisibilityState  uint32          `xml:"visibilityState"`
+	AvatarIcon       string          `xml:"avatarIcon"`
+	AvatarMedium     string          `xml:"avatarMedium"`
+	AvatarFull       string          `xml:"avatarFull"`
+	VACBanned        bool            `xml:"vacBanned"`
+	TradeBanState    string          `xml:"tradeBanState"`
+	IsLimitedAccount bool            `xml:"isLimitedAccount"`
+	CustomURL        string          `xml:"customURL"`
+	MemberSince      string          `xml:"memberSince"`
+	Headline         string          `xml:"headline"`
+	Location         string          `xml:"location"`
+	RealName         string          `xml:"realname"`
+	// HTML
+	Summary string          `xml:"summary"`
+	Groups  []*ProfileGroup `xml:"groups>group"`
+}
+
+// ProfileGroup is a group listed in a Profile.
+type ProfileGroup struct {
+	GroupID      steamid.SteamID `xml:"groupID64"`
+	IsPrimary    bool            `xml:"isPrimary,attr"`
+	Name         string          `xml:"groupName"`
+	URL          string          `xml:"groupURL"`
+	MemberCount  uint32          `xml:"memberCount"`
+	AvatarIcon   string          `xml:"avatarIcon"`
+	AvatarMedium string          `xml:"avatarMedium"`
+	AvatarFull   string          `xml:"avatarFull"`
+}
+
+// GetProfile fetches the profile summary of the given user.
+func (c *Client) GetProfile(id steamid.SteamID) (*Profile, error) {
+	profile := &Profile{}
+
+	if err := c.getXML(c.profileURL(id)+"/?xml=1", profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// ResolveVanityURL returns the SteamID of the user with the given custom URL name, that is, the
+// `name` in `https://steamcommunity.com/id/name`.
+func (c *Client) ResolveVanityURL(name string) (steamid.SteamID, error) {
+	profile := &Profile{}
+
+	if err := c.getXML(c.baseURL+"/id/"+url.PathEscape(name)+"/?xml=1", profile); err != nil {
+		return 0, err
+	}
+
+	if profile.SteamID == 0 {
+		return 0, fmt.Errorf("community: vanity URL %q not found", name)
+	}
+
+	return profile.SteamID, nil
+}
+
+type commentResult struct {
+	Success      bool
+	Error        string
+	Start        uint32
+	TotalCount   uint32 `json:"total_count"`
+	CommentsHTML string `json:"comments_html"`
+}
+
+var commentIDRE = regexp.MustCompile(`id="comment_(\d+)"`)
+
+// PostComment posts a comment on the profile of the given user and returns the comment ID.
+//
+// The comment ID is found in the rendered comment thread returned by Steam, so it is zero if Steam
+// didn't include the new comment in it.
+func (c *Client) PostComment(id steamid.SteamID, text string) (uint64, error) {
+	u := fmt.Sprintf("%s/comment/Profile/post/%s/-1/", c.baseURL, id.FormatString())
+
+	body, err := c.post(u, c.profileURL(id), "sessionid", map[string]string{
+		"comment": text,
+		"count":   "6",
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := decodeCommentResult(body)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// the thread is rendered newest first
+	m := commentIDRE.FindStringSubmatch(result.CommentsHTML)
+
+	if m == nil {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(m[1], 10, 64)
+}
+
+// GetComments fetches a page of the comment thread of the given user's profile.
+func (c *Client) GetComments(id steamid.SteamID, start, count uint32) (*CommentsPage, error) {
+	return c.getComments("Profile", id, c.profileURL(id), start, count)
+}
+
+// DeleteComment deletes a comment from the profile of the given user.
+func (c *Client) DeleteComment(id steamid.SteamID, commentID uint64) error {
+	u := fmt.Sprintf("%s/comment/Profile/delete/%s/-1/", c.baseURL, id.FormatString())
+
+	body, err := c.post(u, c.profileURL(id), "sessionid", map[string]string{
+		"gidcomment": strconv.FormatUint(commentID, 10),
+		"start":      "0",
+		"count":      "6",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeCommentResult(body)
+
+	return err
+}
+
+func decodeCommentResult(body []byte) (*commentResult, error) {
+	result := &commentResult{}
+
+	if err := decodeJSON(body, result); err != nil {
+		return nil, err
+	}
+
+	if !result.Success {
+		return nil, fmt.Errorf("community: comment error: %s", result.Error)
+	}
+
+	return result, nil
+}
+
+// ProfileSettings are the editable profile fields.
+//
+// Steam saves every field at once, so empty fields are cleared. Use GetProfile to fill in the
+// fields that shouldn't change.
+type ProfileSettings struct {
+	PersonaName string
+	RealName    string
+	Summary     string
+	// Country, state and city are location codes, for example "US", "WA" and "3961".
+	Country string
+	State   string
+	City    string
+	// The `name` in `https://steamcommunity.com/id/name`.
+	CustomURL string
+}
+
+// EditProfile saves the profile of the authenticated user.
+func (c *Client) EditProfile(settings *ProfileSettings) error {
+	profileURL := c.profileURL(c.steamID)
+
+	body, err := c.post(profileURL+"/edit/info", profileURL+"/edit/info", "sessionID", map[string]string{
+		"type":                "profileSave",
+		"json":                "1",
+		"hide_profile_awards": "0",
+		"personaName":         settings.PersonaName,
+		"real_name":           settings.RealName,
+		"summary":             settings.Summary,
+		"country":             settings.Country,
+		"state":               settings.State,
+		"city":                settings.City,
+		"customURL":           settings.CustomURL,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	result := &struct {
+		Success int
+		ErrMsg  string `json:"errmsg"`
+	}{}
+
+	if err := decodeJSON(body, result); err != nil {
+		return err
+	}
+
+	if result.Success != 1 {
+		return fmt.Errorf("community: edit profile error: %s", result.ErrMsg)
+	}
+
+	return nil
+}
+
+// PrivacyState is the visibility of a part of the profile.
+type PrivacyState int
+
+const (
+	PrivacyStatePrivate     PrivacyState = 1
+	PrivacyStateFriendsOnly PrivacyState = 2
+	PrivacyStatePublic      PrivacyState = 3
+)
+
+// CommentPermission controls who can comment on a profile.
+type CommentPermission int
+
+const (
+	CommentPermissionFriendsOnly CommentPermission = 0
+	CommentPermissionPublic      CommentPermission = 1
+	CommentPermissionPrivate     CommentPermission = 2
+)
+
+// PrivacySettings are the profile privacy settings.
+type PrivacySettings struct {
+	Profile        PrivacyState `json:"PrivacyProfile"`
+	Inventory      PrivacyState `json:"PrivacyInventory"`
+	InventoryGifts PrivacyState `json:"PrivacyInventoryGifts"`
+	OwnedGames     PrivacyState `json:"PrivacyOwnedGames"`
+	Playtime       PrivacyState `json:"PrivacyPlaytime"`
+	FriendsList    PrivacyState `json:"PrivacyFriendsList"`
+
+	Comments CommentPermission `json:"-"`
+}
+
+// SetPrivacy saves the privacy settings of the authenticated user and returns the settings as
+// saved by Steam.
+func (c *Client) SetPrivacy(settings *PrivacySettings) (*PrivacySettings, error) {
+	privacy, err := json.Marshal(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	profileURL := c.profileURL(c.steamID)
+
+	body, err := c.post(profileURL+"/ajaxsetprivacy/", profileURL+"/edit/settings", "sessionid", map[string]string{
+		"Privacy":            string(privacy),
+		"eCommentPermission": strconv.Itoa(int(settings.Comments)),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &struct {
+		Success int
+		Privacy struct {
+			PrivacySettings    *PrivacySettings
+			ECommentPermission CommentPermission `json:"eCommentPermission"`
+		}
+	}{}
+
+	if err := decodeJSON(body, result); err != nil {
+		return nil, err
+	}
+
+	if result.Success != 1 || result.Privacy.PrivacySettings == nil {
+		return nil, fmt.Errorf("community: set privacy error: result %d", result.Success)
+	}
+
+	saved := result.Privacy.PrivacySettings
+	saved.Comments = result.Privacy.ECommentPermission
+
+	return saved, nil
+}
+
+// AvatarUploadResult is the result of UploadAvatar.
+type AvatarUploadResult struct {
+	// Hex-encoded avatar hash, as used in persona states.
+	Hash string
+	// Image URLs keyed by size name ("full", "medium" and "0" for the small one).
+	Images map[string]string
+}
+
+// UploadAvatar uploads a JPEG, PNG or GIF image and sets it as the avatar of the authenticated user.
+//
+// The filename extension is used to determine the image type.
+func (c *Client) UploadAvatar(image io.Reader, filename string) (*AvatarUploadResult, error) {
+	var contentType string
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jpg", ".jpeg":
+		contentType = "image/jpeg"
+	case ".png":
+		contentType = "image/png"
+	case ".gif":
+		contentType = "image/gif"
+	default:
+		return nil, fmt.Errorf("community: unsupported avatar image type %q", filename)
+	}
+
+	data, err := ioutil.ReadAll(image)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	fields := []struct{ name, value string }{
+		{"MAX_FILE_SIZE", strconv.Itoa(len(data))},
+		{"type", "player_avatar_image"},
+		{"sId", c.steamID.FormatString()},
+		{"sessionid", c.sessionID},
+		{"doSub", "1"},
+		{"json", "1"},
+	}
+
+	for _, f := range fields {
+		if err := w.WriteField(f.name, f.value); err != nil {
+			return nil, err
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="avatar"; filename=%q`, filepath.Base(filename)))
+	header.Set("Content-Type", contentType)
+
+	part, err := w.CreatePart(header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	body, err := c.postMultipart(
+		c.baseURL+"/actions/FileUploader/",
+		c.profileURL(c.steamID)+"/edit/avatar",
+		w.FormDataContentType(),
+		buf,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &struct {
+		Success bool
+		Message string
+		Hash    string
+		Images  map[string]string
+	}{}
+
+	if err := decodeJSON(body, result); err != nil {
+		return nil, err
+	}
+
+	if !result.Success {
+		return nil, fmt.Errorf("community: avatar upload error: %s", result.Message)
+	}
+
+	return &AvatarUploadResult{Hash: result.Hash, Images: result.Images}, nil
+}
@@ -0,0 +1,101 @@
+package community
+
+import (
+	"net/http"
+	"testing"
+)
+
+const profileXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<profile>
+	<steamID64>76561197960287930</steamID64>
+	<steamID><![CDATA[Rabscuttle]]></steamID>
+	<onlineState>online</onlineState>
+	<privacyState>public</privacyState>
+	<visibilityState>3</visibilityState>
+	<avatarFull><![CDATA[https://avatars.steamstatic.com/abc_full.jpg]]></avatarFull>
+	<vacBanned>0</vacBanned>
+	<isLimitedAccount>1</isLimitedAccount>
+	<customURL><![CDATA[rabscuttle]]></customURL>
+	<summary><![CDATA[Hello <b>world</b>]]></summary>
+	<groups>
+		<group isPrimary="1">
+			<groupID64>103582791429521412</groupID64>
+			<groupName><![CDATA[Valve]]></groupName>
+			<memberCount>1000</memberCount>
+		</group>
+		<group isPrimary="0">
+			<groupID64>103582791429521408</groupID64>
+		</group>
+	</groups>
+</profile>`
+
+const profileErrorXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<response>
+	<error><![CDATA[The specified profile could not be found.]]></error>
+</response>`
+
+func TestGetProfile(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/profiles/76561197960287930/" || r.URL.Query().Get("xml") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(profileXML))
+	}))
+
+	profile, err := client.GetProfile(testSteamID)
+
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+
+	if profile.SteamID != testSteamID || profile.PersonaName != "Rabscuttle" || profile.VisibilityState != 3 {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	if profile.VACBanned || !profile.IsLimitedAccount || profile.Summary != "Hello <b>world</b>" {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	if len(profile.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(profile.Groups))
+	}
+
+	g := profile.Groups[0]
+
+	if !g.IsPrimary || g.GroupID != 103582791429521412 || g.Name != "Valve" || g.MemberCount != 1000 {
+		t.Errorf("unexpected primary group %+v", g)
+	}
+
+	if profile.Groups[1].IsPrimary {
+		t.Errorf("expected second group not to be primary")
+	}
+}
+
+func TestResolveVanityURL(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/id/rabscuttle/":
+			_, _ = w.Write([]byte(profileXML))
+		default:
+			_, _ = w.Write([]byte(profileErrorXML))
+		}
+	}))
+
+	id, err := client.ResolveVanityURL("rabscuttle")
+
+	if err != nil {
+		t.Fatalf("ResolveVanityURL: %v", err)
+	}
+
+	if id != testSteamID {
+		t.Errorf("expected %d, got %d", testSteamID, id)
+	}
+
+	if _, err := client.ResolveVanityURL("missing"); err == nil {
+		t.Errorf("expected error for a missing profile")
+	} else if err.Error() != "community: The specified profile could not be found." {
+		t.Errorf("unexpected error %q", err)
+	}
+}
@@ -0,0 +1,241 @@
+package steamtest
+
+// Fixtures recorded from steamcommunity.com and api.steampowered.com, trimmed down to the fields
+// used by the clients.
+
+// OwnInventory is the inventory of the authenticated user in app 440, context 2. Asset 1001 is
+// tradable, asset 1002 is not.
+//
+//nolint:lll
+const OwnInventory = `{
+	"success": true,
+	"rgInventory": {
+		"1001": {"id": "1001", "classid": "101", "instanceid": "0", "amount": "1", "pos": 1},
+		"1002": {"id": "1002", "classid": "102", "instanceid": "0", "amount": "1", "pos": 2}
+	},
+	"rgCurrency": [],
+	"rgDescriptions": {
+		"101_0": {
+			"appid": "440",
+			"classid": "101",
+			"instanceid": "0",
+			"icon_url": "fWFc82js0fmoRAP-qOIPu5THSWqfSmTELLqcUywGkijVjZYMUrsm1j-9xgEAaR4uURrwvz0N252yVaDVWrRTno9m4ccG2GNqxlQoZrC2aG9hcVGUWflbX_drrVu5UGki5sAij6tOtQ",
+			"name": "Mann Co. Supply Crate Key",
+			"market_name": "Mann Co. Supply Crate Key",
+			"market_hash_name": "Mann Co. Supply Crate Key",
+			"name_color": "7D6D00",
+			"background_color": "3C352E",
+			"type": "Level 5 Tool",
+			"tradable": 1,
+			"marketable": 1,
+			"commodity": 1,
+			"market_tradable_restriction": "7",
+			"descriptions": "",
+			"tags": [{"internal_name": "Unique", "name": "Unique", "category": "Quality", "category_name": "Quality"}]
+		},
+		"102_0": {
+			"appid": "440",
+			"classid": "102",
+			"instanceid": "0",
+			"name": "Gift-Stuffed Stocking",
+			"market_name": "Gift-Stuffed Stocking",
+			"market_hash_name": "Gift-Stuffed Stocking",
+			"type": "Level 1 Gift",
+			"tradable": 0,
+			"marketable": 0,
+			"commodity": 0,
+			"descriptions": ""
+		}
+	},
+	"more": false,
+	"more_start": false
+}`
+
+// PartnerInventory is the inventory of the trade partner in app 440, context 2.
+const PartnerInventory = `{
+	"success": true,
+	"rgInventory": {
+		"2001": {"id": "2001", "classid": "201", "instanceid": "11040547", "amount": "1", "pos": 1}
+	},
+	"rgCurrency": [],
+	"rgDescriptions": {
+		"201_11040547": {
+			"appid": "440",
+			"classid": "201",
+			"instanceid": "11040547",
+			"name": "Refined Metal",
+			"market_name": "Refined Metal",
+			"market_hash_name": "Refined Metal",
+			"type": "Level 3 Craft Item",
+			"tradable": 1,
+			"marketable": 1,
+			"commodity": 1,
+			"descriptions": ""
+		}
+	},
+	"more": false,
+	"more_start": false
+}`
+
+// InventoryPage is the profile inventory page of SteamID, listing the inventory apps of app 440 and
+// the Steam app.
+//
+//nolint:lll
+const InventoryPage = `<!DOCTYPE html>
+<html>
+<body>
+<script type="text/javascript">
+	var g_rgAppContextData = {"440":{"appid":440,"name":"Team Fortress 2","icon":"","link":"https:\/\/steamcommunity.com\/app\/440","asset_count":2,"inventory_logo":"","trade_permissions":"FULL","load_failed":0,"rgContexts":{"2":{"asset_count":2,"id":"2","name":"Backpack"}}},"753":{"appid":753,"name":"Steam","icon":"","link":"https:\/\/steamcommunity.com\/app\/753","asset_count":5,"inventory_logo":"","trade_permissions":"FULL","load_failed":0,"rgContexts":{"1":{"asset_count":1,"id":"1","name":"Gifts"},"3":{"asset_count":1,"id":"3","name":"Coupons"},"6":{"asset_count":3,"id":"6","name":"Community"}}}};
+	var g_strInventoryLoadURL = 'https://steamcommunity.com/profiles/76561197960265729/inventory/json/';
+</script>
+</body>
+</html>`
+
+// Receipt is the trade receipt page of an accepted offer.
+//
+//nolint:lll
+const Receipt = `<!DOCTYPE html>
+<html>
+<body>
+<div class="trade_receipt">
+<script type="text/javascript">
+	oItem = {"id":"3001","owner":"76561197960287930","pos":1,"appid":440,"contextid":2,"classid":"201","instanceid":"11040547","name":"Refined Metal","market_hash_name":"Refined Metal","type":"Level 3 Craft Item","tradable":1,"marketable":1,"commodity":1,"descriptions":""};
+	oItem.appid = 440;
+</script>
+</div>
+</body>
+</html>`
+
+// EscrowPage is the new trade offer page, with the escrow durations in days.
+const EscrowPage = `<!DOCTYPE html>
+<html>
+<body>
+<script type="text/javascript">
+	var g_daysMyEscrow = 0;
+	var g_daysTheirEscrow = 2;
+</script>
+</body>
+</html>`
+
+// NotFriendsPage is shown instead of the new trade offer page without a valid access token.
+const NotFriendsPage = `<!DOCTYPE html>
+<html>
+<body>
+<div id="error_msg">
+	<div class="error_page_content">You are not friends with this user</div>
+</div>
+</body>
+</html>`
+
+// InvalidTokenPage is shown instead of the new trade offer page when the access token is invalid.
+const InvalidTokenPage = `<!DOCTYPE html>
+<html>
+<body>
+<div id="error_msg">
+	This Trade URL is no longer valid for sending a trade offer to Partner.
+</div>
+</body>
+</html>`
+
+// PrivacyPage is the trade offer privacy page, with the trade URL. The token is replaced by
+// Server.
+//
+//nolint:lll
+const PrivacyPage = `<!DOCTYPE html>
+<html>
+<body>
+<div class="trade_offer_access_url_ctn">
+	<input class="trade_offer_access_url" id="trade_offer_access_url" type="text" readonly value="https://steamcommunity.com/tradeoffer/new/?partner=%d&amp;token=%s">
+</div>
+</body>
+</html>`
+
+// HoldDurations is the GetTradeHoldDurations response.
+const HoldDurations = `{
+	"response": {
+		"my_escrow": {"escrow_end_duration_seconds": 0},
+		"their_escrow": {"escrow_end_duration_seconds": 172800},
+		"both_escrow": {"escrow_end_duration_seconds": 172800}
+	}
+}`
+
+// TradeStatus is a live trade status with an item added by the partner.
+//
+//nolint:lll
+const TradeStatus = `{
+	"success": true,
+	"trade_status": 0,
+	"version": 2,
+	"logpos": 1,
+	"me": {"ready": 0, "confirmed": 0, "sec_since_touch": 1, "connection_pending": false, "assets": [], "currency": []},
+	"them": {
+		"ready": 0,
+		"confirmed": 0,
+		"sec_since_touch": 3,
+		"connection_pending": false,
+		"assets": [{"appid": "440", "contextid": "2", "assetid": "2001", "amount": 1}],
+		"currency": []
+	},
+	"events": [
+		{"steamid": "76561197960287930", "action": "0", "timestamp": 1400000000, "appid": 440, "contextid": "2", "assetid": "2001"}
+	]
+}`
+
+// StrError is the body of failed create and accept requests. The EResult code is formatted in.
+const StrError = `{"strError": "There was an error sending your trade offer.  Please try again later. (%d)"}`
+
+// OfferDescriptions are the descriptions returned by GetTradeOffers with get_descriptions, for
+// the assets of OwnInventory. Descriptions of partner assets are only available from ClassInfo.
+const OfferDescriptions = `[
+	{
+		"appid": 440,
+		"classid": "101",
+		"instanceid": "0",
+		"name": "Mann Co. Supply Crate Key",
+		"market_name": "Mann Co. Supply Crate Key",
+		"market_hash_name": "Mann Co. Supply Crate Key",
+		"type": "Level 5 Tool",
+		"tradable": true,
+		"commodity": true,
+		"market_tradable_restriction": 7,
+		"tags": [{"internal_name": "Unique", "name": "Unique", "category": "Quality", "category_name": "Quality"}]
+	},
+	{
+		"appid": 440,
+		"classid": "102",
+		"instanceid": "0",
+		"name": "Gift-Stuffed Stocking",
+		"market_name": "Gift-Stuffed Stocking",
+		"market_hash_name": "Gift-Stuffed Stocking",
+		"type": "Level 1 Gift",
+		"tradable": false,
+		"commodity": false
+	}
+]`
+
+// ClassInfo is the GetAssetClassInfo result of the asset of PartnerInventory, keyed by
+// "classid_instanceid".
+const ClassInfo = `{
+	"201_11040547": {
+		"icon_url": "fWFc82js0fmoRAP-qOIPu5THSWqfSmTELLqcUywGkijVjZYMUrsm1j-9xgEDbQEZ",
+		"name": "Refined Metal",
+		"market_hash_name": "Refined Metal",
+		"market_name": "Refined Metal",
+		"name_color": "7D6D00",
+		"background_color": "3C352E",
+		"type": "Level 3 Craft Item",
+		"tradable": "1",
+		"marketable": "1",
+		"commodity": "1",
+		"market_tradable_restriction": "7",
+		"descriptions": {
+			"0": {"type": "html", "value": "Used in crafting"}
+		},
+		"tags": {
+			"0": {"internal_name": "Unique", "name": "Unique", "category": "Quality", "category_name": "Quality"},
+			"1": {"internal_name": "Craft Item", "name": "Craft Item", "category": "Type", "category_name": "Type"}
+		},
+		"classid": "201",
+		"instanceid": "11040547"
+	}
+}`
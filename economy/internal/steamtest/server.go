@@ -0,0 +1,545 @@
+// Package steamtest implements a fake steamcommunity.com and api.steampowered.com for testing the
+// economy packages without network access.
+package steamtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// Credentials accepted by Server.
+const (
+	APIKey      = "APIKEY"
+	SessionID   = "SESSIONID"
+	AccessToken = "TOKEN"
+)
+
+// SteamID of the authenticated user.
+const SteamID steamid.SteamID = 76561197960265729
+
+// Trade offer states, as in tradeoffer.State.
+const (
+	StateActive    = 2
+	StateAccepted  = 3
+	StateCountered = 4
+	StateCanceled  = 6
+	StateDeclined  = 7
+	StateInEscrow  = 11
+
+	StateCreatedNeedsConfirmation = 9
+)
+
+// EResult codes reported in strError.
+const (
+	eresultInvalidState = 11
+	eresultRevoked      = 26
+)
+
+// Asset is an item in an Offer.
+type Asset struct {
+	AppID      uint32 `json:"appid"`
+	ContextID  string `json:"contextid"`
+	AssetID    string `json:"assetid"`
+	ClassID    string `json:"classid"`
+	InstanceID string `json:"instanceid"`
+	Amount     string `json:"amount"`
+	Missing    bool   `json:"missing,omitempty"`
+}
+
+// Offer is a trade offer as returned by GetTradeOffers.
+type Offer struct {
+	TradeOfferID       string  `json:"tradeofferid"`
+	TradeID            string  `json:"tradeid,omitempty"`
+	OtherAccountID     uint32  `json:"accountid_other"`
+	Message            string  `json:"message"`
+	ExpirationTime     uint32  `json:"expiration_time"`
+	State              int     `json:"trade_offer_state"`
+	ToGive             []Asset `json:"items_to_give,omitempty"`
+	ToReceive          []Asset `json:"items_to_receive,omitempty"`
+	IsOurOffer         bool    `json:"is_our_offer"`
+	TimeCreated        uint32  `json:"time_created"`
+	TimeUpdated        uint32  `json:"time_updated"`
+	EscrowEndDate      uint32  `json:"escrow_end_date"`
+	ConfirmationMethod int     `json:"confirmation_method"`
+}
+
+// Class and instance IDs of the assets of OwnInventory and PartnerInventory.
+var fixtureClasses = map[string][2]string{
+	"1001": {"101", "0"},
+	"1002": {"102", "0"},
+	"2001": {"201", "11040547"},
+}
+
+// Asset IDs of OwnInventory.
+var ownAssets = map[string]bool{"1001": true, "1002": true}
+
+// Server is a fake Steam server that keeps the state of trade offers and serves the fixtures for
+// everything else.
+//
+// The same server is used as community and Web API base URL.
+type Server struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	offers      map[uint64]*Offer
+	nextOfferID uint64
+	nextTradeID uint64
+	token       string
+	// Number of GetAssetClassInfo requests served.
+	classInfoRequests int
+	// Fake clock, advanced on every offer change.
+	now uint32
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		offers:      make(map[uint64]*Offer),
+		nextOfferID: 100,
+		nextTradeID: 500,
+		token:       AccessToken,
+		now:         1500000000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/IEconService/", s.handleAPI)
+	mux.HandleFunc("/ISteamEconomy/GetAssetClassInfo/v1", s.handleClassInfo)
+	mux.HandleFunc("/tradeoffer/", s.handleTradeOffer)
+	mux.HandleFunc("/my/inventory/json/", s.handleFixture(OwnInventory))
+	mux.HandleFunc("/profiles/", s.handleFixture(InventoryPage))
+	mux.HandleFunc("/my/tradeoffers/privacy", s.handlePrivacy)
+	mux.HandleFunc("/trade/", s.handleTrade)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// ClassInfoRequests returns the number of GetAssetClassInfo requests served.
+func (s *Server) ClassInfoRequests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.classInfoRequests
+}
+
+// Offer returns a copy of the offer with the given ID, or nil if it doesn't exist.
+func (s *Server) Offer(offerID uint64) *Offer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	offer, ok := s.offers[offerID]
+
+	if !ok {
+		return nil
+	}
+
+	cp := *offer
+
+	return &cp
+}
+
+// ReceiveOffer simulates an offer from the given partner and returns its ID.
+func (s *Server) ReceiveOffer(partner steamid.SteamID, toGive, toReceive []Asset) uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.addOffer(partner.AccountID().Uint32(), toGive, toReceive, false, "")
+}
+
+// SetOfferState changes the state of an offer, as if the partner acted on it.
+func (s *Server) SetOfferState(offerID uint64, state int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if offer, ok := s.offers[offerID]; ok {
+		s.setState(offer, state)
+	}
+}
+
+func (s *Server) addOffer(partner uint32, toGive, toReceive []Asset, ours bool, message string) uint64 {
+	s.now++
+	s.nextOfferID++
+
+	s.offers[s.nextOfferID] = &Offer{
+		TradeOfferID:   strconv.FormatUint(s.nextOfferID, 10),
+		OtherAccountID: partner,
+		Message:        message,
+		ExpirationTime: s.now + 14*24*60*60,
+		State:          StateActive,
+		ToGive:         toGive,
+		ToReceive:      toReceive,
+		IsOurOffer:     ours,
+		TimeCreated:    s.now,
+		TimeUpdated:    s.now,
+	}
+
+	return s.nextOfferID
+}
+
+func (s *Server) setState(offer *Offer, state int) {
+	s.now++
+	offer.State = state
+	offer.TimeUpdated = s.now
+
+	if state == StateAccepted && offer.TradeID == "" {
+		s.nextTradeID++
+		offer.TradeID = strconv.FormatUint(s.nextTradeID, 10)
+	}
+}
+
+func (s *Server) handleFixture(fixture string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fixture)
+	}
+}
+
+func (s *Server) handlePrivacy(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	fmt.Fprintf(w, PrivacyPage, SteamID.AccountID(), s.token)
+}
+
+// handleTrade serves trade receipts and the live trade endpoints of the trade API client.
+func (s *Server) handleTrade(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/receipt"):
+		fmt.Fprint(w, Receipt)
+	case strings.HasSuffix(r.URL.Path, "/foreigninventory"):
+		fmt.Fprint(w, PartnerInventory)
+	case r.Method == "POST":
+		if r.PostFormValue("sessionid") != SessionID {
+			http.Error(w, "invalid session", http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, TradeStatus)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleTradeOffer(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/tradeoffer/"), "/"), "/")
+
+	switch {
+	case len(parts) == 2 && parts[1] == "partnerinventory":
+		fmt.Fprint(w, PartnerInventory)
+	case len(parts) == 2 && parts[0] == "new" && parts[1] == "send":
+		s.handleSend(w, r)
+	case len(parts) == 2 && parts[1] == "accept":
+		s.handleAccept(w, r, parts[0])
+	case len(parts) == 1 && parts[0] == "new":
+		switch r.URL.Query().Get("token") {
+		case "":
+			fmt.Fprint(w, NotFriendsPage)
+		case s.currentToken():
+			fmt.Fprint(w, EscrowPage)
+		default:
+			fmt.Fprint(w, InvalidTokenPage)
+		}
+	case len(parts) == 1:
+		fmt.Fprint(w, EscrowPage)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) currentToken() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.token
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.PostFormValue("sessionid") != SessionID {
+		http.Error(w, "invalid session", http.StatusForbidden)
+		return
+	}
+
+	partner, err := strconv.ParseUint(r.PostFormValue("partner"), 10, 64)
+
+	if err != nil {
+		http.Error(w, "invalid partner", http.StatusBadRequest)
+		return
+	}
+
+	type sentAsset struct {
+		AppID     uint32
+		ContextID string
+		AssetID   string
+		Amount    uint64
+	}
+
+	offer := &struct {
+		Me struct {
+			Assets []sentAsset
+		}
+		Them struct {
+			Assets []sentAsset
+		}
+	}{}
+
+	if err := json.Unmarshal([]byte(r.PostFormValue("json_tradeoffer")), offer); err != nil {
+		http.Error(w, "invalid json_tradeoffer", http.StatusBadRequest)
+		return
+	}
+
+	// only the assets of OwnInventory can be given
+	for _, asset := range offer.Me.Assets {
+		if !ownAssets[asset.AssetID] {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, StrError, eresultRevoked)
+
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if countered, err := strconv.ParseUint(r.PostFormValue("tradeofferid_countered"), 10, 64); err == nil {
+		if old, ok := s.offers[countered]; ok {
+			s.setState(old, StateCountered)
+		}
+	}
+
+	toAssets := func(sent []sentAsset) []Asset {
+		assets := make([]Asset, len(sent))
+
+		for i, a := range sent {
+			class := fixtureClasses[a.AssetID]
+
+			assets[i] = Asset{
+				AppID:      a.AppID,
+				ContextID:  a.ContextID,
+				AssetID:    a.AssetID,
+				ClassID:    class[0],
+				InstanceID: class[1],
+				Amount:     strconv.FormatUint(a.Amount, 10),
+			}
+		}
+
+		return assets
+	}
+
+	accountID := steamid.SteamID(partner).AccountID().Uint32()
+	message := r.PostFormValue("tradeoffermessage")
+	id := s.addOffer(accountID, toAssets(offer.Me.Assets), toAssets(offer.Them.Assets), true, message)
+
+	fmt.Fprintf(w, `{"tradeofferid": "%d"}`, id)
+}
+
+func (s *Server) handleAccept(w http.ResponseWriter, r *http.Request, idParam string) {
+	if r.Method != "POST" || r.PostFormValue("sessionid") != SessionID {
+		http.Error(w, "invalid session", http.StatusForbidden)
+		return
+	}
+
+	id, _ := strconv.ParseUint(idParam, 10, 64)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	offer, ok := s.offers[id]
+
+	if !ok || offer.IsOurOffer || offer.State != StateActive {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, StrError, eresultInvalidState)
+
+		return
+	}
+
+	s.setState(offer, StateAccepted)
+
+	fmt.Fprintf(w, `{"tradeid": "%s"}`, offer.TradeID)
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("key") != APIKey {
+		http.Error(w, "invalid key", http.StatusForbidden)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/IEconService/"), "/")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var response interface{}
+
+	switch parts[0] {
+	case "GetTradeOffers":
+		response = s.getOffers(r)
+	case "GetTradeOffer":
+		id, _ := strconv.ParseUint(r.FormValue("tradeofferid"), 10, 64)
+
+		if offer, ok := s.offers[id]; ok {
+			response = map[string]interface{}{
+				"offer":        offer,
+				"descriptions": offerDescriptions(r, offer),
+			}
+		} else {
+			response = struct{}{}
+		}
+	case "DeclineTradeOffer", "CancelTradeOffer":
+		id, _ := strconv.ParseUint(r.FormValue("tradeofferid"), 10, 64)
+		offer, ok := s.offers[id]
+
+		// sent offers can also be canceled before being confirmed
+		pending := ok && (offer.State == StateActive || offer.IsOurOffer && offer.State == StateCreatedNeedsConfirmation)
+
+		if !pending || offer.IsOurOffer != (parts[0] == "CancelTradeOffer") {
+			http.Error(w, "invalid offer", http.StatusInternalServerError)
+			return
+		}
+
+		if parts[0] == "CancelTradeOffer" {
+			s.setState(offer, StateCanceled)
+		} else {
+			s.setState(offer, StateDeclined)
+		}
+
+		response = struct{}{}
+	case "GetTradeHoldDurations":
+		fmt.Fprint(w, HoldDurations)
+		return
+	case "GetTradeOfferAccessToken":
+		if r.FormValue("generate_new_token") == "1" {
+			s.token = fmt.Sprintf("%s%d", AccessToken, s.now)
+		}
+
+		response = map[string]string{"trade_offer_access_token": s.token}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"response": response})
+}
+
+// getOffers implements the filters of GetTradeOffers.
+func (s *Server) getOffers(r *http.Request) interface{} {
+	cutoff, _ := strconv.ParseUint(r.FormValue("time_historical_cutoff"), 10, 32)
+	activeOnly := r.FormValue("active_only") == "1"
+
+	ids := make([]uint64, 0, len(s.offers))
+
+	for id := range s.offers {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	sent := make([]*Offer, 0)
+	received := make([]*Offer, 0)
+
+	for _, id := range ids {
+		offer := s.offers[id]
+
+		if activeOnly && offer.State != StateActive && uint64(offer.TimeUpdated) < cutoff {
+			continue
+		}
+
+		if offer.IsOurOffer && r.FormValue("get_sent_offers") == "1" {
+			sent = append(sent, offer)
+		}
+
+		if !offer.IsOurOffer && r.FormValue("get_received_offers") == "1" {
+			received = append(received, offer)
+		}
+	}
+
+	return map[string]interface{}{
+		"trade_offers_sent":     sent,
+		"trade_offers_received": received,
+		"descriptions":          offerDescriptions(r, append(sent, received...)...),
+	}
+}
+
+// offerDescriptions returns the descriptions of OfferDescriptions of the assets of the given offers,
+// if requested.
+func offerDescriptions(r *http.Request, offers ...*Offer) []map[string]interface{} {
+	if r.FormValue("get_descriptions") != "1" {
+		return nil
+	}
+
+	var all []map[string]interface{}
+
+	if err := json.Unmarshal([]byte(OfferDescriptions), &all); err != nil {
+		panic(err)
+	}
+
+	classes := make(map[string]bool)
+
+	for _, offer := range offers {
+		for _, assets := range [][]Asset{offer.ToGive, offer.ToReceive} {
+			for _, a := range assets {
+				classes[a.ClassID+"_"+a.InstanceID] = true
+			}
+		}
+	}
+
+	descriptions := make([]map[string]interface{}, 0)
+
+	for _, desc := range all {
+		if classes[fmt.Sprintf("%s_%s", desc["classid"], desc["instanceid"])] {
+			descriptions = append(descriptions, desc)
+		}
+	}
+
+	return descriptions
+}
+
+// handleClassInfo serves the requested classes found in ClassInfo.
+func (s *Server) handleClassInfo(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("key") != APIKey {
+		http.Error(w, "invalid key", http.StatusForbidden)
+		return
+	}
+
+	s.mutex.Lock()
+	s.classInfoRequests++
+	s.mutex.Unlock()
+
+	var all map[string]json.RawMessage
+
+	if err := json.Unmarshal([]byte(ClassInfo), &all); err != nil {
+		panic(err)
+	}
+
+	count, _ := strconv.Atoi(r.FormValue("class_count"))
+	result := map[string]interface{}{"success": true}
+
+	for i := 0; i < count; i++ {
+		key := r.FormValue(fmt.Sprintf("classid%d", i))
+
+		if instanceID := r.FormValue(fmt.Sprintf("instanceid%d", i)); instanceID != "" && instanceID != "0" {
+			key += "_" + instanceID
+		}
+
+		if info, ok := all[key]; ok {
+			result[key] = info
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"result": result})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
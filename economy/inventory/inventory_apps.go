@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/13k/go-steam/steamid"
@@ -38,6 +39,21 @@ type App struct {
 	Contexts         Contexts `json:"rgContexts"`
 }
 
+// ContextsOfKind returns the contexts of the given kind, sorted by ID.
+func (a *App) ContextsOfKind(kind ContextKind) []*Context {
+	var contexts []*Context
+
+	for _, context := range a.Contexts {
+		if context.Kind == kind {
+			contexts = append(contexts, context)
+		}
+	}
+
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].ContextID < contexts[j].ContextID })
+
+	return contexts
+}
+
 type Contexts map[string]*Context
 
 func (c *Contexts) Get(contextID uint64) (*Context, error) {
@@ -58,10 +74,84 @@ type Context struct {
 	ContextID  uint64 `json:"id,string"`
 	AssetCount uint32 `json:"asset_count"`
 	Name       string
+	// Set by GetApps
+	Kind ContextKind `json:"-"`
+}
+
+// The Steam app, whose inventory holds gifts, coupons and community items.
+const SteamAppID uint32 = 753
+
+// Contexts of the Steam app inventory.
+const (
+	SteamContextGifts       uint64 = 1
+	SteamContextCoupons     uint64 = 3
+	SteamContextCommunity   uint64 = 6
+	SteamContextItemRewards uint64 = 7
+)
+
+// ContextKind is the kind of items held by an inventory context.
+type ContextKind int
+
+const (
+	// Items of a game
+	ContextKindGame ContextKind = iota
+	// Steam gifts and wallet codes
+	ContextKindGift
+	// Discount coupons
+	ContextKindCoupon
+	// Trading cards, backgrounds and emoticons
+	ContextKindCommunity
+	// Points shop items
+	ContextKindItemReward
+	// Other contexts of the Steam app
+	ContextKindUnknown
+)
+
+var contextKindNames = map[ContextKind]string{
+	ContextKindGame:       "Game",
+	ContextKindGift:       "Gift",
+	ContextKindCoupon:     "Coupon",
+	ContextKindCommunity:  "Community",
+	ContextKindItemReward: "ItemReward",
+	ContextKindUnknown:    "Unknown",
+}
+
+func (k ContextKind) String() string {
+	if name, ok := contextKindNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("ContextKind(%d)", int(k))
+}
+
+// ContextKindOf returns the kind of the given context. All contexts of apps other than the Steam app
+// hold game items.
+func ContextKindOf(appID uint32, contextID uint64) ContextKind {
+	if appID != SteamAppID {
+		return ContextKindGame
+	}
+
+	switch contextID {
+	case SteamContextGifts:
+		return ContextKindGift
+	case SteamContextCoupons:
+		return ContextKindCoupon
+	case SteamContextCommunity:
+		return ContextKindCommunity
+	case SteamContextItemRewards:
+		return ContextKindItemReward
+	}
+
+	return ContextKindUnknown
 }
 
 func GetApps(client *http.Client, steamID steamid.SteamID) (Apps, error) {
-	resp, err := http.Get("http://steamcommunity.com/profiles/" + steamID.FormatString() + "/inventory/")
+	return GetAppsWithBaseURL(client, DefaultCommunityURL, steamID)
+}
+
+// GetAppsWithBaseURL is like GetApps, but uses the given base URL instead of DefaultCommunityURL.
+func GetAppsWithBaseURL(client *http.Client, baseURL string, steamID steamid.SteamID) (Apps, error) {
+	resp, err := client.Get(baseURL + "/profiles/" + steamID.FormatString() + "/inventory/")
 
 	if err != nil {
 		return nil, err
@@ -89,5 +179,11 @@ func GetApps(client *http.Client, steamID steamid.SteamID) (Apps, error) {
 		return nil, err
 	}
 
+	for _, app := range apps {
+		for _, context := range app.Contexts {
+			context.Kind = ContextKindOf(app.AppID, context.ContextID)
+		}
+	}
+
 	return apps, nil
 }
@@ -0,0 +1,61 @@
+package inventory
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+)
+
+func TestGetApps(t *testing.T) {
+	server := steamtest.NewServer()
+	defer server.Close()
+
+	apps, err := GetAppsWithBaseURL(&http.Client{}, server.URL, steamtest.SteamID)
+
+	if err != nil {
+		t.Fatalf("GetApps: %v", err)
+	}
+
+	tf2, err := apps.Get(440)
+
+	if err != nil {
+		t.Fatalf("Apps.Get(440): %v", err)
+	}
+
+	if context, err := tf2.Contexts.Get(2); err != nil || context.Kind != ContextKindGame {
+		t.Errorf("expected game context 2, got %+v (%v)", context, err)
+	}
+
+	steam, err := apps.Get(SteamAppID)
+
+	if err != nil {
+		t.Fatalf("Apps.Get(%d): %v", SteamAppID, err)
+	}
+
+	kinds := map[uint64]ContextKind{
+		SteamContextGifts:     ContextKindGift,
+		SteamContextCoupons:   ContextKindCoupon,
+		SteamContextCommunity: ContextKindCommunity,
+	}
+
+	for contextID, kind := range kinds {
+		context, err := steam.Contexts.Get(contextID)
+
+		if err != nil {
+			t.Fatalf("Contexts.Get(%d): %v", contextID, err)
+		}
+
+		if context.Kind != kind {
+			t.Errorf("expected context %d to be %v, got %v", contextID, kind, context.Kind)
+		}
+	}
+
+	if gifts := steam.ContextsOfKind(ContextKindGift); len(gifts) != 1 || gifts[0].Name != "Gifts" {
+		t.Errorf("unexpected gift contexts %+v", gifts)
+	}
+
+	if kind := ContextKindOf(SteamAppID, 99); kind != ContextKindUnknown {
+		t.Errorf("expected unknown context kind, got %v", kind)
+	}
+}
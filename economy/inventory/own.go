@@ -1,24 +1,52 @@
 package inventory
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
 
+// DefaultCommunityURL is the base URL of steamcommunity.com inventories are fetched from, unless
+// another one is given to the WithBaseURL variants.
+const DefaultCommunityURL = "https://steamcommunity.com"
+
 func GetOwnPartialInventory(
 	client *http.Client,
 	contextID uint64,
 	appID uint32,
 	start uint,
+) (*PartialInventory, error) {
+	return GetOwnPartialInventoryContext(context.Background(), client, contextID, appID, start)
+}
+
+func GetOwnPartialInventoryContext(
+	ctx context.Context,
+	client *http.Client,
+	contextID uint64,
+	appID uint32,
+	start uint,
+) (*PartialInventory, error) {
+	return GetOwnPartialInventoryWithBaseURL(ctx, client, DefaultCommunityURL, contextID, appID, start)
+}
+
+// GetOwnPartialInventoryWithBaseURL is like GetOwnPartialInventoryContext, but uses the given base
+// URL instead of DefaultCommunityURL.
+func GetOwnPartialInventoryWithBaseURL(
+	ctx context.Context,
+	client *http.Client,
+	baseURL string,
+	contextID uint64,
+	appID uint32,
+	start uint,
 ) (*PartialInventory, error) {
 	// TODO: the "trading" parameter can be left off to return non-tradable items too
-	url := fmt.Sprintf("http://steamcommunity.com/my/inventory/json/%d/%d?trading=1", appID, contextID)
+	url := fmt.Sprintf("%s/my/inventory/json/%d/%d?trading=1", baseURL, appID, contextID)
 
 	if start != 0 {
 		url += fmt.Sprintf("&start=%d", start)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 
 	if err != nil {
 		return nil, err
@@ -28,7 +56,28 @@ func GetOwnPartialInventory(
 }
 
 func GetOwnInventory(client *http.Client, contextID uint64, appID uint32) (*Inventory, error) {
+	return GetOwnInventoryContext(context.Background(), client, contextID, appID)
+}
+
+func GetOwnInventoryContext(
+	ctx context.Context,
+	client *http.Client,
+	contextID uint64,
+	appID uint32,
+) (*Inventory, error) {
+	return GetOwnInventoryWithBaseURL(ctx, client, DefaultCommunityURL, contextID, appID)
+}
+
+// GetOwnInventoryWithBaseURL is like GetOwnInventoryContext, but uses the given base URL instead of
+// DefaultCommunityURL.
+func GetOwnInventoryWithBaseURL(
+	ctx context.Context,
+	client *http.Client,
+	baseURL string,
+	contextID uint64,
+	appID uint32,
+) (*Inventory, error) {
 	return GetFullInventory(func(start uint) (*PartialInventory, error) {
-		return GetOwnPartialInventory(client, contextID, appID, start)
+		return GetOwnPartialInventoryWithBaseURL(ctx, client, baseURL, contextID, appID, start)
 	})
 }
@@ -0,0 +1,40 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+)
+
+func TestGetOwnInventory(t *testing.T) {
+	server := steamtest.NewServer()
+	defer server.Close()
+
+	inv, err := GetOwnInventoryWithBaseURL(context.Background(), &http.Client{}, server.URL, 2, 440)
+
+	if err != nil {
+		t.Fatalf("GetOwnInventory: %v", err)
+	}
+
+	if len(inv.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(inv.Items))
+	}
+
+	item, err := inv.Items.Get(1001)
+
+	if err != nil {
+		t.Fatalf("Items.Get: %v", err)
+	}
+
+	desc, err := inv.Descriptions.Get(item.ClassID, item.InstanceID)
+
+	if err != nil {
+		t.Fatalf("Descriptions.Get: %v", err)
+	}
+
+	if desc.MarketHashName != "Mann Co. Supply Crate Key" || !desc.Tradable || len(desc.Tags) != 1 {
+		t.Errorf("unexpected description %+v", desc)
+	}
+}
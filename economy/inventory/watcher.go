@@ -0,0 +1,423 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ContextKey identifies an app/context pair of an inventory.
+type ContextKey struct {
+	AppID     uint32
+	ContextID uint64
+}
+
+// InventoryFetcher fetches the full inventory of the given app/context pair.
+type InventoryFetcher func(appID uint32, contextID uint64) (*Inventory, error)
+
+// OwnInventoryFetcher returns an InventoryFetcher that fetches the inventory of the user logged in
+// with the cookies of the given client.
+func OwnInventoryFetcher(client *http.Client) InventoryFetcher {
+	return func(appID uint32, contextID uint64) (*Inventory, error) {
+		return GetOwnInventory(client, contextID, appID)
+	}
+}
+
+// Watcher periodically refetches a set of inventories and emits events describing which items were
+// added, removed or had their amount changed since the last fetch.
+//
+// Always poll events from the channel returned by Events() or the watcher will stop refreshing.
+// Like steam.Client, only types ending with "Event" and errors are emitted.
+//
+// If SnapshotPath is set, the last known inventories are loaded from it on Start() and written to it
+// after every refresh, so that changes that happened while the watcher was not running are reported
+// after a restart. The first successful fetch of a context without a previous snapshot only
+// establishes its baseline and doesn't emit any events.
+type Watcher struct {
+	// Interval between refreshes.
+	Interval time.Duration
+	// Path of the file where snapshots are persisted. Persistence is disabled if empty.
+	SnapshotPath string
+
+	fetch    InventoryFetcher
+	contexts []ContextKey
+	events   chan interface{}
+
+	refreshMutex sync.Mutex
+
+	mutex    sync.Mutex
+	snapshot GenericInventory
+	stop     chan struct{}
+}
+
+// NewWatcher creates a Watcher that uses fetch to refresh each of the given contexts.
+func NewWatcher(fetch InventoryFetcher, interval time.Duration, contexts ...ContextKey) *Watcher {
+	return &Watcher{
+		Interval: interval,
+		fetch:    fetch,
+		contexts: contexts,
+		events:   make(chan interface{}, 30),
+	}
+}
+
+// Events returns the event channel. It is never closed.
+func (w *Watcher) Events() <-chan interface{} {
+	return w.events
+}
+
+// Snapshot returns a copy of the last fetched inventories.
+func (w *Watcher) Snapshot() GenericInventory {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	snapshot := NewGenericInventory()
+
+	for appID, contexts := range w.snapshot {
+		for contextID, inv := range contexts {
+			snapshot.Add(appID, contextID, copyInventory(inv))
+		}
+	}
+
+	return snapshot
+}
+
+// Start loads the persisted snapshot, if any, and starts refreshing in the background.
+//
+// Calling Start on a running watcher is a no-op.
+func (w *Watcher) Start() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stop != nil {
+		return nil
+	}
+
+	if w.snapshot == nil && w.SnapshotPath != "" {
+		snapshot, err := loadSnapshot(w.SnapshotPath)
+
+		if err != nil {
+			return err
+		}
+
+		w.snapshot = snapshot
+	}
+
+	w.stop = make(chan struct{})
+
+	go w.loop(w.stop)
+
+	return nil
+}
+
+// Stop stops refreshing. A refresh in progress is not interrupted, but its events are dropped if
+// they're not received.
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stop == nil {
+		return
+	}
+
+	close(w.stop)
+	w.stop = nil
+}
+
+func (w *Watcher) loop(stop chan struct{}) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.refresh()
+
+		if err != nil {
+			events = append(events, err)
+		}
+
+		for _, event := range events {
+			if !w.emit(event, stop) {
+				return
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches all watched contexts, emits the events for the changes found and persists the new
+// snapshot. It is called periodically after Start, but can also be called directly.
+//
+// Contexts that fail to be fetched keep their previous snapshot and are retried on the next refresh.
+func (w *Watcher) Refresh() error {
+	events, err := w.refresh()
+
+	// emit without holding the lock, so that Snapshot() can be called while handling events
+	for _, event := range events {
+		w.emit(event, nil)
+	}
+
+	return err
+}
+
+func (w *Watcher) refresh() ([]interface{}, error) {
+	// refreshes are serialized so that an older fetch is never applied after a newer one
+	w.refreshMutex.Lock()
+	defer w.refreshMutex.Unlock()
+
+	var (
+		events []interface{}
+		errs   []error
+	)
+
+	// fetch without holding the lock, so that Snapshot() and Stop() don't wait for the network
+	fetched := make(map[ContextKey]*Inventory, len(w.contexts))
+
+	for _, key := range w.contexts {
+		inv, err := w.fetch(key.AppID, key.ContextID)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("inventory/Watcher: error fetching %d/%d: %v", key.AppID, key.ContextID, err))
+			continue
+		}
+
+		fetched[key] = inv
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	snapshot := NewGenericInventory()
+
+	for appID, contexts := range w.snapshot {
+		for contextID, inv := range contexts {
+			snapshot.Add(appID, contextID, inv)
+		}
+	}
+
+	for _, key := range w.contexts {
+		inv, ok := fetched[key]
+
+		if !ok {
+			continue
+		}
+
+		// a context without a previous snapshot was never fetched successfully, so its first
+		// successful fetch only establishes its baseline
+		if old, err := snapshot.Get(key.AppID, key.ContextID); err == nil {
+			events = append(events, Diff(key, old, inv)...)
+		}
+
+		snapshot.Add(key.AppID, key.ContextID, inv)
+	}
+
+	w.snapshot = snapshot
+
+	if w.SnapshotPath != "" {
+		if err := saveSnapshot(w.SnapshotPath, w.snapshot); err != nil {
+			errs = append(errs, fmt.Errorf("inventory/Watcher: error saving snapshot: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return events, errs[0]
+	}
+
+	return events, nil
+}
+
+// emit sends an event unless stop is closed first, reporting whether it was sent. A nil stop blocks
+// until the event is received.
+func (w *Watcher) emit(event interface{}, stop <-chan struct{}) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Diff compares two inventories of the same context by asset ID and returns ItemAddedEvent,
+// ItemRemovedEvent and ItemAmountChangedEvent events for the differences. A nil inventory is
+// treated as empty.
+func Diff(key ContextKey, old, cur *Inventory) []interface{} {
+	if old == nil {
+		old = NewInventory()
+	}
+
+	if cur == nil {
+		cur = NewInventory()
+	}
+
+	var events []interface{}
+
+	for assetID, item := range cur.Items {
+		oldItem, ok := old.Items[assetID]
+
+		if !ok {
+			events = append(events, &ItemAddedEvent{
+				ContextKey:  key,
+				Item:        item,
+				Description: findDescription(cur, item),
+			})
+
+			continue
+		}
+
+		if oldItem.Amount != item.Amount {
+			events = append(events, &ItemAmountChangedEvent{
+				ContextKey:  key,
+				Item:        item,
+				Description: findDescription(cur, item),
+				OldAmount:   oldItem.Amount,
+				NewAmount:   item.Amount,
+			})
+		}
+	}
+
+	for assetID, item := range old.Items {
+		if _, ok := cur.Items[assetID]; !ok {
+			events = append(events, &ItemRemovedEvent{
+				ContextKey:  key,
+				Item:        item,
+				Description: findDescription(old, item),
+			})
+		}
+	}
+
+	return events
+}
+
+func findDescription(inv *Inventory, item *Item) *Description {
+	desc, err := inv.Descriptions.Get(item.ClassID, item.InstanceID)
+
+	if err != nil {
+		return nil
+	}
+
+	return desc
+}
+
+// copyInventory returns a deep copy of an inventory.
+func copyInventory(inv *Inventory) *Inventory {
+	c := NewInventory()
+
+	for id, item := range inv.Items {
+		i := *item
+		c.Items[id] = &i
+	}
+
+	for id, currency := range inv.Currencies {
+		cur := *currency
+		c.Currencies[id] = &cur
+	}
+
+	for id, desc := range inv.Descriptions {
+		c.Descriptions[id] = copyDescription(desc)
+	}
+
+	if inv.AppInfo != nil {
+		appInfo := *inv.AppInfo
+		c.AppInfo = &appInfo
+	}
+
+	return c
+}
+
+func copyDescription(desc *Description) *Description {
+	c := *desc
+
+	if desc.Descriptions != nil {
+		c.Descriptions = make(DescriptionLines, len(desc.Descriptions))
+
+		for i, line := range desc.Descriptions {
+			l := *line
+			l.Type = copyString(line.Type)
+			l.Color = copyString(line.Color)
+			c.Descriptions[i] = &l
+		}
+	}
+
+	if desc.Actions != nil {
+		c.Actions = make([]*Action, len(desc.Actions))
+
+		for i, action := range desc.Actions {
+			a := *action
+			c.Actions[i] = &a
+		}
+	}
+
+	if desc.AppData != nil {
+		c.AppData = make(map[string]string, len(desc.AppData))
+
+		for k, v := range desc.AppData {
+			c.AppData[k] = v
+		}
+	}
+
+	if desc.Tags != nil {
+		c.Tags = make([]*Tag, len(desc.Tags))
+
+		for i, tag := range desc.Tags {
+			t := *tag
+			c.Tags[i] = &t
+		}
+	}
+
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+
+	return &c
+}
+
+func loadSnapshot(path string) (GenericInventory, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := NewGenericInventory()
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("inventory/Watcher: invalid snapshot %s: %v", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// saveSnapshot writes to a temporary file first so that a crash never leaves a truncated snapshot.
+func saveSnapshot(path string, snapshot GenericInventory) error {
+	data, err := json.Marshal(snapshot)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
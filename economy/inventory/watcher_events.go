@@ -0,0 +1,26 @@
+package inventory
+
+// ItemAddedEvent is emitted by Watcher when an asset appears in a watched inventory.
+type ItemAddedEvent struct {
+	ContextKey
+	Item        *Item
+	Description *Description // nil if Steam didn't send a description for the item
+}
+
+// ItemRemovedEvent is emitted by Watcher when an asset disappears from a watched inventory.
+//
+// The description is taken from the previous snapshot.
+type ItemRemovedEvent struct {
+	ContextKey
+	Item        *Item
+	Description *Description
+}
+
+// ItemAmountChangedEvent is emitted by Watcher when the amount of a stackable asset changes.
+type ItemAmountChangedEvent struct {
+	ContextKey
+	Item        *Item
+	Description *Description
+	OldAmount   uint64
+	NewAmount   uint64
+}
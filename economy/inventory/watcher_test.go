@@ -0,0 +1,349 @@
+package inventory
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testKey = ContextKey{AppID: 440, ContextID: 2}
+
+func testInventory(amounts map[uint64]uint64) *Inventory {
+	inv := NewInventory()
+
+	for assetID, amount := range amounts {
+		classID := assetID * 10
+		inv.Items[strconv.FormatUint(assetID, 10)] = &Item{ID: assetID, ClassID: classID, Amount: amount}
+		inv.Descriptions[strconv.FormatUint(classID, 10)+"_0"] = &Description{
+			AppID:   440,
+			ClassID: classID,
+			Name:    "Item " + strconv.FormatUint(assetID, 10),
+			Tags:    []*Tag{{Name: "Tag"}},
+		}
+	}
+
+	return inv
+}
+
+func TestDiff(t *testing.T) {
+	old := testInventory(map[uint64]uint64{1: 1, 2: 5, 3: 1})
+	cur := testInventory(map[uint64]uint64{2: 3, 3: 1, 4: 1})
+
+	events := Diff(testKey, old, cur)
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+
+	for _, event := range events {
+		switch e := event.(type) {
+		case *ItemAddedEvent:
+			if e.Item.ID != 4 || e.Description == nil || e.Description.Name != "Item 4" || e.ContextKey != testKey {
+				t.Errorf("unexpected added event %+v", e)
+			}
+		case *ItemRemovedEvent:
+			if e.Item.ID != 1 || e.Description == nil || e.Description.Name != "Item 1" {
+				t.Errorf("unexpected removed event %+v", e)
+			}
+		case *ItemAmountChangedEvent:
+			if e.Item.ID != 2 || e.OldAmount != 5 || e.NewAmount != 3 || e.Description == nil {
+				t.Errorf("unexpected amount changed event %+v", e)
+			}
+		default:
+			t.Errorf("unexpected event %#v", event)
+		}
+	}
+
+	if events := Diff(testKey, nil, cur); len(events) != 3 {
+		t.Errorf("expected every item to be added from a nil inventory, got %d events", len(events))
+	}
+
+	if events := Diff(testKey, old, nil); len(events) != 3 {
+		t.Errorf("expected every item to be removed to a nil inventory, got %d events", len(events))
+	}
+
+	if events := Diff(testKey, cur, cur); len(events) != 0 {
+		t.Errorf("expected no events for the same inventory, got %d", len(events))
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot.json")
+
+	snapshot := NewGenericInventory()
+	snapshot.Add(testKey.AppID, testKey.ContextID, testInventory(map[uint64]uint64{1: 1, 2: 5}))
+
+	if err := saveSnapshot(path, snapshot); err != nil {
+		t.Fatalf("saveSnapshot: %v", err)
+	}
+
+	loaded, err := loadSnapshot(path)
+
+	if err != nil {
+		t.Fatalf("loadSnapshot: %v", err)
+	}
+
+	inv, err := loaded.Get(testKey.AppID, testKey.ContextID)
+
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if events := Diff(testKey, inv, testInventory(map[uint64]uint64{1: 1, 2: 5})); len(events) != 0 {
+		t.Errorf("expected loaded snapshot to equal the saved one, got %d events", len(events))
+	}
+
+	desc, err := inv.Descriptions.Get(20, 0)
+
+	if err != nil || desc.Name != "Item 2" || len(desc.Tags) != 1 {
+		t.Errorf("unexpected loaded description %+v", desc)
+	}
+
+	missing, err := loadSnapshot(filepath.Join(dir, "missing.json"))
+
+	if missing != nil || err != nil {
+		t.Errorf("expected no snapshot and no error for a missing file, got %v, %v", missing, err)
+	}
+}
+
+// fakeFetcher serves copies of the inventory set with set.
+type fakeFetcher struct {
+	mutex   sync.Mutex
+	inv     *Inventory
+	failing map[ContextKey]bool
+}
+
+func (f *fakeFetcher) fail(key ContextKey, failing bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.failing == nil {
+		f.failing = make(map[ContextKey]bool)
+	}
+
+	f.failing[key] = failing
+}
+
+func (f *fakeFetcher) set(inv *Inventory) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.inv = inv
+}
+
+func (f *fakeFetcher) fetch(appID uint32, contextID uint64) (*Inventory, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.failing[ContextKey{AppID: appID, ContextID: contextID}] {
+		return nil, errors.New("fetch failed")
+	}
+
+	return copyInventory(f.inv), nil
+}
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fetcher := &fakeFetcher{inv: testInventory(map[uint64]uint64{1: 1})}
+	watcher := NewWatcher(fetcher.fetch, time.Minute, testKey)
+	watcher.SnapshotPath = filepath.Join(dir, "snapshot.json")
+
+	if err := watcher.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if len(watcher.Events()) != 0 {
+		t.Fatalf("expected no events on baseline")
+	}
+
+	fetcher.set(testInventory(map[uint64]uint64{1: 1, 2: 1}))
+
+	if err := watcher.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if e, ok := (<-watcher.Events()).(*ItemAddedEvent); !ok || e.Item.ID != 2 {
+		t.Fatalf("expected ItemAddedEvent for item 2, got %#v", e)
+	}
+
+	snapshot := watcher.Snapshot()
+	inv, _ := snapshot.Get(testKey.AppID, testKey.ContextID)
+	delete(inv.Items, "1")
+	inv.Descriptions["20_0"].Name = "Modified"
+
+	snapshot = watcher.Snapshot()
+	cur, _ := snapshot.Get(testKey.AppID, testKey.ContextID)
+
+	if len(cur.Items) != 2 || cur.Descriptions["20_0"].Name != "Item 2" {
+		t.Errorf("expected Snapshot to return a copy")
+	}
+
+	// changes while not running are reported after a restart
+	fetcher.set(testInventory(map[uint64]uint64{2: 1}))
+
+	restarted := NewWatcher(fetcher.fetch, time.Minute, testKey)
+	restarted.SnapshotPath = watcher.SnapshotPath
+
+	if err := restarted.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	defer restarted.Stop()
+
+	select {
+	case event := <-restarted.Events():
+		if e, ok := event.(*ItemRemovedEvent); !ok || e.Item.ID != 1 {
+			t.Fatalf("expected ItemRemovedEvent for item 1, got %#v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for first refresh")
+	}
+}
+
+func TestWatcherBaselinePerContext(t *testing.T) {
+	otherKey := ContextKey{AppID: testKey.AppID, ContextID: testKey.ContextID + 1}
+	fetcher := &fakeFetcher{inv: testInventory(map[uint64]uint64{1: 1})}
+	fetcher.fail(otherKey, true)
+	watcher := NewWatcher(fetcher.fetch, time.Minute, testKey, otherKey)
+
+	if err := watcher.Refresh(); err == nil {
+		t.Fatal("expected fetch error")
+	}
+
+	if len(watcher.Events()) != 0 {
+		t.Fatalf("expected no events on baseline")
+	}
+
+	// the first successful fetch of otherKey only establishes its baseline
+	fetcher.fail(otherKey, false)
+
+	if err := watcher.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if len(watcher.Events()) != 0 {
+		t.Fatalf("expected no events on baseline of %v, got %#v", otherKey, <-watcher.Events())
+	}
+
+	fetcher.set(testInventory(map[uint64]uint64{1: 1, 2: 1}))
+
+	if err := watcher.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	keys := make(map[ContextKey]bool)
+
+	for len(watcher.Events()) > 0 {
+		e, ok := (<-watcher.Events()).(*ItemAddedEvent)
+
+		if !ok || e.Item.ID != 2 {
+			t.Fatalf("expected ItemAddedEvent for item 2, got %#v", e)
+		}
+
+		keys[e.ContextKey] = true
+	}
+
+	if !keys[testKey] || !keys[otherKey] || len(keys) != 2 {
+		t.Errorf("expected ItemAddedEvent for both contexts, got %v", keys)
+	}
+}
+
+func TestWatcherSnapshotDuringFetch(t *testing.T) {
+	fetcher := &fakeFetcher{inv: testInventory(map[uint64]uint64{1: 1})}
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+
+	watcher := NewWatcher(func(appID uint32, contextID uint64) (*Inventory, error) {
+		close(fetching)
+		<-release
+		return fetcher.fetch(appID, contextID)
+	}, time.Minute, testKey)
+
+	done := make(chan error, 1)
+
+	go func() { done <- watcher.Refresh() }()
+
+	<-fetching
+
+	snapshot := make(chan GenericInventory, 1)
+
+	go func() { snapshot <- watcher.Snapshot() }()
+
+	select {
+	case <-snapshot:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Snapshot blocked by a refresh in progress")
+	}
+
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	refreshed := watcher.Snapshot()
+
+	if inv, err := refreshed.Get(testKey.AppID, testKey.ContextID); err != nil || len(inv.Items) != 1 {
+		t.Errorf("expected refreshed snapshot, got %v, %v", inv, err)
+	}
+}
+
+func TestWatcherStopUnblocks(t *testing.T) {
+	fetcher := &fakeFetcher{inv: testInventory(map[uint64]uint64{1: 1})}
+	watcher := NewWatcher(fetcher.fetch, time.Millisecond, testKey)
+
+	if err := watcher.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// more events than the channel can buffer, never received
+	amounts := make(map[uint64]uint64)
+
+	for i := uint64(1); i <= uint64(cap(watcher.events))+10; i++ {
+		amounts[i+100] = 1
+	}
+
+	fetcher.set(testInventory(amounts))
+
+	if err := watcher.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// wait for the loop to fill the channel and block
+	for len(watcher.Events()) < cap(watcher.events) {
+		time.Sleep(time.Millisecond)
+	}
+
+	watcher.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	for len(watcher.Events()) > 0 {
+		<-watcher.Events()
+	}
+
+	// a stopped loop blocked on the channel must have given up instead of sending the rest
+	select {
+	case event := <-watcher.Events():
+		t.Fatalf("unexpected event after Stop: %#v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/13k/go-steam/jsont"
 	"github.com/13k/go-steam/steamid"
@@ -108,8 +110,68 @@ type User struct {
 	Confirmed         jsont.UintBool
 	SecSinceTouch     int  `json:"sec_since_touch"`
 	ConnectionPending bool `json:"connection_pending"`
-	Assets            interface{}
-	Currency          interface{} // either []*Currency or empty string
+	Assets            AssetList
+	Currency          CurrencyList
+}
+
+// Asset is an item put up in the trade.
+type Asset struct {
+	AppID     uint32
+	ContextID uint64
+	AssetID   uint64
+	Amount    uint64
+}
+
+// Steam sends the numbers of assets either as strings or as numbers.
+func (a *Asset) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		AppID     json.RawMessage
+		ContextID json.RawMessage
+		AssetID   json.RawMessage
+		Amount    json.RawMessage
+	}{}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	appID, err := parseFlexUint(aux.AppID)
+
+	if err != nil {
+		return err
+	}
+
+	a.AppID = uint32(appID)
+
+	if a.ContextID, err = parseFlexUint(aux.ContextID); err != nil {
+		return err
+	}
+
+	if a.AssetID, err = parseFlexUint(aux.AssetID); err != nil {
+		return err
+	}
+
+	if a.Amount, err = parseFlexUint(aux.Amount); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AssetList is the list of assets of a user, in slot order.
+type AssetList []*Asset
+
+// The AssetList can either be an array, an object of slot -> asset or an empty string
+func (l *AssetList) UnmarshalJSON(data []byte) error {
+	var assets []*Asset
+
+	if err := unmarshalFlexList(data, &assets); err != nil {
+		return err
+	}
+
+	*l = assets
+
+	return nil
 }
 
 type Currency struct {
@@ -118,3 +180,74 @@ type Currency struct {
 	CurrencyID uint64 `json:",string"`
 	Amount     uint64 `json:",string"`
 }
+
+// CurrencyList is the list of currencies of a user.
+type CurrencyList []*Currency
+
+// The CurrencyList can either be an array, an object of id -> currency or an empty string
+func (l *CurrencyList) UnmarshalJSON(data []byte) error {
+	var currencies []*Currency
+
+	if err := unmarshalFlexList(data, &currencies); err != nil {
+		return err
+	}
+
+	*l = currencies
+
+	return nil
+}
+
+// unmarshalFlexList decodes a JSON array, an object with numeric keys (in key order) or an empty
+// string into list, which must be a pointer to a slice.
+func unmarshalFlexList(data []byte, list interface{}) error {
+	switch strings.TrimSpace(string(data)) {
+	case `""`, "null":
+		return nil
+	}
+
+	if err := json.Unmarshal(data, list); err == nil {
+		return nil
+	}
+
+	o := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+
+	keys := make([]int, 0, len(o))
+
+	for k := range o {
+		i, err := strconv.Atoi(k)
+
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, i)
+	}
+
+	sort.Ints(keys)
+
+	items := make([]json.RawMessage, len(keys))
+
+	for i, k := range keys {
+		items[i] = o[strconv.Itoa(k)]
+	}
+
+	data, err := json.Marshal(items)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, list)
+}
+
+func parseFlexUint(raw json.RawMessage) (uint64, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(strings.Trim(string(raw), `"`), 10, 64)
+}
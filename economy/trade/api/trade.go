@@ -2,6 +2,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,7 +18,7 @@ import (
 	"github.com/13k/go-steam/steamid"
 )
 
-const tradeURL = "https://steamcommunity.com/trade/%d/"
+const communityURL = "https://steamcommunity.com"
 
 type Client struct {
 	LogPos  uint // not automatically updated
@@ -27,11 +28,19 @@ type Client struct {
 	other     steamid.SteamID
 	sessionID string // the `sessionid` cookie is sent as a parameter/POST data for CSRF protection.
 	baseURL   string
+	// base URL of steamcommunity.com, own inventories are fetched from it
+	communityURL string
 }
 
 // New creates a new Trade based on the given cookies `sessionid`, `steamLogin`, `steamLoginSecure` and
 // the trade partner's Steam ID.
 func New(sessionID, steamLogin, steamLoginSecure string, other steamid.SteamID) (*Client, error) {
+	return NewWithBaseURL(communityURL, sessionID, steamLogin, steamLoginSecure, other)
+}
+
+// NewWithBaseURL is like New, but uses the given base URL instead of https://steamcommunity.com.
+// Useful for testing.
+func NewWithBaseURL(baseURL, sessionID, steamLogin, steamLoginSecure string, other steamid.SteamID) (*Client, error) {
 	client := &http.Client{}
 	client.Timeout = 10 * time.Second
 
@@ -39,8 +48,10 @@ func New(sessionID, steamLogin, steamLoginSecure string, other steamid.SteamID)
 		client:    client,
 		other:     other,
 		sessionID: sessionID,
-		baseURL:   fmt.Sprintf(tradeURL, other),
+		baseURL:   fmt.Sprintf("%s/trade/%d/", baseURL, other),
 		Version:   1,
+
+		communityURL: baseURL,
 	}
 
 	if err := community.SetCookies(t.client, sessionID, steamLogin, steamLoginSecure); err != nil {
@@ -112,10 +123,16 @@ func (t *Client) postWithStatus(url string, data map[string]string) (*Result, er
 }
 
 func (t *Client) GetStatus() (*Result, error) {
+	return t.GetStatusAt(t.Version, t.LogPos)
+}
+
+// GetStatusAt is like GetStatus, but for the given version and log position instead of the ones of
+// the client. Thread-safe.
+func (t *Client) GetStatusAt(version, logPos uint) (*Result, error) {
 	return t.postWithStatus(t.baseURL+"tradestatus/", map[string]string{
 		"sessionid": t.sessionID,
-		"logpos":    strconv.FormatUint(uint64(t.LogPos), 10),
-		"version":   strconv.FormatUint(uint64(t.Version), 10),
+		"logpos":    strconv.FormatUint(uint64(logPos), 10),
+		"version":   strconv.FormatUint(uint64(version), 10),
 	})
 }
 
@@ -145,7 +162,7 @@ func (t *Client) GetForeignInventory(contextID uint64, appID uint32, start uint)
 
 // Thread-safe.
 func (t *Client) GetOwnInventory(contextID uint64, appID uint32) (*inventory.Inventory, error) {
-	return inventory.GetOwnInventory(t.client, contextID, appID)
+	return inventory.GetOwnInventoryWithBaseURL(context.Background(), t.client, t.communityURL, contextID, appID)
 }
 
 func (t *Client) Chat(message string) (*Result, error) {
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+	"github.com/13k/go-steam/steamid"
+)
+
+const partner steamid.SteamID = 76561197960287930
+
+func TestGetStatus(t *testing.T) {
+	server := steamtest.NewServer()
+	defer server.Close()
+
+	client, err := NewWithBaseURL(server.URL, steamtest.SessionID, "login", "secure", partner)
+
+	if err != nil {
+		t.Fatalf("NewWithBaseURL: %v", err)
+	}
+
+	status, err := client.GetStatus()
+
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+
+	if !status.Success || status.TradeStatus != StatusOpen || status.Version != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	expected := Asset{AppID: 440, ContextID: 2, AssetID: 2001, Amount: 1}
+
+	if len(status.Them.Assets) != 1 || *status.Them.Assets[0] != expected {
+		t.Errorf("unexpected partner assets %+v", status.Them.Assets)
+	}
+
+	event, ok := status.Events[0]
+
+	if !ok || event.Action != ActionAddItem || event.SteamID != partner || event.AssetID != 2001 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	inv, err := client.GetForeignInventory(2, 440, 0)
+
+	if err != nil {
+		t.Fatalf("GetForeignInventory: %v", err)
+	}
+
+	if _, err := inv.Items.Get(2001); err != nil {
+		t.Errorf("expected partner item 2001: %v", err)
+	}
+}
+
+func TestAssetListUnmarshal(t *testing.T) {
+	testCases := map[string]int{
+		`""`: 0,
+		`[]`: 0,
+		`[{"appid": 440, "contextid": "2", "assetid": "1", "amount": "1"}]`:                                           1,
+		`{"1": {"appid": "440", "contextid": "2", "assetid": "2", "amount": 1}, "0": {"appid": 440, "assetid": "1"}}`: 2,
+	}
+
+	for data, expected := range testCases {
+		var list AssetList
+
+		if err := json.Unmarshal([]byte(data), &list); err != nil {
+			t.Errorf("%s: unexpected error: %v", data, err)
+			continue
+		}
+
+		if len(list) != expected {
+			t.Errorf("%s: expected %d assets, got %d", data, expected, len(list))
+		}
+
+		if expected == 2 && (list[0].AssetID != 1 || list[1].AssetID != 2) {
+			t.Errorf("%s: expected assets in slot order, got %+v %+v", data, list[0], list[1])
+		}
+	}
+}
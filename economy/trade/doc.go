@@ -31,6 +31,14 @@ cookies, or use go-steam and after logging in with client.Web.LogOn() and receiv
 WebLoggedOnEvent use the `SessionID` and `SteamLogin` fields of steam.Web for the respective
 cookies.
 
+Alternatively, a Session polls in the background, keeps track of the items of both sides and delivers
+the events on a channel:
+
+	session := trade.NewSession(t)
+	for event := range session.Events() {
+		// handle events
+	}
+
 It is important that there is no delay between the Poll() calls greater than the timeout of the
 Steam client (currently five seconds before the trade partner sees a warning) or the trade will be
 closed automatically by Steam.
@@ -0,0 +1,479 @@
+package trade
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/13k/go-steam/economy/trade/api"
+)
+
+// Errors returned by Session.
+var (
+	ErrSessionEnded = errors.New("trade: session ended")
+	ErrNotReady     = errors.New("trade: both sides must be ready before confirming")
+	ErrNotInTrade   = errors.New("trade: item is not in the trade")
+	ErrInTrade      = errors.New("trade: item is already in the trade")
+	// The trade changed since the last poll. The session was resynced and the action can be retried.
+	ErrOutOfSync = errors.New("trade: out of sync")
+)
+
+// Side is the state of one side of a trade.
+type Side struct {
+	Ready bool
+	// Items in slot order
+	Items []*Item
+	// Amount of each currency
+	Currency map[Currency]uint64
+}
+
+type sessionSide struct {
+	items    map[Slot]*Item
+	currency map[Currency]uint64
+}
+
+func newSessionSide() *sessionSide {
+	return &sessionSide{
+		items:    make(map[Slot]*Item),
+		currency: make(map[Currency]uint64),
+	}
+}
+
+func (s *sessionSide) find(item *Item) (Slot, bool) {
+	for slot, it := range s.items {
+		if *it == *item {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
+
+func (s *sessionSide) freeSlot() Slot {
+	var slot Slot
+
+	for {
+		if _, ok := s.items[slot]; !ok {
+			return slot
+		}
+
+		slot++
+	}
+}
+
+func (s *sessionSide) add(item *Item) {
+	if _, ok := s.find(item); !ok {
+		s.items[s.freeSlot()] = item
+	}
+}
+
+func (s *sessionSide) remove(item *Item) {
+	if slot, ok := s.find(item); ok {
+		delete(s.items, slot)
+	}
+}
+
+func (s *sessionSide) sync(user *api.User) {
+	s.items = make(map[Slot]*Item)
+	s.currency = make(map[Currency]uint64)
+
+	for i, asset := range user.Assets {
+		s.items[Slot(i)] = &Item{
+			AppID:     asset.AppID,
+			ContextID: asset.ContextID,
+			AssetID:   asset.AssetID,
+		}
+	}
+
+	for _, currency := range user.Currency {
+		key := Currency{
+			AppID:      uint32(currency.AppID),
+			ContextID:  currency.ContextID,
+			CurrencyID: currency.CurrencyID,
+		}
+
+		s.currency[key] = currency.Amount
+	}
+}
+
+func (s *sessionSide) snapshot(ready bool) *Side {
+	slots := make([]int, 0, len(s.items))
+
+	for slot := range s.items {
+		slots = append(slots, int(slot))
+	}
+
+	sort.Ints(slots)
+
+	side := &Side{
+		Ready:    ready,
+		Items:    make([]*Item, len(slots)),
+		Currency: make(map[Currency]uint64, len(s.currency)),
+	}
+
+	for i, slot := range slots {
+		item := *s.items[Slot(slot)]
+		side.Items[i] = &item
+	}
+
+	for currency, amount := range s.currency {
+		side.Currency[currency] = amount
+	}
+
+	return side
+}
+
+// Session drives a Trade: it polls Steam in the background at the pace of the official client,
+// keeps track of the items and currency of both sides and emits the trade events on a channel.
+//
+// Start a session after receiving a steam.TradeSessionStartEvent, which follows a
+// steam.Trading.RequestTrade accepted by the partner or a proposed trade accepted with
+// steam.Trading.RespondRequest:
+//
+//	t, err := trade.New(web.SessionID, web.SteamLogin, web.SteamLoginSecure, event.Other)
+//	session := trade.NewSession(t)
+//	for event := range session.Events() {
+//		// handle events
+//	}
+//
+// Besides the Trade events, a SyncEvent is emitted whenever the items were resynced and errors
+// are emitted when polling fails. The events channel is closed when the trade ends, after the
+// EndEvent, or when the session is stopped.
+//
+// Unlike Trade, a Session is safe for concurrent use. The Trade must not be used directly after
+// the session is created.
+type Session struct {
+	trade  *Trade
+	events chan interface{}
+
+	mutex sync.Mutex
+	me    *sessionSide
+	them  *sessionSide
+	ended bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSession creates a Session for the given trade and starts polling.
+func NewSession(t *Trade) *Session {
+	s := &Session{
+		trade:  t,
+		events: make(chan interface{}, 30),
+		me:     newSessionSide(),
+		them:   newSessionSide(),
+		stop:   make(chan struct{}),
+	}
+
+	go s.loop()
+
+	return s
+}
+
+// Events returns the event channel.
+func (s *Session) Events() <-chan interface{} {
+	return s.events
+}
+
+// Me returns the state of our side of the trade.
+func (s *Session) Me() *Side {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.me.snapshot(s.trade.MeReady)
+}
+
+// Them returns the state of the partner's side of the trade.
+func (s *Session) Them() *Side {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.them.snapshot(s.trade.ThemReady)
+}
+
+// Stop stops polling without canceling the trade, which Steam cancels after a few seconds.
+func (s *Session) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *Session) loop() {
+	defer close(s.events)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(pollTimeout):
+		}
+
+		events, err := s.poll()
+
+		if err != nil {
+			events = append(events, err)
+		}
+
+		for _, event := range events {
+			if !s.emit(event) {
+				return
+			}
+
+			if _, ok := event.(*EndEvent); ok {
+				return
+			}
+		}
+	}
+}
+
+// emit sends an event, giving up if the session is stopped first.
+func (s *Session) emit(event interface{}) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+func (s *Session) poll() ([]interface{}, error) {
+	if err := s.fetchStatus(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	events := s.trade.Events()
+
+	var out []interface{}
+
+	// a new version has the full state, including readiness, which already includes the changes of
+	// the events
+	synced := s.trade.synced != nil
+
+	if synced {
+		s.resync()
+		out = append(out, &SyncEvent{})
+	}
+
+	for _, event := range events {
+		if _, ok := event.(*EndEvent); ok {
+			s.ended = true
+		}
+
+		if !synced {
+			s.apply(event)
+		}
+	}
+
+	return append(out, events...), nil
+}
+
+// fetchStatus requests the trade status, unless actions already queued events. The request is sent
+// without holding the lock, so that actions don't wait for it.
+func (s *Session) fetchStatus() error {
+	s.mutex.Lock()
+	queued := len(s.trade.queuedEvents) > 0
+	version, logPos := s.trade.api.Version, s.trade.api.LogPos
+	s.mutex.Unlock()
+
+	if queued {
+		return nil
+	}
+
+	status, err := s.trade.api.GetStatusAt(version, logPos)
+
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// an action changed the trade during the request and its more recent status was applied
+	if s.trade.api.Version != version || s.trade.api.LogPos != logPos {
+		return nil
+	}
+
+	return s.trade.onStatus(status)
+}
+
+// apply updates the state with an event of the partner. Events must be applied in order, since
+// readiness depends on whether items changed before or after a ready event.
+func (s *Session) apply(event interface{}) {
+	switch e := event.(type) {
+	case *ItemAddedEvent:
+		s.them.add(e.Item)
+		s.unready()
+	case *ItemRemovedEvent:
+		s.them.remove(e.Item)
+		s.unready()
+	case *SetCurrencyEvent:
+		s.them.currency[*e.Currency] = e.NewAmount
+		s.unready()
+	case *ReadyEvent:
+		s.trade.ThemReady = true
+	case *UnreadyEvent:
+		s.trade.ThemReady = false
+	}
+}
+
+// resync rebuilds both sides from the last full state.
+func (s *Session) resync() {
+	s.me.sync(&s.trade.synced.Me)
+	s.them.sync(&s.trade.synced.Them)
+	s.trade.synced = nil
+}
+
+// Steam unreadies both sides whenever the items change.
+func (s *Session) unready() {
+	s.trade.MeReady = false
+	s.trade.ThemReady = false
+}
+
+// action runs an action with the lock held. If Steam rejects it, the session is resynced and
+// ErrOutOfSync is returned.
+func (s *Session) action(f func() error) error {
+	if s.ended {
+		return ErrSessionEnded
+	}
+
+	if err := f(); err != nil {
+		if !errors.Is(err, ErrUnsuccessful) {
+			return err
+		}
+
+		// a stale version makes Steam respond with the full state
+		s.trade.api.Version = 0
+
+		if syncErr := s.trade.action(s.trade.api.GetStatus()); syncErr != nil {
+			return err
+		}
+
+		if s.trade.synced != nil {
+			s.resync()
+		}
+
+		return ErrOutOfSync
+	}
+
+	return nil
+}
+
+// AddItem adds one of our items to the trade.
+func (s *Session) AddItem(item *Item) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.me.find(item); ok {
+		return ErrInTrade
+	}
+
+	slot := s.me.freeSlot()
+
+	return s.action(func() error {
+		if err := s.trade.AddItem(slot, item); err != nil {
+			return err
+		}
+
+		s.me.items[slot] = item
+		s.unready()
+
+		return nil
+	})
+}
+
+// RemoveItem removes one of our items from the trade.
+func (s *Session) RemoveItem(item *Item) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot, ok := s.me.find(item)
+
+	if !ok {
+		return ErrNotInTrade
+	}
+
+	return s.action(func() error {
+		if err := s.trade.RemoveItem(slot, item); err != nil {
+			return err
+		}
+
+		delete(s.me.items, slot)
+		s.unready()
+
+		return nil
+	})
+}
+
+// SetCurrency sets the amount of one of our currencies in the trade.
+func (s *Session) SetCurrency(currency *Currency, amount uint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.action(func() error {
+		if err := s.trade.SetCurrency(amount, currency); err != nil {
+			return err
+		}
+
+		if amount == 0 {
+			delete(s.me.currency, *currency)
+		} else {
+			s.me.currency[*currency] = uint64(amount)
+		}
+
+		s.unready()
+
+		return nil
+	})
+}
+
+// Chat sends a message to the trade chat.
+func (s *Session) Chat(message string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.action(func() error {
+		return s.trade.Chat(message)
+	})
+}
+
+// SetReady sets whether we are ready to confirm the trade with its current items.
+func (s *Session) SetReady(ready bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.action(func() error {
+		if err := s.trade.SetReady(ready); err != nil {
+			return err
+		}
+
+		s.trade.MeReady = ready
+
+		return nil
+	})
+}
+
+// Confirm confirms the trade. Both sides must be ready, otherwise ErrNotReady is returned.
+func (s *Session) Confirm() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.trade.MeReady || !s.trade.ThemReady {
+		return ErrNotReady
+	}
+
+	return s.action(func() error {
+		return s.trade.Confirm()
+	})
+}
+
+// Cancel cancels the trade. The session ends after the EndEvent is received.
+func (s *Session) Cancel() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.action(func() error {
+		return s.trade.Cancel()
+	})
+}
@@ -0,0 +1,206 @@
+package trade
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+	"github.com/13k/go-steam/economy/trade/api"
+	"github.com/13k/go-steam/steamid"
+)
+
+const partner steamid.SteamID = 76561197960287930
+
+func TestSession(t *testing.T) {
+	server := steamtest.NewServer()
+	defer server.Close()
+
+	client, err := api.NewWithBaseURL(server.URL, steamtest.SessionID, "login", "secure", partner)
+
+	if err != nil {
+		t.Fatalf("NewWithBaseURL: %v", err)
+	}
+
+	session := NewSession(&Trade{ThemID: partner, lastPoll: time.Unix(0, 0), api: client})
+	defer session.Stop()
+
+	select {
+	case event := <-session.Events():
+		added, ok := event.(*ItemAddedEvent)
+
+		if !ok || added.Item.AssetID != 2001 {
+			t.Fatalf("expected ItemAddedEvent for asset 2001, got %#v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for first poll")
+	}
+
+	if items := session.Them().Items; len(items) != 1 || items[0].AssetID != 2001 {
+		t.Errorf("unexpected partner items %+v", items)
+	}
+
+	if err := session.Confirm(); err != ErrNotReady {
+		t.Errorf("expected ErrNotReady, got %v", err)
+	}
+
+	item := &Item{AppID: 440, ContextID: 2, AssetID: 1001}
+
+	if err := session.AddItem(item); err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+
+	if err := session.AddItem(item); err != ErrInTrade {
+		t.Errorf("expected ErrInTrade, got %v", err)
+	}
+
+	if items := session.Me().Items; len(items) != 1 || *items[0] != *item {
+		t.Errorf("unexpected items %+v", items)
+	}
+
+	if err := session.RemoveItem(item); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+
+	if err := session.RemoveItem(item); err != ErrNotInTrade {
+		t.Errorf("expected ErrNotInTrade, got %v", err)
+	}
+}
+
+func TestSessionEmitStopped(t *testing.T) {
+	session := &Session{events: make(chan interface{}), stop: make(chan struct{})}
+	session.Stop()
+
+	done := make(chan bool)
+
+	go func() { done <- session.emit(&EndEvent{}) }()
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("expected emit to give up on a stopped session")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("emit blocked on a stopped session")
+	}
+}
+
+func TestSessionReadiness(t *testing.T) {
+	item := &Item{AppID: 440, ContextID: 2, AssetID: 2001}
+	addEvent := &api.Event{SteamID: partner, Action: api.ActionAddItem, AppID: 440, ContextID: 2, AssetID: 2001}
+	readyEvent := &api.Event{SteamID: partner, Action: api.ActionReady}
+
+	testCases := []struct {
+		Name      string
+		Status    *api.Result
+		ThemReady bool
+		MeReady   bool
+		Items     int
+	}{
+		{
+			Name:      "ready after items",
+			Status:    &api.Result{Success: true, Events: api.EventList{0: addEvent, 1: readyEvent}},
+			ThemReady: true,
+			Items:     1,
+		},
+		{
+			Name:   "items after ready",
+			Status: &api.Result{Success: true, Events: api.EventList{0: readyEvent, 1: addEvent}},
+			Items:  1,
+		},
+		{
+			Name:    "unready",
+			Status:  &api.Result{Success: true, Events: api.EventList{0: {SteamID: partner, Action: api.ActionUnready}}},
+			MeReady: true,
+		},
+		{
+			Name: "new version",
+			Status: &api.Result{
+				Success:    true,
+				NewVersion: true,
+				Version:    2,
+				Me:         api.User{Ready: true},
+				Them: api.User{
+					Ready:  true,
+					Assets: api.AssetList{{AppID: 440, ContextID: 2, AssetID: 2001, Amount: 1}},
+				},
+				Events: api.EventList{0: readyEvent, 1: addEvent},
+			},
+			ThemReady: true,
+			MeReady:   true,
+			Items:     1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		session := &Session{
+			trade: &Trade{ThemID: partner, MeReady: true, api: &api.Client{}},
+			me:    newSessionSide(),
+			them:  newSessionSide(),
+		}
+
+		if err := session.trade.onStatus(testCase.Status); err != nil {
+			t.Fatalf("%s: onStatus: %v", testCase.Name, err)
+		}
+
+		if _, err := session.poll(); err != nil {
+			t.Fatalf("%s: poll: %v", testCase.Name, err)
+		}
+
+		if me, them := session.Me(), session.Them(); me.Ready != testCase.MeReady || them.Ready != testCase.ThemReady {
+			t.Errorf("%s: expected ready %v/%v, got %v/%v",
+				testCase.Name, testCase.MeReady, testCase.ThemReady, me.Ready, them.Ready)
+		}
+
+		if items := session.Them().Items; len(items) != testCase.Items || (len(items) == 1 && *items[0] != *item) {
+			t.Errorf("%s: unexpected partner items %+v", testCase.Name, items)
+		}
+	}
+}
+
+func TestSessionPollUnlocked(t *testing.T) {
+	polling := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case polling <- struct{}{}:
+		default:
+		}
+
+		<-release
+		_, _ = w.Write([]byte(`{"success": true, "trade_status": 0}`))
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	client, err := api.NewWithBaseURL(server.URL, steamtest.SessionID, "login", "secure", partner)
+
+	if err != nil {
+		t.Fatalf("NewWithBaseURL: %v", err)
+	}
+
+	session := NewSession(&Trade{ThemID: partner, lastPoll: time.Unix(0, 0), api: client})
+	defer session.Stop()
+
+	select {
+	case <-polling:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for first poll")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		session.Me()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Me blocked by a poll in progress")
+	}
+}
@@ -2,6 +2,8 @@ package trade
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/13k/go-steam/economy/trade/api"
@@ -10,6 +12,9 @@ import (
 
 const pollTimeout = time.Second
 
+// ErrUnsuccessful is returned when Steam rejects a request, usually because the trade changed.
+var ErrUnsuccessful = errors.New("trade: returned status not successful")
+
 type Trade struct {
 	ThemID    steamid.SteamID
 	MeReady   bool
@@ -18,6 +23,8 @@ type Trade struct {
 	lastPoll     time.Time
 	queuedEvents []interface{}
 	api          *api.Client
+	// last status with a new version, which has the full state of the trade
+	synced *api.Result
 }
 
 func New(sessionID, steamLogin, steamLoginSecure string, other steamid.SteamID) (*Trade, error) {
@@ -49,13 +56,14 @@ func (t *Trade) Events() []interface{} {
 
 func (t *Trade) onStatus(status *api.Result) error {
 	if !status.Success {
-		return errors.New("trade: returned status not successful! error message: " + status.Error)
+		return fmt.Errorf("%w! error message: %s", ErrUnsuccessful, status.Error)
 	}
 
 	if status.NewVersion {
 		t.api.Version = status.Version
 		t.MeReady = bool(status.Me.Ready)
 		t.ThemReady = bool(status.Them.Ready)
+		t.synced = status
 	}
 
 	switch status.TradeStatus {
@@ -82,9 +90,20 @@ func (t *Trade) updateEvents(events api.EventList) {
 		return
 	}
 
+	positions := make([]int, 0, len(events))
+
+	for i := range events {
+		positions = append(positions, int(i))
+	}
+
+	// events are keyed by log position and must be handled in order
+	sort.Ints(positions)
+
 	var lastLogPos uint
 
-	for i, event := range events {
+	for _, pos := range positions {
+		i, event := uint(pos), events[uint(pos)]
+
 		if i < t.api.LogPos {
 			continue
 		}
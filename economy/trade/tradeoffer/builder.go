@@ -0,0 +1,302 @@
+package tradeoffer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/13k/go-steam/economy/inventory"
+	"github.com/13k/go-steam/steamid"
+)
+
+// Errors returned by Builder when adding items.
+var (
+	ErrItemNotFound    = errors.New("tradeoffer: item not found in inventory")
+	ErrItemNotTradable = errors.New("tradeoffer: item is not tradable")
+	ErrInvalidAmount   = errors.New("tradeoffer: invalid item amount")
+	ErrDuplicateItem   = errors.New("tradeoffer: item already added")
+)
+
+// Builder builds a trade offer, validating every item against the inventory it's added from.
+//
+// Send the offer with Client.Send.
+type Builder struct {
+	// Trade offer access token, required when the partner is not a friend.
+	AccessToken string
+	// ID of the received offer this offer is a counter for.
+	CounteredOfferID uint64
+	Message          string
+
+	partner steamid.SteamID
+	me      builderSide
+	them    builderSide
+}
+
+type builderSide struct {
+	items    []TradeItem
+	currency []TradeItem
+}
+
+// NewBuilder creates a Builder for an offer to the given user.
+func NewBuilder(partner steamid.SteamID) *Builder {
+	return &Builder{partner: partner}
+}
+
+// NewBuilderFromURL creates a Builder for an offer to the owner of the given trade URL.
+func NewBuilderFromURL(u *TradeURL) *Builder {
+	return &Builder{partner: u.Partner, AccessToken: u.Token}
+}
+
+// NewCounterBuilder creates a Builder for a counter offer to the given received offer, starting
+// with the items of the offer. Missing items, which are no longer in their inventory, are left out.
+func NewCounterBuilder(offer *Offer) *Builder {
+	b := &Builder{partner: offer.OtherSteamID, CounteredOfferID: offer.TradeOfferID}
+	b.me.addAssets(offer.ToGive)
+	b.them.addAssets(offer.ToReceive)
+
+	return b
+}
+
+// Partner returns the SteamID of the user the offer is for.
+func (b *Builder) Partner() steamid.SteamID {
+	return b.partner
+}
+
+// MyItems returns the items added from our inventories.
+func (b *Builder) MyItems() []TradeItem {
+	return b.me.all()
+}
+
+// TheirItems returns the items added from the partner's inventories.
+func (b *Builder) TheirItems() []TradeItem {
+	return b.them.all()
+}
+
+// AddMyItem adds amount of the item with the given asset ID from our inventory of the given
+// app/context. Use an amount of 0 to add the whole stack.
+func (b *Builder) AddMyItem(inv *inventory.Inventory, appID uint32, contextID uint64, assetID, amount uint64) error {
+	return b.me.addItem(inv, appID, contextID, assetID, amount)
+}
+
+// AddTheirItem is like AddMyItem, but for the partner's inventory.
+func (b *Builder) AddTheirItem(inv *inventory.Inventory, appID uint32, contextID uint64, assetID, amount uint64) error {
+	return b.them.addItem(inv, appID, contextID, assetID, amount)
+}
+
+// AddMyItemsByName adds count tradable items with the given market hash name from our inventory
+// of the given app/context. Items already in the offer are skipped.
+//
+// Returns ErrItemNotFound if there aren't enough items, in which case none are added.
+func (b *Builder) AddMyItemsByName(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	name string,
+	count int,
+) error {
+	return b.me.addItemsByName(inv, appID, contextID, name, count)
+}
+
+// AddTheirItemsByName is like AddMyItemsByName, but for the partner's inventory.
+func (b *Builder) AddTheirItemsByName(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	name string,
+	count int,
+) error {
+	return b.them.addItemsByName(inv, appID, contextID, name, count)
+}
+
+// AddMyCurrency adds amount of the currency with the given ID from our inventory of the given
+// app/context.
+func (b *Builder) AddMyCurrency(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	currencyID, amount uint64,
+) error {
+	return b.me.addCurrency(inv, appID, contextID, currencyID, amount)
+}
+
+// AddTheirCurrency is like AddMyCurrency, but for the partner's inventory.
+func (b *Builder) AddTheirCurrency(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	currencyID, amount uint64,
+) error {
+	return b.them.addCurrency(inv, appID, contextID, currencyID, amount)
+}
+
+func (s *builderSide) addItem(inv *inventory.Inventory, appID uint32, contextID uint64, assetID, amount uint64) error {
+	item, err := inv.Items.Get(assetID)
+
+	if err != nil {
+		return fmt.Errorf("%w: asset %d", ErrItemNotFound, assetID)
+	}
+
+	if err := checkTradable(inv, item); err != nil {
+		return err
+	}
+
+	if amount == 0 {
+		amount = item.Amount
+	}
+
+	if amount == 0 || amount > item.Amount {
+		return fmt.Errorf("%w: %d of asset %d with amount %d", ErrInvalidAmount, amount, assetID, item.Amount)
+	}
+
+	if s.hasAsset(appID, contextID, assetID) {
+		return fmt.Errorf("%w: asset %d", ErrDuplicateItem, assetID)
+	}
+
+	s.items = append(s.items, TradeItem{
+		AppID:     appID,
+		ContextID: contextID,
+		Amount:    amount,
+		AssetID:   assetID,
+	})
+
+	return nil
+}
+
+func (s *builderSide) addItemsByName(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	name string,
+	count int,
+) error {
+	if count <= 0 {
+		return fmt.Errorf("%w: count %d", ErrInvalidAmount, count)
+	}
+
+	var found []*inventory.Item
+
+	for _, item := range inv.Items {
+		if len(found) == count {
+			break
+		}
+
+		desc, err := inv.Descriptions.Get(item.ClassID, item.InstanceID)
+
+		if err != nil || desc.MarketHashName != name || !bool(desc.Tradable) {
+			continue
+		}
+
+		if s.hasAsset(appID, contextID, item.ID) {
+			continue
+		}
+
+		found = append(found, item)
+	}
+
+	if len(found) < count {
+		return fmt.Errorf("%w: %d of %d tradable %q", ErrItemNotFound, len(found), count, name)
+	}
+
+	for _, item := range found {
+		s.items = append(s.items, TradeItem{
+			AppID:     appID,
+			ContextID: contextID,
+			Amount:    item.Amount,
+			AssetID:   item.ID,
+		})
+	}
+
+	return nil
+}
+
+func (s *builderSide) addCurrency(
+	inv *inventory.Inventory,
+	appID uint32,
+	contextID uint64,
+	currencyID, amount uint64,
+) error {
+	found := false
+
+	for _, currency := range inv.Currencies {
+		if currency.ID == currencyID {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: currency %d", ErrItemNotFound, currencyID)
+	}
+
+	if amount == 0 {
+		return fmt.Errorf("%w: 0 of currency %d", ErrInvalidAmount, currencyID)
+	}
+
+	for _, item := range s.currency {
+		if item.AppID == appID && item.ContextID == contextID && item.CurrencyID == currencyID {
+			return fmt.Errorf("%w: currency %d", ErrDuplicateItem, currencyID)
+		}
+	}
+
+	s.currency = append(s.currency, TradeItem{
+		AppID:      appID,
+		ContextID:  contextID,
+		Amount:     amount,
+		CurrencyID: currencyID,
+	})
+
+	return nil
+}
+
+// addAssets adds the assets of an existing offer, which were validated when it was created.
+func (s *builderSide) addAssets(assets []*Asset) {
+	for _, asset := range assets {
+		if asset.Missing {
+			continue
+		}
+
+		item := TradeItem{
+			AppID:      asset.AppID,
+			ContextID:  asset.ContextID,
+			Amount:     asset.Amount,
+			AssetID:    asset.AssetID,
+			CurrencyID: asset.CurrencyID,
+		}
+
+		if asset.CurrencyID != 0 {
+			s.currency = append(s.currency, item)
+		} else {
+			s.items = append(s.items, item)
+		}
+	}
+}
+
+func (s *builderSide) all() []TradeItem {
+	items := make([]TradeItem, 0, len(s.items)+len(s.currency))
+	items = append(items, s.items...)
+
+	return append(items, s.currency...)
+}
+
+func (s *builderSide) hasAsset(appID uint32, contextID uint64, assetID uint64) bool {
+	for _, item := range s.items {
+		if item.AppID == appID && item.ContextID == contextID && item.AssetID == assetID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkTradable(inv *inventory.Inventory, item *inventory.Item) error {
+	desc, err := inv.Descriptions.Get(item.ClassID, item.InstanceID)
+
+	if err != nil {
+		return fmt.Errorf("%w: description of asset %d", ErrItemNotFound, item.ID)
+	}
+
+	if !desc.Tradable {
+		return fmt.Errorf("%w: asset %d (%s)", ErrItemNotTradable, item.ID, desc.MarketHashName)
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package tradeoffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+	"github.com/13k/go-steam/economy/inventory"
+)
+
+const (
+	keyName   = "Mann Co. Supply Crate Key"
+	metalName = "Refined Metal"
+)
+
+// testInventories fetches the inventories of steamtest, adding a currency with ID 5 to ours.
+func testInventories(t *testing.T) (my, their *inventory.Inventory) {
+	client, _ := newTestClient(t)
+
+	my, err := client.GetOwnInventory(2, 440)
+
+	if err != nil {
+		t.Fatalf("GetOwnInventory: %v", err)
+	}
+
+	their, err = client.GetPartnerInventory(partner, 2, 440, 0)
+
+	if err != nil {
+		t.Fatalf("GetPartnerInventory: %v", err)
+	}
+
+	my.Currencies["5"] = &inventory.Currency{ID: 5, ClassID: 105, IsCurrency: true}
+
+	return my, their
+}
+
+func TestBuilder(t *testing.T) {
+	my, their := testInventories(t)
+
+	testCases := []struct {
+		Name       string
+		Build      func(b *Builder) error
+		Err        error
+		MyItems    []TradeItem
+		TheirItems []TradeItem
+	}{
+		{
+			Name: "items",
+			Build: func(b *Builder) error {
+				if err := b.AddMyItem(my, 440, 2, 1001, 0); err != nil {
+					return err
+				}
+
+				return b.AddTheirItem(their, 440, 2, 2001, 1)
+			},
+			MyItems:    []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 1001}},
+			TheirItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 2001}},
+		},
+		{
+			Name:  "unknown asset",
+			Build: func(b *Builder) error { return b.AddMyItem(my, 440, 2, 9999, 0) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name:  "other side's asset",
+			Build: func(b *Builder) error { return b.AddMyItem(my, 440, 2, 2001, 0) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name:  "not tradable",
+			Build: func(b *Builder) error { return b.AddMyItem(my, 440, 2, 1002, 0) },
+			Err:   ErrItemNotTradable,
+		},
+		{
+			Name:  "amount above stack",
+			Build: func(b *Builder) error { return b.AddMyItem(my, 440, 2, 1001, 2) },
+			Err:   ErrInvalidAmount,
+		},
+		{
+			Name: "duplicate asset",
+			Build: func(b *Builder) error {
+				if err := b.AddTheirItem(their, 440, 2, 2001, 0); err != nil {
+					return err
+				}
+
+				return b.AddTheirItem(their, 440, 2, 2001, 0)
+			},
+			Err:        ErrDuplicateItem,
+			TheirItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 2001}},
+		},
+		{
+			Name:    "by name",
+			Build:   func(b *Builder) error { return b.AddMyItemsByName(my, 440, 2, keyName, 1) },
+			MyItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 1001}},
+		},
+		{
+			Name:       "by name from their inventory",
+			Build:      func(b *Builder) error { return b.AddTheirItemsByName(their, 440, 2, metalName, 1) },
+			TheirItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 2001}},
+		},
+		{
+			Name:  "by name count above matches",
+			Build: func(b *Builder) error { return b.AddMyItemsByName(my, 440, 2, keyName, 2) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name:  "by name zero count",
+			Build: func(b *Builder) error { return b.AddMyItemsByName(my, 440, 2, keyName, 0) },
+			Err:   ErrInvalidAmount,
+		},
+		{
+			Name:  "by name not tradable",
+			Build: func(b *Builder) error { return b.AddMyItemsByName(my, 440, 2, "Gift-Stuffed Stocking", 1) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name:  "by name other side's item",
+			Build: func(b *Builder) error { return b.AddMyItemsByName(my, 440, 2, metalName, 1) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name: "by name skips added items",
+			Build: func(b *Builder) error {
+				if err := b.AddMyItem(my, 440, 2, 1001, 0); err != nil {
+					return err
+				}
+
+				return b.AddMyItemsByName(my, 440, 2, keyName, 1)
+			},
+			Err:     ErrItemNotFound,
+			MyItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 1001}},
+		},
+		{
+			Name:    "currency",
+			Build:   func(b *Builder) error { return b.AddMyCurrency(my, 440, 2, 5, 250) },
+			MyItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 250, CurrencyID: 5}},
+		},
+		{
+			Name: "currency after items",
+			Build: func(b *Builder) error {
+				if err := b.AddMyCurrency(my, 440, 2, 5, 1); err != nil {
+					return err
+				}
+
+				return b.AddMyItem(my, 440, 2, 1001, 0)
+			},
+			MyItems: []TradeItem{
+				{AppID: 440, ContextID: 2, Amount: 1, AssetID: 1001},
+				{AppID: 440, ContextID: 2, Amount: 1, CurrencyID: 5},
+			},
+		},
+		{
+			Name:  "unknown currency",
+			Build: func(b *Builder) error { return b.AddTheirCurrency(their, 440, 2, 5, 1) },
+			Err:   ErrItemNotFound,
+		},
+		{
+			Name:  "zero currency",
+			Build: func(b *Builder) error { return b.AddMyCurrency(my, 440, 2, 5, 0) },
+			Err:   ErrInvalidAmount,
+		},
+		{
+			Name: "duplicate currency",
+			Build: func(b *Builder) error {
+				if err := b.AddMyCurrency(my, 440, 2, 5, 1); err != nil {
+					return err
+				}
+
+				return b.AddMyCurrency(my, 440, 2, 5, 2)
+			},
+			Err:     ErrDuplicateItem,
+			MyItems: []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, CurrencyID: 5}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		b := NewBuilder(partner)
+		err := testCase.Build(b)
+
+		if testCase.Err == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.Name, err)
+		} else if !errors.Is(err, testCase.Err) {
+			t.Errorf("%s: expected %v, got %v", testCase.Name, testCase.Err, err)
+		}
+
+		if items := b.MyItems(); !sameItems(items, testCase.MyItems) {
+			t.Errorf("%s: expected my items %+v, got %+v", testCase.Name, testCase.MyItems, items)
+		}
+
+		if items := b.TheirItems(); !sameItems(items, testCase.TheirItems) {
+			t.Errorf("%s: expected their items %+v, got %+v", testCase.Name, testCase.TheirItems, items)
+		}
+	}
+}
+
+func sameItems(a, b []TradeItem) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+func TestNewCounterBuilder(t *testing.T) {
+	client, server := newTestClient(t)
+
+	offerID := server.ReceiveOffer(partner, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "1001", ClassID: "101", InstanceID: "0", Amount: "1"},
+	}, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+		{AppID: 440, ContextID: "2", AssetID: "2002", ClassID: "201", InstanceID: "11040547", Amount: "1", Missing: true},
+	})
+
+	result, err := client.GetOffer(offerID)
+
+	if err != nil {
+		t.Fatalf("GetOffer: %v", err)
+	}
+
+	b := NewCounterBuilder(result.Offer)
+
+	if b.Partner() != partner || b.CounteredOfferID != offerID {
+		t.Errorf("unexpected counter builder partner %d, countered %d", b.Partner(), b.CounteredOfferID)
+	}
+
+	toGive := []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 1001}}
+	toReceive := []TradeItem{{AppID: 440, ContextID: 2, Amount: 1, AssetID: 2001}}
+
+	if items := b.MyItems(); !sameItems(items, toGive) {
+		t.Errorf("expected the items to give, got %+v", items)
+	}
+
+	if items := b.TheirItems(); !sameItems(items, toReceive) {
+		t.Errorf("expected the items to receive without missing ones, got %+v", items)
+	}
+
+	// the carried over items count as added
+	_, their := testInventories(t)
+
+	if err := b.AddTheirItem(their, 440, 2, 2001, 0); !errors.Is(err, ErrDuplicateItem) {
+		t.Errorf("expected %v, got %v", ErrDuplicateItem, err)
+	}
+}
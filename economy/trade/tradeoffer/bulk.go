@@ -0,0 +1,163 @@
+package tradeoffer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultBulkConcurrency is the number of concurrent requests of bulk operations when a
+// non-positive concurrency is given.
+const DefaultBulkConcurrency = 4
+
+// BulkResult is the result of a bulk operation for one offer.
+type BulkResult struct {
+	OfferID uint64
+	// nil on success
+	Err error
+}
+
+// BulkReport is the report of a bulk operation, with one result per offer.
+type BulkReport struct {
+	Results []*BulkResult
+}
+
+// Succeeded returns the IDs of the offers the operation succeeded for.
+func (r *BulkReport) Succeeded() []uint64 {
+	var ids []uint64
+
+	for _, result := range r.Results {
+		if result.Err == nil {
+			ids = append(ids, result.OfferID)
+		}
+	}
+
+	return ids
+}
+
+// Failed returns the results of the offers the operation failed for.
+func (r *BulkReport) Failed() []*BulkResult {
+	var failed []*BulkResult
+
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// CancelSentOlderThan cancels all sent offers created more than age ago that are active or still
+// need a confirmation.
+//
+// An error is returned only if the offers can't be fetched. Errors canceling each offer are in the
+// report.
+//
+// Canceling is a Web API request, so set Client.APILimiter to avoid being throttled.
+func (c *Client) CancelSentOlderThan(ctx context.Context, age time.Duration, concurrency int) (*BulkReport, error) {
+	created := time.Now().Add(-age).Unix()
+
+	return c.bulkOffers(ctx, true, concurrency, func(offer *Offer) bool {
+		return int64(offer.TimeCreated) < created
+	}, c.CancelContext)
+}
+
+// DeclineReceivedMatching declines all active received offers for which match returns true.
+//
+// An error is returned only if the offers can't be fetched. Errors declining each offer are in the
+// report.
+//
+// Declining is a Web API request, so set Client.APILimiter to avoid being throttled.
+func (c *Client) DeclineReceivedMatching(
+	ctx context.Context,
+	match func(*Offer) bool,
+	concurrency int,
+) (*BulkReport, error) {
+	return c.bulkOffers(ctx, false, concurrency, match, c.DeclineContext)
+}
+
+// AcceptAll accepts the given received offers.
+//
+// Accepting is a steamcommunity.com request, so set Client.CommunityLimiter to avoid being throttled.
+func (c *Client) AcceptAll(ctx context.Context, offerIDs []uint64, concurrency int) *BulkReport {
+	return c.bulk(ctx, offerIDs, concurrency, c.AcceptContext)
+}
+
+func (c *Client) bulkOffers(
+	ctx context.Context,
+	sent bool,
+	concurrency int,
+	match func(*Offer) bool,
+	op func(context.Context, uint64) error,
+) (*BulkReport, error) {
+	result, err := c.GetOffersContext(ctx, sent, !sent, false, true, false, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	offers := result.Received
+
+	if sent {
+		offers = result.Sent
+	}
+
+	var ids []uint64
+
+	for _, offer := range offers {
+		if isPendingState(offer.State, sent) && match(offer) {
+			ids = append(ids, offer.TradeOfferID)
+		}
+	}
+
+	return c.bulk(ctx, ids, concurrency, op), nil
+}
+
+// isPendingState reports whether offers in the given state can still be canceled or declined. Sent
+// offers can also be canceled before being confirmed.
+func isPendingState(state State, sent bool) bool {
+	return state == StateActive || sent && state == StateCreatedNeedsConfirmation
+}
+
+// bulk runs op for every offer with at most concurrency concurrent calls. Offers not processed
+// before ctx is done fail with the context error.
+func (c *Client) bulk(
+	ctx context.Context,
+	ids []uint64,
+	concurrency int,
+	op func(context.Context, uint64) error,
+) *BulkReport {
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	report := &BulkReport{Results: make([]*BulkResult, len(ids))}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		report.Results[i] = &BulkResult{OfferID: id}
+
+		select {
+		case <-ctx.Done():
+			report.Results[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func(result *BulkResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result.Err = op(ctx, result.OfferID)
+		}(report.Results[i])
+	}
+
+	wg.Wait()
+
+	return report
+}
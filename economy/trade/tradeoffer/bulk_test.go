@@ -0,0 +1,146 @@
+package tradeoffer
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+)
+
+func TestBulk(t *testing.T) {
+	client, server := newTestClient(t)
+	client.CommunityLimiter = NewRateLimiter(1000, 2)
+
+	var sent []uint64
+
+	for i := 0; i < 3; i++ {
+		id, err := client.Create(partner, "", []TradeItem{{AppID: 440, ContextID: 2, AssetID: 1001, Amount: 1}}, nil, 0, "")
+
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		sent = append(sent, id)
+	}
+
+	// not confirmed yet, but can be canceled
+	server.SetOfferState(sent[2], steamtest.StateCreatedNeedsConfirmation)
+
+	// the fake server clock is in the past, so all offers are old
+	report, err := client.CancelSentOlderThan(context.Background(), time.Hour, 2)
+
+	if err != nil {
+		t.Fatalf("CancelSentOlderThan: %v", err)
+	}
+
+	if len(report.Succeeded()) != len(sent) || len(report.Failed()) != 0 {
+		t.Errorf("expected %d canceled offers, got %+v", len(sent), report.Results)
+	}
+
+	for _, id := range sent {
+		if state := server.Offer(id).State; state != steamtest.StateCanceled {
+			t.Errorf("expected offer %d to be canceled, got state %d", id, state)
+		}
+	}
+
+	spam := server.ReceiveOffer(partner, nil, nil)
+	gift := server.ReceiveOffer(partner, nil, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+	})
+
+	report, err = client.DeclineReceivedMatching(context.Background(), func(offer *Offer) bool {
+		return len(offer.ToReceive) == 0
+	}, 0)
+
+	if err != nil {
+		t.Fatalf("DeclineReceivedMatching: %v", err)
+	}
+
+	if ids := report.Succeeded(); len(ids) != 1 || ids[0] != spam {
+		t.Errorf("expected offer %d to be declined, got %+v", spam, report.Results)
+	}
+
+	report = client.AcceptAll(context.Background(), []uint64{gift, spam}, 2)
+
+	if len(report.Results) != 2 || report.Results[0].Err != nil || report.Results[1].Err == nil {
+		t.Errorf("expected only offer %d to be accepted, got %+v", gift, report.Results)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expected 3 requests at 100/s to take at least 15ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := NewRateLimiter(0.001, 1).Wait(ctx); err != nil {
+		t.Errorf("expected burst request to be allowed, got %v", err)
+	}
+
+	limited := NewRateLimiter(0.001, 1)
+	_ = limited.Wait(context.Background())
+
+	if err := limited.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		unlimited := NewRateLimiter(rate, 1)
+
+		for i := 0; i < 3; i++ {
+			if err := unlimited.Wait(ctx); err != nil {
+				t.Errorf("rate %v: expected requests not to be limited, got %v", rate, err)
+			}
+		}
+	}
+}
+
+func TestClientLimiters(t *testing.T) {
+	var requests int32
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+
+	client.APILimiter = NewRateLimiter(0.001, 1)
+	client.CommunityLimiter = NewRateLimiter(0.001, 1)
+
+	if err := client.Decline(1); err != nil {
+		t.Fatalf("Decline: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.DeclineContext(ctx, 2); err != context.Canceled {
+		t.Errorf("expected the Web API request to be limited, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+
+	// steamcommunity.com requests have their own limit
+	if err := client.wait(ctx, client.CommunityURL+"/tradeoffer/1/"); err != nil {
+		t.Errorf("expected burst request to be allowed, got %v", err)
+	}
+
+	if err := client.wait(ctx, client.CommunityURL+"/tradeoffer/1/"); err != context.Canceled {
+		t.Errorf("expected the steamcommunity.com request to be limited, got %v", err)
+	}
+}
@@ -1,12 +1,15 @@
 package tradeoffer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/13k/go-steam/community"
@@ -17,19 +20,57 @@ import (
 
 type APIKey string
 
-const apiURL = "https://api.steampowered.com/IEconService/%s/v%d"
+// Default base URLs of Client.
+const (
+	DefaultCommunityURL = "https://steamcommunity.com"
+	DefaultAPIURL       = "https://api.steampowered.com"
+)
 
+// Client is a trade offer client.
+//
+// Every method has a variant that accepts a context.Context, used to cancel the requests and the
+// retries. The methods without it use context.Background().
 type Client struct {
+	// Retry policy applied to failed requests. Retries are disabled if nil.
+	Retry *RetryPolicy
+	// Base URLs of steamcommunity.com and the Web API, overridable for testing.
+	CommunityURL string
+	APIURL       string
+	// Rate limiter of steamcommunity.com requests: sending and accepting offers, inventories,
+	// receipts, escrow durations and the trade URL. Requests are not limited if nil.
+	CommunityLimiter *RateLimiter
+	// Rate limiter of Web API requests: fetching, declining and canceling offers, and item
+	// descriptions. Requests are not limited if nil.
+	APILimiter *RateLimiter
+	// Cache of item descriptions joined into the assets of fetched offers. It can be replaced by a
+	// cache shared with other clients.
+	Descriptions *DescriptionCache
+
 	client    *http.Client
 	key       APIKey
 	sessionID string
 }
 
+// NewClient creates a Client that uses an http.Client with a 30 seconds timeout.
 func NewClient(key APIKey, sessionID, steamLogin, steamLoginSecure string) (*Client, error) {
+	return NewClientWithHTTPClient(&http.Client{Timeout: 30 * time.Second}, key, sessionID, steamLogin, steamLoginSecure)
+}
+
+// NewClientWithHTTPClient creates a Client that sends requests with the given http.Client, which
+// can be configured with proxies, timeouts or a custom transport. The session cookies are added to
+// its cookie jar, which is created if nil.
+func NewClientWithHTTPClient(
+	client *http.Client,
+	key APIKey,
+	sessionID, steamLogin, steamLoginSecure string,
+) (*Client, error) {
 	c := &Client{
-		client:    &http.Client{},
-		key:       key,
-		sessionID: sessionID,
+		CommunityURL: DefaultCommunityURL,
+		APIURL:       DefaultAPIURL,
+		Descriptions: NewDescriptionCache(),
+		client:       client,
+		key:          key,
+		sessionID:    sessionID,
 	}
 
 	if err := community.SetCookies(c.client, sessionID, steamLogin, steamLoginSecure); err != nil {
@@ -39,12 +80,71 @@ func NewClient(key APIKey, sessionID, steamLogin, steamLoginSecure string) (*Cli
 	return c, nil
 }
 
-func (c *Client) GetOffer(offerID uint64) (*Result, error) {
-	resp, err := c.client.Get(fmt.Sprintf(apiURL, "GetTradeOffer", 1) + "?" + netutil.ToURLValues(map[string]string{
-		"key":          string(c.key),
-		"tradeofferid": strconv.FormatUint(offerID, 10),
-		"language":     "en_us",
-	}).Encode())
+func (c *Client) apiURL(method string, version uint) string {
+	return fmt.Sprintf("%s/IEconService/%s/v%d", c.APIURL, method, version)
+}
+
+// retry calls op with the retry policy, if any.
+func (c *Client) retry(ctx context.Context, op func() error) error {
+	if c.Retry == nil {
+		return op()
+	}
+
+	return c.Retry.Do(ctx, op)
+}
+
+// wait waits for the rate limiter of the host of u, if any.
+func (c *Client) wait(ctx context.Context, u string) error {
+	var limiter *RateLimiter
+
+	switch {
+	case strings.HasPrefix(u, c.CommunityURL):
+		limiter = c.CommunityLimiter
+	case strings.HasPrefix(u, c.APIURL):
+		limiter = c.APILimiter
+	}
+
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.Wait(ctx)
+}
+
+func (c *Client) get(ctx context.Context, u string) (*http.Response, error) {
+	if err := c.wait(ctx, u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.Do(req)
+}
+
+func (c *Client) postForm(ctx context.Context, u, referer string, data url.Values) (*http.Response, error) {
+	if err := c.wait(ctx, u); err != nil {
+		return nil, err
+	}
+
+	req, err := netutil.NewPostForm(u, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if referer != "" {
+		req.Header.Add("Referer", referer)
+	}
+
+	return c.client.Do(req.WithContext(ctx))
+}
+
+func (c *Client) getBody(ctx context.Context, u string) ([]byte, error) {
+	resp, err := c.get(ctx, u)
 
 	if err != nil {
 		return nil, err
@@ -52,19 +152,53 @@ func (c *Client) GetOffer(offerID uint64) (*Result, error) {
 
 	defer resp.Body.Close()
 
-	t := &struct {
-		Response *Result
-	}{}
+	return ioutil.ReadAll(resp.Body)
+}
 
-	if err = json.NewDecoder(resp.Body).Decode(t); err != nil {
+func (c *Client) GetOffer(offerID uint64) (*Result, error) {
+	return c.GetOfferContext(context.Background(), offerID)
+}
+
+func (c *Client) GetOfferContext(ctx context.Context, offerID uint64) (*Result, error) {
+	// the descriptions returned with the offer spare a GetAssetClassInfo request per class
+	result, err := c.getOffer(ctx, offerID, true)
+
+	if err != nil {
 		return nil, err
 	}
 
-	if t.Response == nil || t.Response.Offer == nil {
+	result.DescriptionsError = c.enrich(ctx, []*Offer{result.Offer}, result.Descriptions, true)
+
+	return result, nil
+}
+
+func (c *Client) getOffer(ctx context.Context, offerID uint64, getDescriptions bool) (*Result, error) {
+	params := map[string]string{
+		"key":          string(c.key),
+		"tradeofferid": strconv.FormatUint(offerID, 10),
+	}
+
+	if getDescriptions {
+		params["get_descriptions"] = "1"
+		params["language"] = "en_us"
+	}
+
+	var result *Result
+
+	err := c.retry(ctx, func() error {
+		result = &Result{}
+		return c.getAPI(ctx, "GetTradeOffer", 1, params, result)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Offer == nil {
 		return nil, newSteamErrorf("steam returned empty offer result")
 	}
 
-	return t.Response, nil
+	return result, nil
 }
 
 func (c *Client) GetOffers(
@@ -74,6 +208,26 @@ func (c *Client) GetOffers(
 	activeOnly bool,
 	historicalOnly bool,
 	timeHistoricalCutoff uint32,
+) (*MultiResult, error) {
+	return c.GetOffersContext(
+		context.Background(),
+		getSent,
+		getReceived,
+		getDescriptions,
+		activeOnly,
+		historicalOnly,
+		timeHistoricalCutoff,
+	)
+}
+
+func (c *Client) GetOffersContext(
+	ctx context.Context,
+	getSent bool,
+	getReceived bool,
+	getDescriptions bool,
+	activeOnly bool,
+	historicalOnly bool,
+	timeHistoricalCutoff uint32,
 ) (*MultiResult, error) {
 	if !getSent && !getReceived {
 		return nil, errors.New("getSent and getReceived can't be both false")
@@ -108,27 +262,24 @@ func (c *Client) GetOffers(
 		params["time_historical_cutoff"] = strconv.FormatUint(uint64(timeHistoricalCutoff), 10)
 	}
 
-	resp, err := c.client.Get(fmt.Sprintf(apiURL, "GetTradeOffers", 1) + "?" + netutil.ToURLValues(params).Encode())
+	var result *MultiResult
+
+	err := c.retry(ctx, func() error {
+		result = &MultiResult{}
+		return c.getAPI(ctx, "GetTradeOffers", 1, params, result)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-
-	t := &struct {
-		Response *MultiResult
-	}{}
-
-	if err = json.NewDecoder(resp.Body).Decode(t); err != nil {
-		return nil, err
-	}
+	if getDescriptions {
+		offers := append(append([]*Offer{}, result.Sent...), result.Received...)
 
-	if t.Response == nil {
-		return nil, newSteamErrorf("steam returned empty offers result")
+		result.DescriptionsError = c.enrich(ctx, offers, result.Descriptions, true)
 	}
 
-	return t.Response, nil
+	return result, nil
 }
 
 // action is used by Decline and Cancel.
@@ -138,39 +289,43 @@ func (c *Client) GetOffers(
 //
 // It is also possible to implement Decline/Cancel using steamcommunity, which have more predictable
 // responses.
-func (c *Client) action(method string, version uint, offerID uint64) error {
+func (c *Client) action(ctx context.Context, method string, version uint, offerID uint64) error {
 	data := netutil.ToURLValues(map[string]string{
 		"key":          string(c.key),
 		"tradeofferid": strconv.FormatUint(offerID, 10),
 	})
 
-	req, err := netutil.NewPostForm(fmt.Sprintf(apiURL, method, version), data)
+	return c.retry(ctx, func() error {
+		resp, err := c.postForm(ctx, c.apiURL(method, version), "", data)
 
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.client.Do(req)
-
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			return err
+		}
 
-	defer resp.Body.Close()
+		defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("%s error: status code %d", method, resp.StatusCode)
-	}
+		if resp.StatusCode != 200 {
+			return &StatusError{Method: method, StatusCode: resp.StatusCode}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (c *Client) Decline(offerID uint64) error {
-	return c.action("DeclineTradeOffer", 1, offerID)
+	return c.DeclineContext(context.Background(), offerID)
+}
+
+func (c *Client) DeclineContext(ctx context.Context, offerID uint64) error {
+	return c.action(ctx, "DeclineTradeOffer", 1, offerID)
 }
 
 func (c *Client) Cancel(offerID uint64) error {
-	return c.action("CancelTradeOffer", 1, offerID)
+	return c.CancelContext(context.Background(), offerID)
+}
+
+func (c *Client) CancelContext(ctx context.Context, offerID uint64) error {
+	return c.action(ctx, "CancelTradeOffer", 1, offerID)
 }
 
 // Accept accepts received trade offer.
@@ -178,7 +333,11 @@ func (c *Client) Cancel(offerID uint64) error {
 // It is best to confirm that offer was actually accepted by calling GetOffer after Accept and
 // checking offer state.
 func (c *Client) Accept(offerID uint64) error {
-	baseurl := fmt.Sprintf("https://steamcommunity.com/tradeoffer/%d/", offerID)
+	return c.AcceptContext(context.Background(), offerID)
+}
+
+func (c *Client) AcceptContext(ctx context.Context, offerID uint64) error {
+	baseurl := fmt.Sprintf("%s/tradeoffer/%d/", c.CommunityURL, offerID)
 
 	data := netutil.ToURLValues(map[string]string{
 		"sessionid":    c.sessionID,
@@ -186,39 +345,33 @@ func (c *Client) Accept(offerID uint64) error {
 		"tradeofferid": strconv.FormatUint(offerID, 10),
 	})
 
-	req, err := netutil.NewPostForm(baseurl+"accept", data)
-
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Referer", baseurl)
-
-	resp, err := c.client.Do(req)
+	return c.retry(ctx, func() error {
+		resp, err := c.postForm(ctx, baseurl+"accept", baseurl, data)
 
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			return err
+		}
 
-	defer resp.Body.Close()
+		defer resp.Body.Close()
 
-	t := &struct {
-		StrError string `json:"strError"`
-	}{}
+		t := &struct {
+			StrError string `json:"strError"`
+		}{}
 
-	if err = json.NewDecoder(resp.Body).Decode(t); err != nil {
-		return err
-	}
+		if err = json.NewDecoder(resp.Body).Decode(t); err != nil {
+			return err
+		}
 
-	if t.StrError != "" {
-		return newSteamErrorf("accept error: %v\n", t.StrError)
-	}
+		if t.StrError != "" {
+			return newStrError("accept", t.StrError)
+		}
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("accept error: status code %d", resp.StatusCode)
-	}
+		if resp.StatusCode != 200 {
+			return &StatusError{Method: "accept", StatusCode: resp.StatusCode}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 type TradeItem struct {
@@ -242,18 +395,66 @@ func (c *Client) Create(
 	counteredOfferID uint64,
 	message string,
 ) (uint64, error) {
+	return c.CreateContext(context.Background(), other, accessToken, myItems, theirItems, counteredOfferID, message)
+}
+
+func (c *Client) CreateContext(
+	ctx context.Context,
+	other steamid.SteamID,
+	accessToken string,
+	myItems, theirItems []TradeItem,
+	counteredOfferID uint64,
+	message string,
+) (uint64, error) {
+	return c.create(ctx, other, accessToken, myItems, nil, theirItems, nil, counteredOfferID, message)
+}
+
+// Send sends the trade offer built with b. See Create.
+func (c *Client) Send(b *Builder) (uint64, error) {
+	return c.SendContext(context.Background(), b)
+}
+
+func (c *Client) SendContext(ctx context.Context, b *Builder) (uint64, error) {
+	return c.create(
+		ctx,
+		b.partner,
+		b.AccessToken,
+		b.me.items,
+		b.me.currency,
+		b.them.items,
+		b.them.currency,
+		b.CounteredOfferID,
+		b.Message,
+	)
+}
+
+func (c *Client) create(
+	ctx context.Context,
+	other steamid.SteamID,
+	accessToken string,
+	myItems, myCurrency, theirItems, theirCurrency []TradeItem,
+	counteredOfferID uint64,
+	message string,
+) (uint64, error) {
+	// Steam requires arrays, not nulls
+	for _, items := range []*[]TradeItem{&myItems, &myCurrency, &theirItems, &theirCurrency} {
+		if *items == nil {
+			*items = make([]TradeItem, 0)
+		}
+	}
+
 	// Create new trade offer status
 	to := map[string]interface{}{
 		"newversion": true,
 		"version":    3,
 		"me": map[string]interface{}{
 			"assets":   myItems,
-			"currency": make([]struct{}, 0),
+			"currency": myCurrency,
 			"ready":    false,
 		},
 		"them": map[string]interface{}{
 			"assets":   theirItems,
-			"currency": make([]struct{}, 0),
+			"currency": theirCurrency,
 			"ready":    false,
 		},
 	}
@@ -276,7 +477,7 @@ func (c *Client) Create(
 	var referer string
 
 	if counteredOfferID != 0 {
-		referer = fmt.Sprintf("https://steamcommunity.com/tradeoffer/%d/", counteredOfferID)
+		referer = fmt.Sprintf("%s/tradeoffer/%d/", c.CommunityURL, counteredOfferID)
 		data["tradeofferid_countered"] = strconv.FormatUint(counteredOfferID, 10)
 	} else { // Add token for non-friend offers
 		if accessToken != "" {
@@ -295,28 +496,22 @@ func (c *Client) Create(
 			data["trade_offer_create_params"] = string(paramsJSON)
 
 			referer = fmt.Sprintf(
-				"https://steamcommunity.com/tradeoffer/new/?partner=%d&token=%s",
+				"%s/tradeoffer/new/?partner=%d&token=%s",
+				c.CommunityURL,
 				other.AccountID(),
 				accessToken,
 			)
 		} else {
 			referer = fmt.Sprintf(
-				"https://steamcommunity.com/tradeoffer/new/?partner=%d",
+				"%s/tradeoffer/new/?partner=%d",
+				c.CommunityURL,
 				other.AccountID(),
 			)
 		}
 	}
 
-	req, err := netutil.NewPostForm("https://steamcommunity.com/tradeoffer/new/send", netutil.ToURLValues(data))
-
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Add("Referer", referer)
-
-	// Send request
-	resp, err := c.client.Do(req)
+	// Send request. Not retried, see RetryPolicy.
+	resp, err := c.postForm(ctx, c.CommunityURL+"/tradeoffer/new/send", referer, netutil.ToURLValues(data))
 
 	if err != nil {
 		return 0, err
@@ -333,19 +528,13 @@ func (c *Client) Create(
 		return 0, err
 	}
 
-	// strError code descriptions:
-	// 15	invalide trade access token
-	// 16	timeout
-	// 20	wrong contextid
-	// 25	can't send more offers until some is accepted/canceled...
-	// 26	object is not in our inventory
-	// error code names are in internal/steamlang/enums.go EResult_name
+	// strError codes are mapped to the Err* variables
 	if t.StrError != "" {
-		return 0, newSteamErrorf("create error: %v\n", t.StrError)
+		return 0, newStrError("create", t.StrError)
 	}
 
 	if resp.StatusCode != 200 {
-		return 0, fmt.Errorf("create error: status code %d", resp.StatusCode)
+		return 0, &StatusError{Method: "create", StatusCode: resp.StatusCode}
 	}
 
 	if t.TradeOfferID == 0 {
@@ -356,7 +545,28 @@ func (c *Client) Create(
 }
 
 func (c *Client) GetOwnInventory(contextID uint64, appID uint32) (*inventory.Inventory, error) {
-	return inventory.GetOwnInventory(c.client, contextID, appID)
+	return c.GetOwnInventoryContext(context.Background(), contextID, appID)
+}
+
+func (c *Client) GetOwnInventoryContext(
+	ctx context.Context,
+	contextID uint64,
+	appID uint32,
+) (*inventory.Inventory, error) {
+	return inventory.GetFullInventory(func(start uint) (*inventory.PartialInventory, error) {
+		var inv *inventory.PartialInventory
+
+		err := c.retry(ctx, func() (err error) {
+			if err = c.wait(ctx, c.CommunityURL); err != nil {
+				return err
+			}
+
+			inv, err = inventory.GetOwnPartialInventoryWithBaseURL(ctx, c.client, c.CommunityURL, contextID, appID, start)
+			return err
+		})
+
+		return inv, err
+	})
 }
 
 func (c *Client) GetPartnerInventory(
@@ -364,13 +574,31 @@ func (c *Client) GetPartnerInventory(
 	contextID uint64,
 	appID uint32,
 	offerID uint64,
+) (*inventory.Inventory, error) {
+	return c.GetPartnerInventoryContext(context.Background(), other, contextID, appID, offerID)
+}
+
+func (c *Client) GetPartnerInventoryContext(
+	ctx context.Context,
+	other steamid.SteamID,
+	contextID uint64,
+	appID uint32,
+	offerID uint64,
 ) (*inventory.Inventory, error) {
 	return inventory.GetFullInventory(func(start uint) (*inventory.PartialInventory, error) {
-		return c.getPartialPartnerInventory(other, contextID, appID, offerID, start)
+		var inv *inventory.PartialInventory
+
+		err := c.retry(ctx, func() (err error) {
+			inv, err = c.getPartialPartnerInventory(ctx, other, contextID, appID, offerID, start)
+			return err
+		})
+
+		return inv, err
 	})
 }
 
 func (c *Client) getPartialPartnerInventory(
+	ctx context.Context,
 	other steamid.SteamID,
 	contextID uint64,
 	appID uint32,
@@ -388,7 +616,7 @@ func (c *Client) getPartialPartnerInventory(
 		data["start"] = strconv.FormatUint(uint64(start), 10)
 	}
 
-	baseURL := "https://steamcommunity.com/tradeoffer/%v/"
+	baseURL := c.CommunityURL + "/tradeoffer/%v/"
 
 	if offerID != 0 {
 		baseURL = fmt.Sprintf(baseURL, offerID)
@@ -396,7 +624,12 @@ func (c *Client) getPartialPartnerInventory(
 		baseURL = fmt.Sprintf(baseURL, "new")
 	}
 
-	req, err := http.NewRequest("GET", baseURL+"partnerinventory/?"+netutil.ToURLValues(data).Encode(), nil)
+	if err := c.wait(ctx, baseURL); err != nil {
+		return nil, err
+	}
+
+	reqURL := baseURL + "partnerinventory/?" + netutil.ToURLValues(data).Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 
 	if err != nil {
 		return nil, err
@@ -409,16 +642,18 @@ func (c *Client) getPartialPartnerInventory(
 
 // Can be used to verify accepted tradeoffer and find out received asset ids
 func (c *Client) GetTradeReceipt(tradeID uint64) ([]*ReceiptItem, error) {
-	url := fmt.Sprintf("https://steamcommunity.com/trade/%d/receipt", tradeID)
-	resp, err := c.client.Get(url)
+	return c.GetTradeReceiptContext(context.Background(), tradeID)
+}
 
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) GetTradeReceiptContext(ctx context.Context, tradeID uint64) ([]*ReceiptItem, error) {
+	url := fmt.Sprintf("%s/trade/%d/receipt", c.CommunityURL, tradeID)
 
-	defer resp.Body.Close()
+	var respBody []byte
 
-	respBody, err := ioutil.ReadAll(resp.Body)
+	err := c.retry(ctx, func() (err error) {
+		respBody, err = c.getBody(ctx, url)
+		return err
+	})
 
 	if err != nil {
 		return nil, err
@@ -433,192 +668,102 @@ func (c *Client) GetTradeReceipt(tradeID uint64) ([]*ReceiptItem, error) {
 	return items, nil
 }
 
-// Get duration of escrow in days. Call this before sending a trade offer
+// Get duration of escrow in days. Call this before sending a trade offer.
+//
+// Uses GetTradeHoldDurations, falling back to scraping the new trade offer page if Steam doesn't
+// return the hold durations, see canScrapeEscrow. Returns ErrNotFriends, ErrInvalidAccessToken or
+// ErrTradeBan if no offer can be sent.
 func (c *Client) GetPartnerEscrowDuration(other steamid.SteamID, accessToken string) (*EscrowDuration, error) {
-	data := map[string]string{
-		"partner": other.AccountID().FormatString(),
-	}
-
-	if accessToken != "" {
-		data["token"] = accessToken
-	}
-
-	return c.getEscrowDuration("https://steamcommunity.com/tradeoffer/new/?" + netutil.ToURLValues(data).Encode())
-}
-
-// Get duration of escrow in days. Call this after receiving a trade offer
-func (c *Client) GetOfferEscrowDuration(offerID uint64) (*EscrowDuration, error) {
-	return c.getEscrowDuration("http://steamcommunity.com/tradeoffer/" + strconv.FormatUint(offerID, 10))
+	return c.GetPartnerEscrowDurationContext(context.Background(), other, accessToken)
 }
 
-func (c *Client) getEscrowDuration(queryURL string) (*EscrowDuration, error) {
-	resp, err := c.client.Get(queryURL)
+func (c *Client) GetPartnerEscrowDurationContext(
+	ctx context.Context,
+	other steamid.SteamID,
+	accessToken string,
+) (*EscrowDuration, error) {
+	escrow, err := c.GetTradeHoldDurationsContext(ctx, other, accessToken)
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve escrow duration: %v", err)
+	if err == nil {
+		return escrow, nil
 	}
 
-	defer resp.Body.Close()
-
-	respBody, err := ioutil.ReadAll(resp.Body)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 
-	if err != nil {
+	if !canScrapeEscrow(err) {
 		return nil, err
 	}
 
-	escrowDuration, err := parseEscrowDuration(respBody)
+	data := map[string]string{
+		"partner": other.AccountID().FormatString(),
+	}
 
-	if err != nil {
-		return nil, newSteamErrorf("failed to parse escrow duration: %v", err)
+	if accessToken != "" {
+		data["token"] = accessToken
 	}
-	return escrowDuration, nil
-}
 
-func (c *Client) GetOfferWithRetry(
-	offerID uint64,
-	retryCount int,
-	retryDelay time.Duration,
-) (*Result, error) {
-	var res *Result
-
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.GetOffer(offerID)
-			return err
-		}, retryCount, retryDelay)
+	return c.getEscrowDuration(ctx, c.CommunityURL+"/tradeoffer/new/?"+netutil.ToURLValues(data).Encode())
 }
 
-func (c *Client) GetOffersWithRetry(
-	getSent bool,
-	getReceived bool,
-	getDescriptions bool,
-	activeOnly bool,
-	historicalOnly bool,
-	timeHistoricalCutoff uint32,
-	retryCount int,
-	retryDelay time.Duration,
-) (*MultiResult, error) {
-	var res *MultiResult
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.GetOffers(getSent, getReceived, getDescriptions, activeOnly, historicalOnly, timeHistoricalCutoff)
-			return err
-		}, retryCount, retryDelay)
+// Get duration of escrow in days. Call this after receiving a trade offer
+//
+// Uses GetTradeHoldDurations with the partner and ID of the offer, which doesn't require an access
+// token, falling back to scraping the offer page like GetPartnerEscrowDuration.
+func (c *Client) GetOfferEscrowDuration(offerID uint64) (*EscrowDuration, error) {
+	return c.GetOfferEscrowDurationContext(context.Background(), offerID)
 }
 
-func (c *Client) DeclineWithRetry(offerID uint64, retryCount int, retryDelay time.Duration) error {
-	return withRetry(
-		func() error {
-			return c.Decline(offerID)
-		}, retryCount, retryDelay)
-}
+func (c *Client) GetOfferEscrowDurationContext(ctx context.Context, offerID uint64) (*EscrowDuration, error) {
+	result, err := c.getOffer(ctx, offerID, false)
 
-func (c *Client) CancelWithRetry(offerID uint64, retryCount int, retryDelay time.Duration) error {
-	return withRetry(
-		func() error {
-			return c.Cancel(offerID)
-		}, retryCount, retryDelay)
-}
+	if err == nil {
+		var escrow *EscrowDuration
 
-func (c *Client) AcceptWithRetry(offerID uint64, retryCount int, retryDelay time.Duration) error {
-	return withRetry(
-		func() error {
-			return c.Accept(offerID)
-		}, retryCount, retryDelay)
-}
+		escrow, err = c.tradeHoldDurations(ctx, map[string]string{
+			"key":            string(c.key),
+			"steamid_target": result.Offer.OtherSteamID.FormatString(),
+			"tradeofferid":   strconv.FormatUint(offerID, 10),
+		})
 
-func (c *Client) CreateWithRetry(
-	other steamid.SteamID,
-	accessToken string,
-	myItems, theirItems []TradeItem,
-	counteredOfferID uint64,
-	message string,
-	retryCount int,
-	retryDelay time.Duration,
-) (uint64, error) {
-	var res uint64
+		if err == nil {
+			return escrow, nil
+		}
+	}
 
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.Create(other, accessToken, myItems, theirItems, counteredOfferID, message)
-			return err
-		}, retryCount, retryDelay)
-}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 
-func (c *Client) GetOwnInventoryWithRetry(
-	contextID uint64,
-	appID uint32,
-	retryCount int,
-	retryDelay time.Duration,
-) (*inventory.Inventory, error) {
-	var res *inventory.Inventory
+	if !canScrapeEscrow(err) {
+		return nil, err
+	}
 
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.GetOwnInventory(contextID, appID)
-			return err
-		}, retryCount, retryDelay)
+	return c.getEscrowDuration(ctx, c.CommunityURL+"/tradeoffer/"+strconv.FormatUint(offerID, 10))
 }
 
-func (c *Client) GetPartnerInventoryWithRetry(
-	other steamid.SteamID,
-	contextID uint64,
-	appID uint32,
-	offerID uint64,
-	retryCount int,
-	retryDelay time.Duration,
-) (*inventory.Inventory, error) {
-	var res *inventory.Inventory
-
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.GetPartnerInventory(other, contextID, appID, offerID)
-			return err
-		}, retryCount, retryDelay,
-	)
-}
+func (c *Client) getEscrowDuration(ctx context.Context, queryURL string) (*EscrowDuration, error) {
+	var respBody []byte
 
-func (c *Client) GetTradeReceiptWithRetry(
-	tradeID uint64,
-	retryCount int,
-	retryDelay time.Duration,
-) ([]*ReceiptItem, error) {
-	var res []*ReceiptItem
-	return res, withRetry(
-		func() (err error) {
-			res, err = c.GetTradeReceipt(tradeID)
-			return err
-		}, retryCount, retryDelay)
-}
+	err := c.retry(ctx, func() (err error) {
+		respBody, err = c.getBody(ctx, queryURL)
+		return err
+	})
 
-// TODO: rewrite this
-func withRetry(f func() error, retryCount int, retryDelay time.Duration) error {
-	if retryCount <= 0 {
-		return errors.New("retry count must be more than 0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve escrow duration: %w", err)
 	}
 
-	i := 0
-
-	for {
-		i++
-
-		if err := f(); err != nil {
-			// If we got steam error do not retry
-			if _, ok := err.(*SteamError); ok {
-				return err
-			}
-
-			if i == retryCount {
-				return err
-			}
-
-			time.Sleep(retryDelay)
+	escrowDuration, err := parseEscrowDuration(respBody)
 
-			continue
-		}
+	if errors.Is(err, ErrNotFriends) || errors.Is(err, ErrInvalidAccessToken) || errors.Is(err, ErrTradeBan) {
+		return nil, err
+	}
 
-		break
+	if err != nil {
+		return nil, newSteamErrorf("failed to parse escrow duration: %v", err)
 	}
 
-	return nil
+	return escrowDuration, nil
 }
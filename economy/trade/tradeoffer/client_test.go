@@ -0,0 +1,323 @@
+package tradeoffer
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+	"github.com/13k/go-steam/economy/inventory"
+)
+
+func newTestClient(t *testing.T) (*Client, *steamtest.Server) {
+	server := steamtest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithHTTPClient(&http.Client{}, steamtest.APIKey, steamtest.SessionID, "login", "secure")
+
+	if err != nil {
+		t.Fatalf("NewClientWithHTTPClient: %v", err)
+	}
+
+	client.CommunityURL = server.URL
+	client.APIURL = server.URL
+
+	return client, server
+}
+
+func TestLifecycle(t *testing.T) {
+	client, server := newTestClient(t)
+	manager := NewManager(client, time.Minute)
+
+	if events := poll(t, manager); len(events) != 0 {
+		t.Fatalf("expected no events on empty baseline, got %v", events)
+	}
+
+	myInv, err := client.GetOwnInventory(2, 440)
+
+	if err != nil {
+		t.Fatalf("GetOwnInventory: %v", err)
+	}
+
+	theirInv, err := client.GetPartnerInventory(partner, 2, 440, 0)
+
+	if err != nil {
+		t.Fatalf("GetPartnerInventory: %v", err)
+	}
+
+	b := NewBuilderFromURL(NewTradeURL(partner, steamtest.AccessToken))
+	b.Message = "hello"
+
+	if err := b.AddMyItemsByName(myInv, 440, 2, "Mann Co. Supply Crate Key", 1); err != nil {
+		t.Fatalf("AddMyItemsByName: %v", err)
+	}
+
+	if err := b.AddTheirItem(theirInv, 440, 2, 2001, 0); err != nil {
+		t.Fatalf("AddTheirItem: %v", err)
+	}
+
+	sentID, err := client.Send(b)
+
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	events := poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	newOffer, ok := events[0].(*NewOfferEvent)
+
+	if !ok || newOffer.Offer.TradeOfferID != sentID || !newOffer.Offer.IsOurOffer {
+		t.Fatalf("expected NewOfferEvent for sent offer %d, got %#v", sentID, events[0])
+	}
+
+	if newOffer.Offer.OtherSteamID != partner || newOffer.Offer.Message != "hello" {
+		t.Errorf("unexpected sent offer %+v", newOffer.Offer)
+	}
+
+	if len(newOffer.Offer.ToGive) != 1 || newOffer.Offer.ToGive[0].AssetID != 1001 {
+		t.Errorf("unexpected items to give %+v", newOffer.Offer.ToGive)
+	}
+
+	server.SetOfferState(sentID, steamtest.StateAccepted)
+
+	events = poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	changed, ok := events[0].(*OfferStateChangedEvent)
+
+	if !ok || changed.OldState != StateActive || changed.NewState != StateAccepted {
+		t.Fatalf("expected OfferStateChangedEvent Active -> Accepted, got %#v", events[0])
+	}
+
+	receivedID := server.ReceiveOffer(partner, nil, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+	})
+
+	events = poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	if e, ok := events[0].(*NewOfferEvent); !ok || e.Offer.TradeOfferID != receivedID || e.Offer.IsOurOffer {
+		t.Fatalf("expected NewOfferEvent for received offer %d, got %#v", receivedID, events[0])
+	}
+
+	if err := client.Accept(receivedID); err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+
+	events = poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	changed, ok = events[0].(*OfferStateChangedEvent)
+
+	if !ok || changed.NewState != StateAccepted || changed.Offer.TradeID == 0 {
+		t.Fatalf("expected accepted OfferStateChangedEvent, got %#v", events[0])
+	}
+
+	items, err := client.GetTradeReceipt(changed.Offer.TradeID)
+
+	if err != nil {
+		t.Fatalf("GetTradeReceipt: %v", err)
+	}
+
+	if len(items) != 1 || items[0].AssetID != 3001 || items[0].MarketHashName != "Refined Metal" {
+		t.Errorf("unexpected receipt items %+v", items)
+	}
+
+	if events := poll(t, manager); len(events) != 0 {
+		t.Errorf("expected no events without changes, got %v", events)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	items := []TradeItem{{AppID: 440, ContextID: 2, AssetID: 9999, Amount: 1}}
+	_, err := client.Create(partner, steamtest.AccessToken, items, nil, 0, "")
+
+	if !errors.Is(err, ErrRevoked) {
+		t.Errorf("expected ErrRevoked, got %v", err)
+	}
+
+	if err := client.Accept(12345); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestDeclineCancel(t *testing.T) {
+	client, server := newTestClient(t)
+
+	received := server.ReceiveOffer(partner, nil, nil)
+
+	if err := client.Decline(received); err != nil {
+		t.Fatalf("Decline: %v", err)
+	}
+
+	if state := server.Offer(received).State; state != steamtest.StateDeclined {
+		t.Errorf("expected declined offer, got state %d", state)
+	}
+
+	sent, err := client.Create(partner, "", []TradeItem{{AppID: 440, ContextID: 2, AssetID: 1001, Amount: 1}}, nil, 0, "")
+
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := client.Cancel(sent); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	result, err := client.GetOffer(sent)
+
+	if err != nil {
+		t.Fatalf("GetOffer: %v", err)
+	}
+
+	if result.Offer.State != StateCanceled {
+		t.Errorf("expected canceled offer, got state %d", result.Offer.State)
+	}
+
+	if err := client.Cancel(sent); err == nil {
+		t.Errorf("expected error canceling canceled offer")
+	}
+}
+
+func TestBuilderValidation(t *testing.T) {
+	client, _ := newTestClient(t)
+	inv, err := client.GetOwnInventory(2, 440)
+
+	if err != nil {
+		t.Fatalf("GetOwnInventory: %v", err)
+	}
+
+	b := NewBuilder(partner)
+
+	if err := b.AddMyItem(inv, 440, 2, 1002, 0); !errors.Is(err, ErrItemNotTradable) {
+		t.Errorf("expected ErrItemNotTradable, got %v", err)
+	}
+
+	if err := b.AddMyItem(inv, 440, 2, 9999, 0); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+
+	if err := b.AddMyItem(inv, 440, 2, 1001, 2); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+
+	if err := b.AddMyItem(inv, 440, 2, 1001, 0); err != nil {
+		t.Fatalf("AddMyItem: %v", err)
+	}
+
+	if err := b.AddMyItem(inv, 440, 2, 1001, 0); !errors.Is(err, ErrDuplicateItem) {
+		t.Errorf("expected ErrDuplicateItem, got %v", err)
+	}
+
+	if err := b.AddMyItemsByName(inv, 440, 2, "Mann Co. Supply Crate Key", 1); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound for already added item, got %v", err)
+	}
+
+	if items := b.MyItems(); len(items) != 1 {
+		t.Errorf("expected 1 item, got %+v", items)
+	}
+}
+
+func TestEscrowDuration(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	escrow, err := client.GetPartnerEscrowDuration(partner, steamtest.AccessToken)
+
+	if err != nil {
+		t.Fatalf("GetPartnerEscrowDuration: %v", err)
+	}
+
+	if escrow.DaysMyEscrow != 0 || escrow.DaysTheirEscrow != 2 || escrow.TheirEscrow != 48*time.Hour {
+		t.Errorf("unexpected escrow duration %+v", escrow)
+	}
+
+	escrow, err = client.GetOfferEscrowDuration(101)
+
+	if err != nil {
+		t.Fatalf("GetOfferEscrowDuration: %v", err)
+	}
+
+	if escrow.DaysTheirEscrow != 2 {
+		t.Errorf("unexpected escrow duration %+v", escrow)
+	}
+
+	// without a valid API key, the new trade offer page is scraped
+	client.key = "invalid"
+
+	if _, err := client.GetPartnerEscrowDuration(partner, ""); !errors.Is(err, ErrNotFriends) {
+		t.Errorf("expected ErrNotFriends, got %v", err)
+	}
+
+	if _, err := client.GetPartnerEscrowDuration(partner, "invalid"); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Errorf("expected ErrInvalidAccessToken, got %v", err)
+	}
+}
+
+func TestTradeURLFetching(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	u, err := client.GetTradeURL()
+
+	if err != nil {
+		t.Fatalf("GetTradeURL: %v", err)
+	}
+
+	if u.Partner != steamtest.SteamID || u.Token != steamtest.AccessToken {
+		t.Errorf("unexpected trade URL %+v", u)
+	}
+
+	regenerated, err := client.RegenerateTradeURL()
+
+	if err != nil {
+		t.Fatalf("RegenerateTradeURL: %v", err)
+	}
+
+	if regenerated.Token == u.Token {
+		t.Errorf("expected new token, got %q", regenerated.Token)
+	}
+}
+
+func TestOfferGifts(t *testing.T) {
+	client, server := newTestClient(t)
+
+	offerID := server.ReceiveOffer(partner, nil, []steamtest.Asset{
+		{AppID: 753, ContextID: "1", AssetID: "4001", ClassID: "401", InstanceID: "0", Amount: "1"},
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+	})
+
+	result, err := client.GetOffer(offerID)
+
+	if err != nil {
+		t.Fatalf("GetOffer: %v", err)
+	}
+
+	if !result.Offer.HasGifts() {
+		t.Fatalf("expected offer with gifts, got %+v", result.Offer.ToReceive)
+	}
+
+	gifts := result.Offer.AssetsOfKind(inventory.ContextKindGift)
+
+	if len(gifts) != 1 || gifts[0].AssetID != 4001 {
+		t.Errorf("unexpected gifts %+v", gifts)
+	}
+
+	if kind := result.Offer.ToReceive[1].Kind(); kind != inventory.ContextKindGame {
+		t.Errorf("expected game item, got %v", kind)
+	}
+}
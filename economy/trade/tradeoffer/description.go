@@ -0,0 +1,320 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/13k/go-steam/economy/inventory"
+	"github.com/13k/go-steam/netutil"
+)
+
+// ClassInstance identifies the description of an asset.
+type ClassInstance struct {
+	AppID      uint32
+	ClassID    uint64
+	InstanceID uint64
+}
+
+// DescriptionCache caches item descriptions by app, class and instance ID. It's safe for concurrent
+// use and can be shared by several clients.
+type DescriptionCache struct {
+	mutex        sync.RWMutex
+	descriptions map[ClassInstance]*Description
+}
+
+// NewDescriptionCache creates an empty DescriptionCache.
+func NewDescriptionCache() *DescriptionCache {
+	return &DescriptionCache{descriptions: make(map[ClassInstance]*Description)}
+}
+
+// Get returns the cached description, or nil.
+func (c *DescriptionCache) Get(key ClassInstance) *Description {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.descriptions[key]
+}
+
+// Add caches the given descriptions.
+func (c *DescriptionCache) Add(descriptions ...*Description) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, desc := range descriptions {
+		c.descriptions[ClassInstance{desc.AppID, desc.ClassID, desc.InstanceID}] = desc
+	}
+}
+
+// Len returns the number of cached descriptions.
+func (c *DescriptionCache) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return len(c.descriptions)
+}
+
+// enrich joins the descriptions into the assets of the given offers, caching the descriptions
+// returned with the offers. If fetch is true, missing descriptions are fetched with
+// GetAssetClassInfo. Joining is best effort: assets whose description can't be fetched are left
+// without one and the first fetch error is returned.
+func (c *Client) enrich(ctx context.Context, offers []*Offer, descriptions []*Description, fetch bool) error {
+	c.Descriptions.Add(descriptions...)
+
+	missing := c.join(offers)
+
+	if !fetch || len(missing) == 0 {
+		return nil
+	}
+
+	var fetchErr error
+
+	for appID, keys := range missing {
+		classes := make([]ClassInstance, 0, len(keys))
+
+		for key := range keys {
+			classes = append(classes, key)
+		}
+
+		if _, err := c.GetAssetClassInfoContext(ctx, appID, classes); err != nil && fetchErr == nil {
+			fetchErr = err
+		}
+	}
+
+	c.join(offers)
+
+	return fetchErr
+}
+
+// join sets the cached descriptions of the assets of the given offers and returns the classes
+// missing from the cache by app.
+func (c *Client) join(offers []*Offer) map[uint32]map[ClassInstance]bool {
+	missing := make(map[uint32]map[ClassInstance]bool)
+
+	for _, offer := range offers {
+		for _, assets := range [][]*Asset{offer.ToGive, offer.ToReceive} {
+			for _, asset := range assets {
+				key := ClassInstance{asset.AppID, asset.ClassID, asset.InstanceID}
+
+				if asset.Description = c.Descriptions.Get(key); asset.Description == nil {
+					if missing[key.AppID] == nil {
+						missing[key.AppID] = make(map[ClassInstance]bool)
+					}
+
+					missing[key.AppID][key] = true
+				}
+			}
+		}
+	}
+
+	return missing
+}
+
+// Maximum number of classes requested by a single GetAssetClassInfo request.
+const classInfoBatchSize = 100
+
+// GetAssetClassInfo fetches the descriptions of the given classes of an app through
+// `ISteamEconomy/GetAssetClassInfo` and adds them to the client's description cache.
+//
+// Classes are requested in batches of 100. If some batches fail, the descriptions of the others are
+// still returned and cached, along with the first error.
+func (c *Client) GetAssetClassInfo(appID uint32, classes []ClassInstance) ([]*Description, error) {
+	return c.GetAssetClassInfoContext(context.Background(), appID, classes)
+}
+
+func (c *Client) GetAssetClassInfoContext(
+	ctx context.Context,
+	appID uint32,
+	classes []ClassInstance,
+) ([]*Description, error) {
+	var (
+		descriptions []*Description
+		firstErr     error
+	)
+
+	for start := 0; start < len(classes); start += classInfoBatchSize {
+		end := start + classInfoBatchSize
+
+		if end > len(classes) {
+			end = len(classes)
+		}
+
+		batch, err := c.getAssetClassInfo(ctx, appID, classes[start:end])
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		descriptions = append(descriptions, batch...)
+	}
+
+	return descriptions, firstErr
+}
+
+func (c *Client) getAssetClassInfo(
+	ctx context.Context,
+	appID uint32,
+	classes []ClassInstance,
+) ([]*Description, error) {
+	params := map[string]string{
+		"key":         string(c.key),
+		"appid":       strconv.FormatUint(uint64(appID), 10),
+		"language":    "en",
+		"class_count": strconv.Itoa(len(classes)),
+	}
+
+	for i, class := range classes {
+		params[fmt.Sprintf("classid%d", i)] = strconv.FormatUint(class.ClassID, 10)
+		params[fmt.Sprintf("instanceid%d", i)] = strconv.FormatUint(class.InstanceID, 10)
+	}
+
+	u := c.APIURL + "/ISteamEconomy/GetAssetClassInfo/v1?" + netutil.ToURLValues(params).Encode()
+
+	var result map[string]json.RawMessage
+
+	err := c.retry(ctx, func() error {
+		resp, err := c.get(ctx, u)
+
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return &StatusError{Method: "GetAssetClassInfo", StatusCode: resp.StatusCode}
+		}
+
+		t := &struct {
+			Result map[string]json.RawMessage
+		}{}
+
+		if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+			return err
+		}
+
+		result = t.Result
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var success bool
+
+	if err := json.Unmarshal(result["success"], &success); err != nil || !success {
+		return nil, newSteamErrorf("GetAssetClassInfo error: %s", result["error"])
+	}
+
+	descriptions := make([]*Description, 0, len(classes))
+
+	for key, data := range result {
+		if key == "success" || key == "error" {
+			continue
+		}
+
+		info := &classInfo{}
+
+		if err := json.Unmarshal(data, info); err != nil {
+			return nil, fmt.Errorf("GetAssetClassInfo: invalid class info %s: %v", key, err)
+		}
+
+		descriptions = append(descriptions, info.description(appID))
+	}
+
+	c.Descriptions.Add(descriptions...)
+
+	return descriptions, nil
+}
+
+// classInfo is a description as returned by GetAssetClassInfo, where every number is a string and
+// lists are objects keyed by index.
+type classInfo struct {
+	ClassID                   uint64 `json:"classid,string"`
+	InstanceID                string `json:"instanceid"`
+	IconURL                   string `json:"icon_url"`
+	IconLargeURL              string `json:"icon_url_large"`
+	Name                      string
+	MarketName                string `json:"market_name"`
+	MarketHashName            string `json:"market_hash_name"`
+	NameColor                 string `json:"name_color"`
+	BackgroundColor           string `json:"background_color"`
+	Type                      string
+	Tradable                  string
+	Commodity                 string
+	MarketTradableRestriction string                                `json:"market_tradable_restriction"`
+	Descriptions              map[string]*inventory.DescriptionLine `json:"descriptions"`
+	Actions                   map[string]*inventory.Action          `json:"actions"`
+	Tags                      map[string]*inventory.Tag             `json:"tags"`
+}
+
+func (i *classInfo) description(appID uint32) *Description {
+	instanceID, _ := strconv.ParseUint(i.InstanceID, 10, 64)
+	restriction, _ := strconv.ParseUint(i.MarketTradableRestriction, 10, 32)
+
+	desc := &Description{
+		AppID:                     appID,
+		ClassID:                   i.ClassID,
+		InstanceID:                instanceID,
+		IconURL:                   i.IconURL,
+		IconLargeURL:              i.IconLargeURL,
+		Name:                      i.Name,
+		MarketName:                i.MarketName,
+		MarketHashName:            i.MarketHashName,
+		NameColor:                 i.NameColor,
+		BackgroundColor:           i.BackgroundColor,
+		Type:                      i.Type,
+		Tradable:                  i.Tradable == "1",
+		Commodity:                 i.Commodity == "1",
+		MarketTradableRestriction: uint32(restriction),
+	}
+
+	descKeys := make([]string, 0, len(i.Descriptions))
+
+	for k := range i.Descriptions {
+		descKeys = append(descKeys, k)
+	}
+
+	for _, k := range sortIndexKeys(descKeys) {
+		desc.Descriptions = append(desc.Descriptions, i.Descriptions[k])
+	}
+
+	actionKeys := make([]string, 0, len(i.Actions))
+
+	for k := range i.Actions {
+		actionKeys = append(actionKeys, k)
+	}
+
+	for _, k := range sortIndexKeys(actionKeys) {
+		desc.Actions = append(desc.Actions, i.Actions[k])
+	}
+
+	tagKeys := make([]string, 0, len(i.Tags))
+
+	for k := range i.Tags {
+		tagKeys = append(tagKeys, k)
+	}
+
+	for _, k := range sortIndexKeys(tagKeys) {
+		desc.Tags = append(desc.Tags, i.Tags[k])
+	}
+
+	return desc
+}
+
+// sortIndexKeys sorts the keys of an object keyed by index by their numeric value.
+func sortIndexKeys(keys []string) []string {
+	sort.Slice(keys, func(a, b int) bool {
+		x, _ := strconv.Atoi(keys[a])
+		y, _ := strconv.Atoi(keys[b])
+
+		return x < y
+	})
+
+	return keys
+}
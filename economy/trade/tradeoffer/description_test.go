@@ -0,0 +1,198 @@
+package tradeoffer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+)
+
+func TestDescriptionEnrichment(t *testing.T) {
+	client, server := newTestClient(t)
+
+	offerID := server.ReceiveOffer(partner, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "1001", ClassID: "101", InstanceID: "0", Amount: "1"},
+	}, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+	})
+
+	result, err := client.GetOffers(false, true, true, true, false, 0)
+
+	if err != nil {
+		t.Fatalf("GetOffers: %v", err)
+	}
+
+	if len(result.Received) != 1 || result.Received[0].TradeOfferID != offerID {
+		t.Fatalf("unexpected received offers %+v", result.Received)
+	}
+
+	offer := result.Received[0]
+	give, receive := offer.ToGive[0].Description, offer.ToReceive[0].Description
+
+	if give == nil || give.MarketHashName != "Mann Co. Supply Crate Key" || !give.Tradable {
+		t.Errorf("unexpected description of item to give %+v", give)
+	}
+
+	if len(give.Tags) != 1 || give.Tags[0].Category != "Quality" {
+		t.Errorf("unexpected tags of item to give %+v", give.Tags)
+	}
+
+	if receive == nil || receive.Name != "Refined Metal" || !receive.Tradable || receive.MarketTradableRestriction != 7 {
+		t.Fatalf("unexpected description of item to receive %+v", receive)
+	}
+
+	if receive.AppID != 440 || receive.ClassID != 201 || receive.InstanceID != 11040547 {
+		t.Errorf("unexpected class of item to receive %+v", receive)
+	}
+
+	if len(receive.Tags) != 2 || receive.Tags[1].InternalName != "Craft Item" {
+		t.Errorf("unexpected tags of item to receive %+v", receive.Tags)
+	}
+
+	if len(receive.Descriptions) != 1 || receive.Descriptions[0].Value != "Used in crafting" {
+		t.Errorf("unexpected description lines of item to receive %+v", receive.Descriptions)
+	}
+
+	if n := server.ClassInfoRequests(); n != 1 {
+		t.Errorf("expected 1 GetAssetClassInfo request, got %d", n)
+	}
+
+	single, err := client.GetOffer(offerID)
+
+	if err != nil {
+		t.Fatalf("GetOffer: %v", err)
+	}
+
+	if d := single.Offer.ToReceive[0].Description; d != receive {
+		t.Errorf("expected cached description, got %+v", d)
+	}
+
+	if n := server.ClassInfoRequests(); n != 1 {
+		t.Errorf("expected cached descriptions to be reused, got %d GetAssetClassInfo requests", n)
+	}
+
+	if n := client.Descriptions.Len(); n != 2 {
+		t.Errorf("expected 2 cached descriptions, got %d", n)
+	}
+}
+
+func TestDescriptionFetchError(t *testing.T) {
+	client, server := newTestClient(t)
+
+	target, err := url.Parse(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/ISteamEconomy/GetAssetClassInfo/") {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		proxy.ServeHTTP(w, r)
+	}))
+
+	defer api.Close()
+
+	client.APIURL = api.URL
+
+	offerID := server.ReceiveOffer(partner, nil, []steamtest.Asset{
+		{AppID: 440, ContextID: "2", AssetID: "2001", ClassID: "201", InstanceID: "11040547", Amount: "1"},
+	})
+
+	result, err := client.GetOffers(false, true, true, true, false, 0)
+
+	if err != nil {
+		t.Fatalf("GetOffers: %v", err)
+	}
+
+	if len(result.Received) != 1 || result.Received[0].ToReceive[0].Description != nil {
+		t.Fatalf("unexpected received offers %+v", result.Received)
+	}
+
+	if _, ok := result.DescriptionsError.(*StatusError); !ok {
+		t.Errorf("expected StatusError, got %v", result.DescriptionsError)
+	}
+
+	single, err := client.GetOffer(offerID)
+
+	if err != nil {
+		t.Fatalf("GetOffer: %v", err)
+	}
+
+	if single.Offer.ToReceive[0].Description != nil || single.DescriptionsError == nil {
+		t.Errorf("expected offer without description and an error, got %+v", single)
+	}
+}
+
+func TestGetAssetClassInfoBatches(t *testing.T) {
+	client, server := newTestClient(t)
+
+	target, err := url.Parse(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	// fails the second batch, which starts with class 1100
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("classid0") == "1100" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		proxy.ServeHTTP(w, r)
+	}))
+
+	defer api.Close()
+
+	classes := make([]ClassInstance, 250)
+
+	for i := range classes {
+		classes[i] = ClassInstance{AppID: 440, ClassID: uint64(1000 + i)}
+	}
+
+	// the only known class, in the third batch
+	classes[210] = ClassInstance{AppID: 440, ClassID: 201, InstanceID: 11040547}
+
+	descriptions, err := client.GetAssetClassInfo(440, classes)
+
+	if err != nil {
+		t.Fatalf("GetAssetClassInfo: %v", err)
+	}
+
+	if len(descriptions) != 1 || descriptions[0].ClassID != 201 {
+		t.Errorf("expected the description of class 201, got %+v", descriptions)
+	}
+
+	if n := server.ClassInfoRequests(); n != 3 {
+		t.Errorf("expected 3 GetAssetClassInfo requests, got %d", n)
+	}
+
+	client.APIURL = api.URL
+	client.Descriptions = NewDescriptionCache()
+
+	descriptions, err = client.GetAssetClassInfo(440, classes)
+
+	if _, ok := err.(*StatusError); !ok {
+		t.Errorf("expected StatusError, got %v", err)
+	}
+
+	if len(descriptions) != 1 || client.Descriptions.Len() != 1 {
+		t.Errorf("expected the descriptions of the other batches, got %+v", descriptions)
+	}
+
+	if n := server.ClassInfoRequests(); n != 5 {
+		t.Errorf("expected the other batches to be requested, got %d GetAssetClassInfo requests", n-3)
+	}
+}
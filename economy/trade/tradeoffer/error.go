@@ -2,6 +2,11 @@ package tradeoffer
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/13k/go-steam-resources/steamlang"
 )
 
 // SteamError can be returned by Create, Accept, Decline and Cancel methods.
@@ -9,12 +14,57 @@ import (
 // or request was declined.
 type SteamError struct {
 	msg string
+	// Result code reported by Steam, if any. Use errors.Is with the Err* variables to check it.
+	Result steamlang.EResult
 }
 
 func (e *SteamError) Error() string {
 	return e.msg
 }
 
+// Is reports whether target is a SteamError with the same Result.
+func (e *SteamError) Is(target error) bool {
+	t, ok := target.(*SteamError)
+	return ok && t.Result != steamlang.EResult_Invalid && t.Result == e.Result
+}
+
 func newSteamErrorf(format string, args ...interface{}) *SteamError {
 	return &SteamError{msg: fmt.Sprintf(format, args...)}
 }
+
+// Errors reported by Steam as `strError` codes, to be used with errors.Is.
+var (
+	// Invalid trade offer access token, or the partner can't trade.
+	ErrAccessDenied = newResultError(steamlang.EResult_AccessDenied, "access denied")
+	ErrTimeout      = newResultError(steamlang.EResult_Timeout, "timeout")
+	// The offer is no longer active.
+	ErrInvalidState = newResultError(steamlang.EResult_InvalidState, "invalid offer state")
+	// Steam is unavailable, or the offer has items from an invalid context.
+	ErrServiceUnavailable = newResultError(steamlang.EResult_ServiceUnavailable, "service unavailable")
+	// Too many offers are active. Some must be accepted, declined or canceled first.
+	ErrLimitExceeded = newResultError(steamlang.EResult_LimitExceeded, "limit exceeded")
+	// Some items are no longer in the inventory they were added from.
+	ErrRevoked = newResultError(steamlang.EResult_Revoked, "items revoked")
+)
+
+func newResultError(result steamlang.EResult, msg string) *SteamError {
+	return &SteamError{msg: "tradeoffer: " + msg, Result: result}
+}
+
+// Steam appends the EResult code to the strError message, for example:
+//
+//	There was an error sending your trade offer.  Please try again later. (26)
+var strErrorCodeRE = regexp.MustCompile(`\((\d+)\)$`)
+
+func newStrError(action, strError string) *SteamError {
+	strError = strings.TrimSpace(strError)
+	err := newSteamErrorf("%s error: %s", action, strError)
+
+	if m := strErrorCodeRE.FindStringSubmatch(strError); m != nil {
+		if code, convErr := strconv.Atoi(m[1]); convErr == nil {
+			err.Result = steamlang.EResult(code)
+		}
+	}
+
+	return err
+}
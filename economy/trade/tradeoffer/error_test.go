@@ -0,0 +1,22 @@
+package tradeoffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrError(t *testing.T) {
+	err := newStrError("create", "There was an error sending your trade offer.  Please try again later. (26)")
+
+	if !errors.Is(err, ErrRevoked) {
+		t.Errorf("expected %v to be ErrRevoked", err)
+	}
+
+	if errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected %v not to be ErrAccessDenied", err)
+	}
+
+	if errors.Is(newStrError("create", "unknown error"), ErrRevoked) {
+		t.Errorf("expected error without code not to be ErrRevoked")
+	}
+}
@@ -1,35 +1,131 @@
 package tradeoffer
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// Errors returned when the escrow duration of a trade can't be determined, usually because no offer
+// can be sent to the partner.
+var (
+	ErrNotFriends         = errors.New("tradeoffer: you are not friends with this user")
+	ErrInvalidAccessToken = errors.New("tradeoffer: invalid trade offer access token")
+	ErrTradeBan           = errors.New("tradeoffer: user is not available to trade")
 )
 
 type EscrowDuration struct {
 	DaysMyEscrow    uint32
 	DaysTheirEscrow uint32
+	// Exact durations when fetched through the Web API, whole days when scraped from HTML pages.
+	MyEscrow    time.Duration
+	TheirEscrow time.Duration
 }
 
-func parseEscrowDuration(data []byte) (*EscrowDuration, error) {
-	// TODO: why we are using case insensitive matching?
-	myRegex := regexp.MustCompile(`(?i)g_daysMyEscrow[\s=]+(\d+);`)
-	theirRegex := regexp.MustCompile(`(?i)g_daysTheirEscrow[\s=]+(\d+);`)
+func newEscrowDuration(mySeconds, theirSeconds uint32) *EscrowDuration {
+	return &EscrowDuration{
+		DaysMyEscrow:    (mySeconds + 86399) / 86400,
+		DaysTheirEscrow: (theirSeconds + 86399) / 86400,
+		MyEscrow:        time.Duration(mySeconds) * time.Second,
+		TheirEscrow:     time.Duration(theirSeconds) * time.Second,
+	}
+}
 
-	myM := myRegex.FindSubmatch(data)
-	theirM := theirRegex.FindSubmatch(data)
+// GetTradeHoldDurations fetches the escrow duration of a trade with the given user through
+// `IEconService/GetTradeHoldDurations`. The access token is required if the user is not a friend.
+func (c *Client) GetTradeHoldDurations(other steamid.SteamID, accessToken string) (*EscrowDuration, error) {
+	return c.GetTradeHoldDurationsContext(context.Background(), other, accessToken)
+}
 
-	if myM == nil || theirM == nil {
-		// check if access token is valid
-		notFriendsRegex := regexp.MustCompile(">You are not friends with this user<")
-		notFriendsM := notFriendsRegex.FindSubmatch(data)
+func (c *Client) GetTradeHoldDurationsContext(
+	ctx context.Context,
+	other steamid.SteamID,
+	accessToken string,
+) (*EscrowDuration, error) {
+	params := map[string]string{
+		"key":            string(c.key),
+		"steamid_target": other.FormatString(),
+	}
+
+	if accessToken != "" {
+		params["trade_offer_access_token"] = accessToken
+	}
+
+	return c.tradeHoldDurations(ctx, params)
+}
+
+func (c *Client) tradeHoldDurations(ctx context.Context, params map[string]string) (*EscrowDuration, error) {
+	type holdDuration struct {
+		Seconds uint32 `json:"escrow_end_duration_seconds"`
+	}
+
+	type holdDurations struct {
+		MyEscrow    *holdDuration `json:"my_escrow"`
+		TheirEscrow *holdDuration `json:"their_escrow"`
+	}
+
+	var result *holdDurations
 
-		if notFriendsM == nil {
-			return nil, errors.New("regexp does not match")
+	err := c.retry(ctx, func() error {
+		result = &holdDurations{}
+		return c.getAPI(ctx, "GetTradeHoldDurations", 1, params, result)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MyEscrow == nil || result.TheirEscrow == nil {
+		return nil, newSteamErrorf("steam returned incomplete trade hold durations")
+	}
+
+	return newEscrowDuration(result.MyEscrow.Seconds, result.TheirEscrow.Seconds), nil
+}
+
+// canScrapeEscrow reports whether the escrow duration should be scraped from the trade offer pages
+// after GetTradeHoldDurations failed with err, that is, when Steam answered without the hold
+// durations, for example because of an API key that can't be used with it. Network errors and
+// cancellations would fail the same way when scraping, so they are returned as is.
+func canScrapeEscrow(err error) bool {
+	var statusErr *StatusError
+	var steamErr *SteamError
+
+	return errors.As(err, &statusErr) || errors.As(err, &steamErr)
+}
+
+var (
+	escrowMyRE    = regexp.MustCompile(`(?i)g_daysMyEscrow[\s=]+(\d+);`)
+	escrowTheirRE = regexp.MustCompile(`(?i)g_daysTheirEscrow[\s=]+(\d+);`)
+)
+
+// Messages shown instead of the trade offer page when no offer can be sent.
+var escrowErrorMessages = []struct {
+	text []byte
+	err  error
+}{
+	{[]byte(">You are not friends with this user<"), ErrNotFriends},
+	{[]byte("This Trade URL is no longer valid"), ErrInvalidAccessToken},
+	{[]byte("is not available to trade"), ErrTradeBan},
+}
+
+func parseEscrowDuration(data []byte) (*EscrowDuration, error) {
+	myM := escrowMyRE.FindSubmatch(data)
+	theirM := escrowTheirRE.FindSubmatch(data)
+
+	if myM == nil || theirM == nil {
+		for _, msg := range escrowErrorMessages {
+			if bytes.Contains(data, msg.text) {
+				return nil, msg.err
+			}
 		}
 
-		return nil, errors.New("you are not friends with this user")
+		return nil, errors.New("regexp does not match")
 	}
 
 	myEscrow, err := strconv.ParseUint(string(myM[1]), 10, 32)
@@ -47,5 +143,7 @@ func parseEscrowDuration(data []byte) (*EscrowDuration, error) {
 	return &EscrowDuration{
 		DaysMyEscrow:    uint32(myEscrow),
 		DaysTheirEscrow: uint32(theirEscrow),
+		MyEscrow:        time.Duration(myEscrow) * 24 * time.Hour,
+		TheirEscrow:     time.Duration(theirEscrow) * 24 * time.Hour,
 	}, nil
 }
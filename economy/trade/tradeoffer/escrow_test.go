@@ -0,0 +1,108 @@
+package tradeoffer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/13k/go-steam/economy/internal/steamtest"
+)
+
+const holdDurationsResponse = `{
+  "response": {
+    "my_escrow": {"escrow_end_duration_seconds": 0},
+    "their_escrow": {"escrow_end_duration_seconds": 86400}
+  }
+}`
+
+func TestGetOfferEscrowDuration(t *testing.T) {
+	var query url.Values
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/IEconService/GetTradeOffer/v1":
+			_, _ = w.Write([]byte(`{"response": {"offer": {"tradeofferid": "101", "accountid_other": 22202}}}`))
+		case "/IEconService/GetTradeHoldDurations/v1":
+			query = r.URL.Query()
+			_, _ = w.Write([]byte(holdDurationsResponse))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	escrow, err := client.GetOfferEscrowDuration(101)
+
+	if err != nil {
+		t.Fatalf("GetOfferEscrowDuration: %v", err)
+	}
+
+	if escrow.DaysMyEscrow != 0 || escrow.DaysTheirEscrow != 1 {
+		t.Errorf("unexpected escrow duration %+v", escrow)
+	}
+
+	if query.Get("tradeofferid") != "101" || query.Get("steamid_target") != partner.FormatString() {
+		t.Errorf("unexpected GetTradeHoldDurations query %v", query)
+	}
+}
+
+func TestEscrowDurationFallback(t *testing.T) {
+	var scraped int
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/IEconService/GetTradeHoldDurations/v1":
+			w.WriteHeader(http.StatusForbidden)
+		case "/tradeoffer/new/":
+			scraped++
+			_, _ = w.Write([]byte(steamtest.EscrowPage))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	// Steam answered without hold durations
+	escrow, err := client.GetPartnerEscrowDuration(partner, "token")
+
+	if err != nil {
+		t.Fatalf("GetPartnerEscrowDuration: %v", err)
+	}
+
+	if escrow.DaysTheirEscrow != 2 || scraped != 1 {
+		t.Errorf("expected the scraped escrow duration, got %+v", escrow)
+	}
+
+	// cancellations are not followed by scraping
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetPartnerEscrowDurationContext(ctx, partner, "token"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if scraped != 1 {
+		t.Errorf("expected no scraping after a cancellation")
+	}
+}
+
+func TestGetOffersEmptyResponse(t *testing.T) {
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"response": {}}`))
+	}))
+
+	result, err := client.GetOffers(true, true, false, true, false, 0)
+
+	if err != nil {
+		t.Fatalf("expected no offers without an error, got %v", err)
+	}
+
+	if len(result.Sent) != 0 || len(result.Received) != 0 {
+		t.Errorf("unexpected offers %+v", result)
+	}
+
+	// an empty object is still a failure for a single offer
+	if _, err := client.GetOffer(1); err == nil {
+		t.Errorf("expected error for an empty offer result")
+	}
+}
@@ -0,0 +1,250 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/13k/go-steam/netutil"
+	"github.com/13k/go-steam/steamid"
+)
+
+// TradeStatus is the status of a completed trade, as returned by GetTradeHistory and
+// GetTradeStatus.
+type TradeStatus uint
+
+const (
+	// Trade has just been accepted/confirmed, but no work has been done yet
+	TradeStatusInit TradeStatus = iota
+	// Steam is about to start committing the trade
+	TradeStatusPreCommitted
+	// The items have been exchanged
+	TradeStatusCommitted
+	// All work is finished
+	TradeStatusComplete
+	// Something went wrong after Init, but before Committed, and the trade has been rolled back
+	TradeStatusFailed
+	// A support person rolled back the trade for one side
+	TradeStatusPartialSupportRollback
+	// A support person rolled back the trade for both sides
+	TradeStatusFullSupportRollback
+	// A support person rolled back the trade for some set of items
+	TradeStatusSupportRollbackSelective
+	// We tried to roll back the trade when it failed, but haven't managed to do that for all items yet
+	TradeStatusRollbackFailed
+	// We tried to roll back the trade, but some failure didn't go away and we gave up
+	TradeStatusRollbackAbandoned
+	// Trade is in escrow
+	TradeStatusInEscrow
+	// A trade in escrow was rolled back
+	TradeStatusEscrowRollback
+)
+
+// TradeAsset is an asset exchanged in a trade.
+//
+// AssetID and ContextID are the IDs the item had before the trade, NewAssetID and NewContextID
+// are the IDs it has in the inventory of its new owner. The new IDs are zero until the trade
+// is committed, for example while it's in escrow.
+type TradeAsset struct {
+	AppID        uint32 `json:"appid"`
+	ContextID    uint64 `json:"contextid,string"`
+	AssetID      uint64 `json:"assetid,string"`
+	CurrencyID   uint64 `json:"currencyid,string"`
+	ClassID      uint64 `json:"classid,string"`
+	InstanceID   uint64 `json:"instanceid,string"`
+	Amount       uint64 `json:"amount,string"`
+	NewAssetID   uint64 `json:"new_assetid,string"`
+	NewContextID uint64 `json:"new_contextid,string"`
+	// IDs after a rollback
+	RollbackNewAssetID   uint64 `json:"rollback_new_assetid,string"`
+	RollbackNewContextID uint64 `json:"rollback_new_contextid,string"`
+}
+
+// Trade is a trade as returned by GetTradeHistory and GetTradeStatus.
+type Trade struct {
+	TradeID      uint64          `json:"tradeid,string"`
+	OtherSteamID steamid.SteamID `json:"steamid_other,string"`
+	TimeInit     uint32          `json:"time_init"`
+	// Zero if the trade was not held in escrow
+	TimeEscrowEnd uint32        `json:"time_escrow_end"`
+	Status        TradeStatus   `json:"status"`
+	Received      []*TradeAsset `json:"assets_received"`
+	Given         []*TradeAsset `json:"assets_given"`
+}
+
+// EscrowEnd returns when the items held in escrow will be delivered, or the zero time if the trade
+// was not held in escrow.
+func (t *Trade) EscrowEnd() time.Time {
+	if t.TimeEscrowEnd == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t.TimeEscrowEnd), 0)
+}
+
+// TradeHistory is a page of the trade history.
+type TradeHistory struct {
+	Trades []*Trade
+	// Only set if GetDescriptions was requested
+	Descriptions []*Description
+	// Only set if IncludeTotal was requested
+	TotalTrades uint32 `json:"total_trades"`
+	// Whether there are more trades after this page
+	More bool
+}
+
+// TradeHistoryOptions are the options of GetTradeHistory.
+//
+// To fetch the next page, set StartAfterTime and StartAfterTradeID to the TimeInit and TradeID of
+// the last trade of the current page. Set NavigatingBack to go to the previous page instead, using
+// the first trade of the current page.
+type TradeHistoryOptions struct {
+	// Defaults to 100
+	MaxTrades         uint32
+	StartAfterTime    uint32
+	StartAfterTradeID uint64
+	NavigatingBack    bool
+	GetDescriptions   bool
+	IncludeFailed     bool
+	IncludeTotal      bool
+}
+
+// GetTradeHistory fetches a page of the trade history, newest trades first.
+func (c *Client) GetTradeHistory(opts *TradeHistoryOptions) (*TradeHistory, error) {
+	return c.GetTradeHistoryContext(context.Background(), opts)
+}
+
+func (c *Client) GetTradeHistoryContext(ctx context.Context, opts *TradeHistoryOptions) (*TradeHistory, error) {
+	maxTrades := opts.MaxTrades
+
+	if maxTrades == 0 {
+		maxTrades = 100
+	}
+
+	params := map[string]string{
+		"key":        string(c.key),
+		"max_trades": strconv.FormatUint(uint64(maxTrades), 10),
+	}
+
+	if opts.StartAfterTime != 0 {
+		params["start_after_time"] = strconv.FormatUint(uint64(opts.StartAfterTime), 10)
+	}
+
+	if opts.StartAfterTradeID != 0 {
+		params["start_after_tradeid"] = strconv.FormatUint(opts.StartAfterTradeID, 10)
+	}
+
+	if opts.NavigatingBack {
+		params["navigating_back"] = "1"
+	}
+
+	if opts.GetDescriptions {
+		params["get_descriptions"] = "1"
+		params["language"] = "en_us"
+	}
+
+	if opts.IncludeFailed {
+		params["include_failed"] = "1"
+	}
+
+	if opts.IncludeTotal {
+		params["include_total"] = "1"
+	}
+
+	var history *TradeHistory
+
+	err := c.retry(ctx, func() error {
+		history = &TradeHistory{}
+		return c.getAPI(ctx, "GetTradeHistory", 1, params, history)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetTradeStatus fetches the trade with the given ID, which is the TradeID of an accepted Offer.
+//
+// The descriptions of the exchanged assets are returned if getDescriptions is true.
+func (c *Client) GetTradeStatus(tradeID uint64, getDescriptions bool) (*Trade, []*Description, error) {
+	return c.GetTradeStatusContext(context.Background(), tradeID, getDescriptions)
+}
+
+func (c *Client) GetTradeStatusContext(
+	ctx context.Context,
+	tradeID uint64,
+	getDescriptions bool,
+) (*Trade, []*Description, error) {
+	params := map[string]string{
+		"key":     string(c.key),
+		"tradeid": strconv.FormatUint(tradeID, 10),
+	}
+
+	if getDescriptions {
+		params["get_descriptions"] = "1"
+		params["language"] = "en_us"
+	}
+
+	type tradeStatus struct {
+		Trades       []*Trade
+		Descriptions []*Description
+	}
+
+	var result *tradeStatus
+
+	err := c.retry(ctx, func() error {
+		result = &tradeStatus{}
+		return c.getAPI(ctx, "GetTradeStatus", 1, params, result)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(result.Trades) == 0 {
+		return nil, nil, newSteamErrorf("steam returned empty trade status result")
+	}
+
+	return result.Trades[0], result.Descriptions, nil
+}
+
+// getAPI calls an IEconService GET method and decodes the `response` object into v.
+//
+// Steam returns an empty `response` object both for empty results and for some failures, so callers
+// that expect a result must check that v was filled.
+func (c *Client) getAPI(
+	ctx context.Context,
+	method string,
+	version uint,
+	params map[string]string,
+	v interface{},
+) error {
+	resp, err := c.get(ctx, c.apiURL(method, version)+"?"+netutil.ToURLValues(params).Encode())
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return &StatusError{Method: method, StatusCode: resp.StatusCode}
+	}
+
+	t := &struct {
+		Response json.RawMessage
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return err
+	}
+
+	if len(t.Response) == 0 {
+		return newSteamErrorf("steam returned no %s response", method)
+	}
+
+	return json.Unmarshal(t.Response, v)
+}
@@ -0,0 +1,146 @@
+package tradeoffer
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+const tradeHistoryResponse = `{
+  "response": {
+    "more": true,
+    "total_trades": 7,
+    "trades": [
+      {
+        "tradeid": "3622543526339473510",
+        "steamid_other": "76561197960287930",
+        "time_init": 1400000000,
+        "time_escrow_end": 1400172800,
+        "status": 3,
+        "assets_received": [
+          {
+            "appid": 440,
+            "contextid": "2",
+            "assetid": "2001",
+            "amount": "1",
+            "classid": "201",
+            "instanceid": "11040547",
+            "new_assetid": "3001",
+            "new_contextid": "2"
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+const tradeStatusResponse = `{
+  "response": {
+    "trades": [
+      {
+        "tradeid": "3622543526339473510",
+        "steamid_other": "76561197960287930",
+        "status": 10,
+        "assets_given": [
+          {"appid": 440, "contextid": "2", "assetid": "1001", "amount": "1", "classid": "101", "instanceid": "0"}
+        ]
+      }
+    ],
+    "descriptions": [
+      {"appid": 440, "classid": "101", "instanceid": "0", "name": "Mann Co. Supply Crate Key"}
+    ]
+  }
+}`
+
+func TestGetTradeHistory(t *testing.T) {
+	var query url.Values
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IEconService/GetTradeHistory/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(tradeHistoryResponse))
+	}))
+
+	history, err := client.GetTradeHistory(&TradeHistoryOptions{
+		MaxTrades:         10,
+		StartAfterTime:    1500000000,
+		StartAfterTradeID: 3622543526339473511,
+		NavigatingBack:    true,
+		IncludeFailed:     true,
+		IncludeTotal:      true,
+	})
+
+	if err != nil {
+		t.Fatalf("GetTradeHistory: %v", err)
+	}
+
+	expectedQuery := map[string]string{
+		"max_trades":          "10",
+		"start_after_time":    "1500000000",
+		"start_after_tradeid": "3622543526339473511",
+		"navigating_back":     "1",
+		"include_failed":      "1",
+		"include_total":       "1",
+		"get_descriptions":    "",
+	}
+
+	for key, expected := range expectedQuery {
+		if actual := query.Get(key); actual != expected {
+			t.Errorf("expected %s=%q, got %q", key, expected, actual)
+		}
+	}
+
+	if !history.More || history.TotalTrades != 7 || len(history.Trades) != 1 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	trade := history.Trades[0]
+
+	if trade.TradeID != 3622543526339473510 || trade.OtherSteamID != partner || trade.Status != TradeStatusComplete {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	if trade.TimeEscrowEnd != 1400172800 || len(trade.Received) != 1 || len(trade.Given) != 0 {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	if asset := trade.Received[0]; asset.AssetID != 2001 || asset.NewAssetID != 3001 || asset.NewContextID != 2 {
+		t.Errorf("unexpected received asset %+v", asset)
+	}
+}
+
+func TestGetTradeStatus(t *testing.T) {
+	var query url.Values
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IEconService/GetTradeStatus/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(tradeStatusResponse))
+	}))
+
+	trade, descriptions, err := client.GetTradeStatus(3622543526339473510, true)
+
+	if err != nil {
+		t.Fatalf("GetTradeStatus: %v", err)
+	}
+
+	if query.Get("tradeid") != "3622543526339473510" || query.Get("get_descriptions") != "1" {
+		t.Errorf("unexpected query %v", query)
+	}
+
+	if trade.Status != TradeStatusInEscrow || len(trade.Given) != 1 || trade.Given[0].NewAssetID != 0 {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	if len(descriptions) != 1 || descriptions[0].Name != "Mann Co. Supply Crate Key" {
+		t.Errorf("unexpected descriptions %+v", descriptions)
+	}
+}
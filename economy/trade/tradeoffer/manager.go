@@ -0,0 +1,402 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// How long offers in a final state are kept after the historical cutoff passes them.
+const managerPruneAge = 24 * 60 * 60
+
+// Manager polls the sent and received trade offers and emits events when offers are created or
+// change.
+//
+// Polls are incremental: only active offers and offers updated since the last poll are fetched,
+// using the `time_historical_cutoff` parameter of GetTradeOffers.
+//
+// Besides polling every Interval, a poll can be triggered with Notify(), for example when the
+// steam.Client emits a NotificationEvent of pending trade offers.
+//
+// Always poll events from the channel returned by Events() or the manager will stop polling. Like
+// steam.Client, only types ending with "Event" and errors are emitted.
+//
+// If StatePath is set, the known offers are loaded from it on Start() and written to it after every
+// poll, so that changes that happened while the manager was not running are reported after a
+// restart. Without a previous state, only active offers are reported as new on the first poll.
+//
+// Offers of emitted events and returned by Offer() are copies of the manager state.
+type Manager struct {
+	// Interval between polls.
+	Interval time.Duration
+	// Path of the file where the known offers are persisted. Persistence is disabled if empty.
+	StatePath string
+
+	client *Client
+	events chan interface{}
+	notify chan struct{}
+
+	pollMutex sync.Mutex
+
+	mutex  sync.Mutex
+	state  *managerState
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type managerState struct {
+	Cutoff uint32
+	Offers map[uint64]*Offer
+}
+
+// NewManager creates a Manager that polls offers with the given client.
+func NewManager(client *Client, interval time.Duration) *Manager {
+	return &Manager{
+		Interval: interval,
+		client:   client,
+		events:   make(chan interface{}, 30),
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// Events returns the event channel. It is never closed.
+func (m *Manager) Events() <-chan interface{} {
+	return m.events
+}
+
+// Offer returns the last polled version of the offer with the given ID, or nil if it's unknown.
+func (m *Manager) Offer(offerID uint64) *Offer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.state == nil {
+		return nil
+	}
+
+	offer, ok := m.state.Offers[offerID]
+
+	if !ok {
+		return nil
+	}
+
+	return copyOffer(offer)
+}
+
+// Start loads the persisted state, if any, and starts polling in the background.
+//
+// Calling Start on a running manager is a no-op.
+func (m *Manager) Start() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.cancel != nil {
+		return nil
+	}
+
+	if m.state == nil && m.StatePath != "" {
+		state, err := loadManagerState(m.StatePath)
+
+		if err != nil {
+			return err
+		}
+
+		m.state = state
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.loop(ctx, m.done)
+
+	return nil
+}
+
+// Stop stops polling, cancelling a poll in progress, and waits for the background loop to exit.
+// Events that are not received are dropped.
+func (m *Manager) Stop() {
+	m.mutex.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mutex.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// Notify triggers a poll as soon as possible. It never blocks and notifications received while a
+// poll is pending are coalesced.
+func (m *Manager) Notify() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := m.poll(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			events = append(events, err)
+		}
+
+		for _, event := range events {
+			if !m.emit(event, ctx.Done()) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.notify:
+		}
+	}
+}
+
+// Poll fetches the offers updated since the last poll, emits the events for the changes found and
+// persists the new state. It is called periodically after Start, but can also be called directly.
+func (m *Manager) Poll() error {
+	return m.PollContext(context.Background())
+}
+
+// PollContext is like Poll, but the request can be cancelled with ctx.
+func (m *Manager) PollContext(ctx context.Context) error {
+	events, err := m.poll(ctx)
+
+	// emit without holding the lock, so that Offer() can be called while handling events
+	for _, event := range events {
+		m.emit(event, nil)
+	}
+
+	return err
+}
+
+func (m *Manager) poll(ctx context.Context) ([]interface{}, error) {
+	// polls are serialized so that each one starts from the cutoff of the previous one
+	m.pollMutex.Lock()
+	defer m.pollMutex.Unlock()
+
+	var cutoff uint32
+
+	m.mutex.Lock()
+	if m.state != nil {
+		cutoff = m.state.Cutoff
+	}
+	m.mutex.Unlock()
+
+	// fetch without holding the lock, so that Offer() and Stop() don't wait for the network
+	result, err := m.client.GetOffersContext(ctx, true, true, true, true, false, cutoff)
+
+	if err != nil {
+		return nil, fmt.Errorf("tradeoffer/Manager: error polling offers: %v", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	baseline := m.state == nil
+
+	if baseline {
+		m.state = &managerState{Offers: make(map[uint64]*Offer)}
+	}
+
+	var events []interface{}
+
+	if result.DescriptionsError != nil {
+		events = append(events, fmt.Errorf("tradeoffer/Manager: error fetching descriptions: %v", result.DescriptionsError))
+	}
+
+	offers := make([]*Offer, 0, len(result.Sent)+len(result.Received))
+	offers = append(offers, result.Sent...)
+	offers = append(offers, result.Received...)
+
+	for _, offer := range offers {
+		events = append(events, m.update(offer, baseline)...)
+	}
+
+	m.prune()
+
+	if m.StatePath != "" {
+		if err := saveManagerState(m.StatePath, m.state); err != nil {
+			return events, fmt.Errorf("tradeoffer/Manager: error saving state: %v", err)
+		}
+	}
+
+	return events, nil
+}
+
+func (m *Manager) update(offer *Offer, baseline bool) []interface{} {
+	old, known := m.state.Offers[offer.TradeOfferID]
+	m.state.Offers[offer.TradeOfferID] = offer
+
+	if offer.TimeUpdated > m.state.Cutoff {
+		m.state.Cutoff = offer.TimeUpdated
+	}
+
+	if !known {
+		if baseline && offer.State != StateActive {
+			return nil
+		}
+
+		return []interface{}{&NewOfferEvent{Offer: copyOffer(offer)}}
+	}
+
+	var events []interface{}
+
+	if old.State != offer.State {
+		events = append(events, &OfferStateChangedEvent{
+			Offer:    copyOffer(offer),
+			OldState: old.State,
+			NewState: offer.State,
+		})
+	}
+
+	if !sameAssets(old.ToGive, offer.ToGive) || !sameAssets(old.ToReceive, offer.ToReceive) {
+		events = append(events, &OfferItemsChangedEvent{
+			Offer:        copyOffer(offer),
+			OldToGive:    copyAssets(old.ToGive),
+			OldToReceive: copyAssets(old.ToReceive),
+		})
+	}
+
+	return events
+}
+
+// prune forgets offers in a final state that are no longer returned by polls.
+func (m *Manager) prune() {
+	if m.state.Cutoff < managerPruneAge {
+		return
+	}
+
+	limit := m.state.Cutoff - managerPruneAge
+
+	for id, offer := range m.state.Offers {
+		if offer.TimeUpdated < limit && isFinalState(offer.State) {
+			delete(m.state.Offers, id)
+		}
+	}
+}
+
+// emit sends an event, giving up if stop is closed first.
+func (m *Manager) emit(event interface{}, stop <-chan struct{}) bool {
+	select {
+	case m.events <- event:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// copyOffer returns a copy of the offer that doesn't share assets with it. Descriptions are shared,
+// like the ones of the client's description cache.
+func copyOffer(offer *Offer) *Offer {
+	c := *offer
+	c.ToGive = copyAssets(offer.ToGive)
+	c.ToReceive = copyAssets(offer.ToReceive)
+
+	return &c
+}
+
+func copyAssets(assets []*Asset) []*Asset {
+	if assets == nil {
+		return nil
+	}
+
+	c := make([]*Asset, len(assets))
+
+	for i, asset := range assets {
+		a := *asset
+		c[i] = &a
+	}
+
+	return c
+}
+
+func isFinalState(state State) bool {
+	switch state {
+	case StateActive, StateCreatedNeedsConfirmation, StateInEscrow:
+		return false
+	}
+
+	return true
+}
+
+func sameAssets(a, b []*Asset) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := *a[i], *b[i]
+		// persisted offers don't have descriptions
+		x.Description, y.Description = nil, nil
+
+		if x != y {
+			return false
+		}
+	}
+
+	return true
+}
+
+func loadManagerState(path string) (*managerState, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	state := &managerState{}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("tradeoffer/Manager: invalid state %s: %v", path, err)
+	}
+
+	if state.Offers == nil {
+		state.Offers = make(map[uint64]*Offer)
+	}
+
+	return state, nil
+}
+
+// saveManagerState writes to a temporary file first so that a crash never leaves a truncated state.
+func saveManagerState(path string, state *managerState) error {
+	data, err := json.Marshal(state)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,21 @@
+package tradeoffer
+
+// NewOfferEvent is emitted by Manager when an offer, sent or received, is seen for the first time.
+type NewOfferEvent struct {
+	Offer *Offer
+}
+
+// OfferStateChangedEvent is emitted by Manager when the state of a known offer changes.
+type OfferStateChangedEvent struct {
+	Offer    *Offer
+	OldState State
+	NewState State
+}
+
+// OfferItemsChangedEvent is emitted by Manager when the items of a known offer change, for example
+// when items go missing or get new asset IDs after leaving escrow.
+type OfferItemsChangedEvent struct {
+	Offer        *Offer
+	OldToGive    []*Asset
+	OldToReceive []*Asset
+}
@@ -0,0 +1,437 @@
+package tradeoffer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const partner steamid.SteamID = 76561197960287930
+
+// redirectTransport sends every request to a test server, regardless of its URL.
+type redirectTransport struct {
+	host string
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newRedirectedClient creates a Client whose requests are all served by handler.
+func newRedirectedClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("key", "session", "login", "secure")
+
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	client.client.Transport = &redirectTransport{host: server.Listener.Addr().String()}
+
+	return client
+}
+
+// fakeOffers serves GetTradeOffers from a set of offers, like Steam: active offers and offers
+// updated since time_historical_cutoff are returned.
+type fakeOffers struct {
+	mutex   sync.Mutex
+	offers  map[uint64]*Offer
+	cutoffs []uint32
+}
+
+func newFakeOffers() *fakeOffers {
+	return &fakeOffers{offers: make(map[uint64]*Offer)}
+}
+
+func (f *fakeOffers) set(offer Offer) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.offers[offer.TradeOfferID] = &offer
+}
+
+// polls returns the number of GetTradeOffers requests served.
+func (f *fakeOffers) polls() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.cutoffs)
+}
+
+func (f *fakeOffers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if r.URL.Path != "/IEconService/GetTradeOffers/v1" {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	cutoff, _ := strconv.ParseUint(query.Get("time_historical_cutoff"), 10, 32)
+	f.cutoffs = append(f.cutoffs, uint32(cutoff))
+
+	result := &MultiResult{Sent: []*Offer{}, Received: []*Offer{}}
+	seen := make(map[uint64]bool)
+
+	for _, offer := range f.offers {
+		if offer.State != StateActive && offer.TimeUpdated < uint32(cutoff) {
+			continue
+		}
+
+		if offer.IsOurOffer {
+			result.Sent = append(result.Sent, offer)
+		} else {
+			result.Received = append(result.Received, offer)
+		}
+
+		if query.Get("get_descriptions") != "1" {
+			continue
+		}
+
+		assets := append([]*Asset{}, offer.ToGive...)
+		assets = append(assets, offer.ToReceive...)
+
+		for _, asset := range assets {
+			if !seen[asset.ClassID] {
+				seen[asset.ClassID] = true
+				result.Descriptions = append(result.Descriptions, &Description{
+					AppID:   asset.AppID,
+					ClassID: asset.ClassID,
+					Name:    "Item " + strconv.FormatUint(asset.ClassID, 10),
+				})
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"response": result})
+}
+
+// drain returns the events emitted by the last poll.
+func drain(m *Manager) []interface{} {
+	var events []interface{}
+
+	for {
+		select {
+		case event := <-m.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func poll(t *testing.T, m *Manager) []interface{} {
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+
+	return drain(m)
+}
+
+func testOffer(id uint64, state State, updated uint32, assetIDs ...uint64) Offer {
+	offer := Offer{
+		TradeOfferID: id,
+		State:        state,
+		TimeCreated:  updated,
+		TimeUpdated:  updated,
+	}
+
+	for _, assetID := range assetIDs {
+		offer.ToReceive = append(offer.ToReceive, &Asset{
+			AppID:     440,
+			ContextID: 2,
+			AssetID:   assetID,
+			ClassID:   assetID * 10,
+			Amount:    1,
+		})
+	}
+
+	return offer
+}
+
+func TestManagerEvents(t *testing.T) {
+	offers := newFakeOffers()
+	offers.set(testOffer(1, StateActive, 1000, 11))
+	offers.set(testOffer(2, StateDeclined, 1100))
+
+	manager := NewManager(newRedirectedClient(t, offers), time.Minute)
+
+	events := poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event on baseline, got %v", events)
+	}
+
+	if e, ok := events[0].(*NewOfferEvent); !ok || e.Offer.TradeOfferID != 1 {
+		t.Fatalf("expected NewOfferEvent for active offer 1, got %#v", events[0])
+	}
+
+	offers.set(testOffer(3, StateActive, 1200))
+
+	events = poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	if e, ok := events[0].(*NewOfferEvent); !ok || e.Offer.TradeOfferID != 3 {
+		t.Fatalf("expected NewOfferEvent for offer 3, got %#v", events[0])
+	}
+
+	offers.set(testOffer(1, StateAccepted, 1300, 11))
+	offers.set(testOffer(3, StateActive, 1300, 31))
+
+	events = poll(t, manager)
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+
+	for _, event := range events {
+		switch e := event.(type) {
+		case *OfferStateChangedEvent:
+			if e.Offer.TradeOfferID != 1 || e.OldState != StateActive || e.NewState != StateAccepted {
+				t.Errorf("unexpected state change %+v", e)
+			}
+		case *OfferItemsChangedEvent:
+			if e.Offer.TradeOfferID != 3 || len(e.OldToReceive) != 0 || len(e.Offer.ToReceive) != 1 {
+				t.Errorf("unexpected items change %+v", e)
+			}
+		default:
+			t.Errorf("unexpected event %#v", event)
+		}
+	}
+
+	if events := poll(t, manager); len(events) != 0 {
+		t.Errorf("expected no events without changes, got %v", events)
+	}
+
+	if offer := manager.Offer(1); offer == nil || offer.State != StateAccepted {
+		t.Errorf("expected accepted offer 1, got %+v", offer)
+	}
+
+	expectedCutoffs := []uint32{0, 1100, 1200, 1300}
+
+	for i, cutoff := range expectedCutoffs {
+		if offers.cutoffs[i] != cutoff {
+			t.Errorf("poll %d: expected cutoff %d, got %d", i, cutoff, offers.cutoffs[i])
+		}
+	}
+}
+
+func TestManagerStatePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tradeoffer")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	statePath := filepath.Join(dir, "offers.json")
+
+	offers := newFakeOffers()
+	offers.set(testOffer(1, StateActive, 1000))
+
+	client := newRedirectedClient(t, offers)
+	manager := NewManager(client, time.Minute)
+	manager.StatePath = statePath
+
+	if events := poll(t, manager); len(events) != 1 {
+		t.Fatalf("expected active offer to be reported on baseline, got %v", events)
+	}
+
+	offers.set(testOffer(2, StateActive, 1100))
+
+	restarted := NewManager(client, time.Minute)
+	restarted.StatePath = statePath
+
+	if err := restarted.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	defer restarted.Stop()
+
+	// Start polls in the background, so wait for the first poll
+	select {
+	case event := <-restarted.Events():
+		if e, ok := event.(*NewOfferEvent); !ok || e.Offer.TradeOfferID != 2 {
+			t.Fatalf("expected NewOfferEvent for offer 2, got %#v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for first poll")
+	}
+}
+
+func TestManagerCopies(t *testing.T) {
+	offers := newFakeOffers()
+	offers.set(testOffer(1, StateActive, 1000, 11))
+
+	manager := NewManager(newRedirectedClient(t, offers), time.Minute)
+
+	events := poll(t, manager)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+
+	e := events[0].(*NewOfferEvent)
+	e.Offer.State = StateDeclined
+	e.Offer.ToReceive[0].AssetID = 99
+
+	offer := manager.Offer(1)
+
+	if offer.State != StateActive || offer.ToReceive[0].AssetID != 11 {
+		t.Fatalf("expected events not to share offers with the manager, got %+v", offer)
+	}
+
+	offer.ToReceive = nil
+
+	if events := poll(t, manager); len(events) != 0 {
+		t.Errorf("expected Offer to return a copy, got events %v", events)
+	}
+}
+
+func TestManagerNotify(t *testing.T) {
+	offers := newFakeOffers()
+	manager := NewManager(newRedirectedClient(t, offers), time.Hour)
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	defer manager.Stop()
+
+	for offers.polls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	offers.set(testOffer(1, StateActive, 1000))
+
+	select {
+	case event := <-manager.Events():
+		t.Fatalf("unexpected event %#v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	manager.Notify()
+
+	select {
+	case event := <-manager.Events():
+		if e, ok := event.(*NewOfferEvent); !ok || e.Offer.TradeOfferID != 1 {
+			t.Fatalf("expected NewOfferEvent for offer 1, got %#v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the notified poll")
+	}
+}
+
+func TestManagerStopCancelsPoll(t *testing.T) {
+	polling := make(chan struct{}, 1)
+
+	manager := NewManager(newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polling <- struct{}{}
+		<-r.Context().Done()
+	})), time.Hour)
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	<-polling
+
+	// the state can be read while a poll is in progress
+	if offer := manager.Offer(1); offer != nil {
+		t.Errorf("expected no offers, got %+v", offer)
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		manager.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop didn't cancel the poll in progress")
+	}
+
+	if len(manager.Events()) != 0 {
+		t.Errorf("expected the cancelled poll not to emit, got %#v", <-manager.Events())
+	}
+}
+
+func TestManagerStopUnblocks(t *testing.T) {
+	offers := newFakeOffers()
+	manager := NewManager(newRedirectedClient(t, offers), time.Hour)
+
+	// more new offers than the channel can buffer, never received
+	for id := uint64(1); id <= uint64(cap(manager.events))+10; id++ {
+		offers.set(testOffer(id, StateActive, 1000))
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	for len(manager.Events()) < cap(manager.events) {
+		time.Sleep(time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		manager.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop blocked on a loop waiting to emit")
+	}
+
+	drain(manager)
+
+	// no loop is left running after Stop
+	polls := offers.polls()
+	manager.Notify()
+	time.Sleep(50 * time.Millisecond)
+
+	if n := offers.polls(); n != polls {
+		t.Fatalf("expected no polls after Stop, got %d", n-polls)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// the first poll of the new loop, followed by the one of the pending notification
+	for offers.polls() < polls+2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	manager.Stop()
+
+	if n := offers.polls(); n != polls+2 {
+		t.Errorf("expected a single loop after restarting, got %d polls", n-polls)
+	}
+}
@@ -7,6 +7,7 @@ package tradeoffer
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/13k/go-steam/economy/inventory"
 	"github.com/13k/go-steam/steamid"
@@ -52,7 +53,8 @@ const (
 )
 
 type Asset struct {
-	AppID      uint32 `json:"-"`
+	// Returned by GetTradeOffers and needed to join descriptions, which are keyed by app.
+	AppID      uint32 `json:"appid"`
 	ContextID  uint64 `json:",string"`
 	AssetID    uint64 `json:",string"`
 	CurrencyID uint64 `json:",string"`
@@ -60,6 +62,14 @@ type Asset struct {
 	InstanceID uint64 `json:",string"`
 	Amount     uint64 `json:",string"`
 	Missing    bool
+	// Joined by the client from the description cache, nil if unknown.
+	Description *Description `json:"-"`
+}
+
+// Kind returns the kind of the asset's inventory context, telling apart gifts, coupons and community
+// items of the Steam app from game items.
+func (a *Asset) Kind() inventory.ContextKind {
+	return inventory.ContextKindOf(a.AppID, a.ContextID)
 }
 
 type Offer struct {
@@ -97,15 +107,36 @@ func (t *Offer) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// InEscrow reports whether the offer was accepted and its items are held in escrow.
+func (t *Offer) InEscrow() bool {
+	return t.State == StateInEscrow
+}
+
+// EscrowEnd returns when the items held in escrow will be delivered, or the zero time if the offer
+// is not in escrow.
+func (t *Offer) EscrowEnd() time.Time {
+	if t.EscrowEndDate == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t.EscrowEndDate), 0)
+}
+
 type MultiResult struct {
 	Sent         []*Offer `json:"trade_offers_sent"`
 	Received     []*Offer `json:"trade_offers_received"`
 	Descriptions []*Description
+	// Error fetching the descriptions missing from the response. The assets without a description
+	// are still returned, with a nil Description.
+	DescriptionsError error `json:"-"`
 }
 
 type Result struct {
 	Offer        *Offer
 	Descriptions []*Description
+	// Error fetching the descriptions missing from the response. The assets without a description
+	// are still returned, with a nil Description.
+	DescriptionsError error `json:"-"`
 }
 
 type Description struct {
@@ -132,4 +163,25 @@ type Description struct {
 
 	Descriptions inventory.DescriptionLines `json:"descriptions"`
 	Actions      []*inventory.Action        `json:"actions"`
+	Tags         []*inventory.Tag           `json:"tags"`
+}
+
+// AssetsOfKind returns the assets on both sides of the offer whose context is of the given kind.
+func (t *Offer) AssetsOfKind(kind inventory.ContextKind) []*Asset {
+	var assets []*Asset
+
+	for _, side := range [][]*Asset{t.ToGive, t.ToReceive} {
+		for _, asset := range side {
+			if asset.Kind() == kind {
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	return assets
+}
+
+// HasGifts reports whether the offer includes Steam gifts or wallet codes.
+func (t *Offer) HasGifts() bool {
+	return len(t.AssetsOfKind(inventory.ContextKindGift)) > 0
 }
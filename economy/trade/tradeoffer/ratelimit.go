@@ -0,0 +1,78 @@
+package tradeoffer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter. It's safe for concurrent use and can be shared by
+// several clients to limit the requests of a single account or IP address.
+type RateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that allows rate requests per second on average, with bursts
+// of up to burst requests. A rate that is not positive doesn't limit requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	// also catches NaN
+	if !(rate > 0) {
+		rate = 0
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.rate == 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	l.last = now
+
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+
+	// reserve a token, waiting for the debt to be paid if there are none left
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+
+	l.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
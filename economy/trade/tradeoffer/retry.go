@@ -0,0 +1,130 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+)
+
+// StatusError is returned when Steam responds with an unexpected HTTP status code.
+type StatusError struct {
+	Method     string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s error: status code %d", e.Method, e.StatusCode)
+}
+
+// RetryPolicy retries failed requests with exponential backoff.
+//
+// Set it in Client.Retry to apply it to every request of the client, except for Create and Send,
+// which are never retried because a request that failed after reaching Steam could still have
+// created the offer.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Delay before the first retry.
+	InitialDelay time.Duration
+	// Maximum delay between attempts. Unlimited if zero.
+	MaxDelay time.Duration
+	// Factor applied to the delay after every retry. Defaults to 2.
+	Multiplier float64
+	// Reports whether a failed attempt should be retried. Defaults to IsRetryable.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with 5 attempts and delays from 1 to 30 seconds.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: time.Second,
+		MaxDelay:     30 * time.Second,
+		Multiplier:   2,
+	}
+}
+
+// Do calls op until it succeeds, returns an error that shouldn't be retried, the attempts are
+// exhausted or ctx is done. The last error is returned.
+func (p *RetryPolicy) Do(ctx context.Context, op func() error) error {
+	retryable := p.Retryable
+
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	multiplier := p.Multiplier
+
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	delay := p.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * multiplier)
+
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+}
+
+// IsRetryable reports whether a request that failed with err may succeed if retried.
+//
+// Network errors, server errors, rate limiting, malformed responses (Steam often responds with HTML
+// error pages when overloaded) and SteamError results indicating a temporary failure are retryable.
+// Canceled contexts and other Steam errors are not.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var steamErr *SteamError
+
+	if errors.As(err, &steamErr) {
+		switch steamErr.Result {
+		case steamlang.EResult_Timeout, steamlang.EResult_ServiceUnavailable, steamlang.EResult_Busy:
+			return true
+		}
+
+		return false
+	}
+
+	var statusErr *StatusError
+
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var urlErr *url.Error
+
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var syntaxErr *json.SyntaxError
+
+	return errors.As(err, &syntaxErr)
+}
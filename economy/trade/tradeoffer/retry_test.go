@@ -0,0 +1,163 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	retryable := &StatusError{Method: "test", StatusCode: http.StatusServiceUnavailable}
+	attempts := 0
+
+	err := policy.Do(context.Background(), func() error {
+		attempts++
+		return retryable
+	})
+
+	if err != retryable || attempts != 3 {
+		t.Errorf("expected 3 attempts with the last error, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+
+	err = policy.Do(context.Background(), func() error {
+		attempts++
+
+		if attempts < 2 {
+			return retryable
+		}
+
+		return nil
+	})
+
+	if err != nil || attempts != 2 {
+		t.Errorf("expected success after 2 attempts, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+
+	err = policy.Do(context.Background(), func() error {
+		attempts++
+		return ErrInvalidState
+	})
+
+	if err != ErrInvalidState || attempts != 1 {
+		t.Errorf("expected a single attempt, got %d attempts and %v", attempts, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	policy.InitialDelay = time.Hour
+	attempts = 0
+
+	go cancel()
+
+	err = policy.Do(ctx, func() error {
+		attempts++
+		return retryable
+	})
+
+	if err != retryable || attempts != 1 {
+		t.Errorf("expected canceled retries after 1 attempt, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		Err       error
+		Retryable bool
+	}{
+		{context.Canceled, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), false},
+		{ErrTimeout, true},
+		{ErrServiceUnavailable, true},
+		{ErrInvalidState, false},
+		{ErrRevoked, false},
+		{&StatusError{Method: "test", StatusCode: http.StatusBadGateway}, true},
+		{&StatusError{Method: "test", StatusCode: http.StatusTooManyRequests}, true},
+		{&StatusError{Method: "test", StatusCode: http.StatusForbidden}, false},
+		{&url.Error{Op: "Get", URL: "https://steamcommunity.com", Err: errors.New("connection reset")}, true},
+		{json.Unmarshal([]byte("<html>"), &struct{}{}), true},
+		{errors.New("unknown"), false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := IsRetryable(testCase.Err); actual != testCase.Retryable {
+			t.Errorf("%v: expected %v, got %v", testCase.Err, testCase.Retryable, actual)
+		}
+	}
+}
+
+func TestAcceptRetry(t *testing.T) {
+	var attempts int32
+
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			fmt.Fprintf(w, `{"strError": "There was an error accepting this trade offer. (%d)"}`, steamlang.EResult_Busy)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{}`)
+		case 3:
+			fmt.Fprint(w, `{"tradeid": "3622543526339473510"}`)
+		default:
+			fmt.Fprintf(w, `{"strError": "There was an error accepting this trade offer. (%d)"}`, steamlang.EResult_InvalidState)
+		}
+	}))
+
+	client.Retry = &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond}
+
+	if err := client.Accept(101); err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	err := client.Accept(101)
+
+	if !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+
+	var steamErr *SteamError
+
+	if !errors.As(err, &steamErr) || steamErr.Result != steamlang.EResult_InvalidState {
+		t.Errorf("expected SteamError with InvalidState result, got %v", err)
+	}
+
+	if attempts != 4 {
+		t.Errorf("expected non-retryable error to fail after 1 attempt, got %d attempts", attempts-3)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	client := newRedirectedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	client.Retry = &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	if _, err := client.GetOfferContext(ctx, 101); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected retries to stop with the context, took %v", elapsed)
+	}
+}
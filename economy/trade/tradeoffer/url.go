@@ -0,0 +1,158 @@
+package tradeoffer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const tradeURLBase = "https://steamcommunity.com/tradeoffer/new/"
+
+// TradeURL is a trade offer URL, which allows anyone to send trade offers to its owner:
+//
+//	https://steamcommunity.com/tradeoffer/new/?partner=<accountid>&token=<token>
+//
+// Friends can send trade offers to each other without a token.
+type TradeURL struct {
+	Partner steamid.SteamID
+	Token   string
+}
+
+// NewTradeURL returns the trade URL of the given user.
+func NewTradeURL(partner steamid.SteamID, token string) *TradeURL {
+	return &TradeURL{Partner: partner, Token: token}
+}
+
+// ParseTradeURL parses a trade offer URL.
+func ParseTradeURL(s string) (*TradeURL, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+
+	if err != nil {
+		return nil, fmt.Errorf("tradeoffer: invalid trade URL: %v", err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+
+	if host != "steamcommunity.com" || strings.TrimSuffix(u.Path, "/") != "/tradeoffer/new" {
+		return nil, fmt.Errorf("tradeoffer: invalid trade URL %q", s)
+	}
+
+	query := u.Query()
+	partner, err := strconv.ParseUint(query.Get("partner"), 10, 32)
+
+	if err != nil || partner == 0 {
+		return nil, fmt.Errorf("tradeoffer: invalid trade URL partner %q", query.Get("partner"))
+	}
+
+	return &TradeURL{
+		Partner: steamid.AccountID(partner).SteamID(),
+		Token:   query.Get("token"),
+	}, nil
+}
+
+// String returns the URL.
+func (u *TradeURL) String() string {
+	query := url.Values{"partner": {u.Partner.AccountID().FormatString()}}
+
+	if u.Token != "" {
+		query.Set("token", u.Token)
+	}
+
+	return tradeURLBase + "?" + query.Encode()
+}
+
+// GetAccessToken returns the trade offer access token of the authenticated user, which is the
+// `token` of their trade URL. If generateNew is true, a new token is generated, invalidating the
+// previous trade URL.
+func (c *Client) GetAccessToken(generateNew bool) (string, error) {
+	return c.GetAccessTokenContext(context.Background(), generateNew)
+}
+
+func (c *Client) GetAccessTokenContext(ctx context.Context, generateNew bool) (string, error) {
+	params := map[string]string{
+		"key": string(c.key),
+	}
+
+	if generateNew {
+		params["generate_new_token"] = "1"
+	}
+
+	t := &struct {
+		Token string `json:"trade_offer_access_token"`
+	}{}
+
+	err := c.retry(ctx, func() error {
+		return c.getAPI(ctx, "GetTradeOfferAccessToken", 1, params, t)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if t.Token == "" {
+		return "", newSteamErrorf("steam returned empty trade offer access token")
+	}
+
+	return t.Token, nil
+}
+
+var tradeURLInputRE = regexp.MustCompile(`id="trade_offer_access_url"[^>]*value="([^"]+)"`)
+
+// GetTradeURL fetches the trade URL of the authenticated user from their trade offer privacy page.
+func (c *Client) GetTradeURL() (*TradeURL, error) {
+	return c.GetTradeURLContext(context.Background())
+}
+
+func (c *Client) GetTradeURLContext(ctx context.Context) (*TradeURL, error) {
+	var respBody []byte
+
+	err := c.retry(ctx, func() (err error) {
+		respBody, err = c.getBody(ctx, c.CommunityURL+"/my/tradeoffers/privacy")
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	m := tradeURLInputRE.FindSubmatch(respBody)
+
+	if m == nil {
+		return nil, newSteamErrorf("trade URL not found in steam response")
+	}
+
+	return ParseTradeURL(html.UnescapeString(string(m[1])))
+}
+
+// RegenerateTradeURL generates a new trade offer access token, invalidating the previous trade URL,
+// and returns the new trade URL.
+func (c *Client) RegenerateTradeURL() (*TradeURL, error) {
+	return c.RegenerateTradeURLContext(context.Background())
+}
+
+func (c *Client) RegenerateTradeURLContext(ctx context.Context) (*TradeURL, error) {
+	token, err := c.GetAccessTokenContext(ctx, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := c.GetTradeURLContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Token != token {
+		return nil, errors.New("tradeoffer: trade URL doesn't have the regenerated token")
+	}
+
+	return u, nil
+}
@@ -0,0 +1,66 @@
+package tradeoffer
+
+import (
+	"testing"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+func TestParseTradeURL(t *testing.T) {
+	testCases := []struct {
+		Subject  string
+		Expected *TradeURL
+		Err      bool
+	}{
+		{
+			Subject:  "https://steamcommunity.com/tradeoffer/new/?partner=22202&token=AbCd-123",
+			Expected: &TradeURL{Partner: steamid.SteamID(76561197960287930), Token: "AbCd-123"},
+		},
+		{
+			Subject:  "http://www.steamcommunity.com/tradeoffer/new?partner=22202",
+			Expected: &TradeURL{Partner: steamid.SteamID(76561197960287930)},
+		},
+		{
+			Subject: "https://steamcommunity.com/tradeoffer/new/?token=AbCd-123",
+			Err:     true,
+		},
+		{
+			Subject: "https://steamcommunity.com/tradeoffer/new/?partner=76561197960287930",
+			Err:     true,
+		},
+		{
+			Subject: "https://example.com/tradeoffer/new/?partner=22202&token=AbCd-123",
+			Err:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseTradeURL(testCase.Subject)
+
+		if testCase.Err {
+			if err == nil {
+				t.Errorf("%q: expected error, got %+v", testCase.Subject, actual)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", testCase.Subject, err)
+			continue
+		}
+
+		if *actual != *testCase.Expected {
+			t.Errorf("%q: expected %+v, got %+v", testCase.Subject, testCase.Expected, actual)
+		}
+	}
+}
+
+func TestTradeURLString(t *testing.T) {
+	u := NewTradeURL(steamid.SteamID(76561197960287930), "AbCd-123")
+	expected := "https://steamcommunity.com/tradeoffer/new/?partner=22202&token=AbCd-123"
+
+	if actual := u.String(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
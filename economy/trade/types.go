@@ -65,3 +65,7 @@ type SetCurrencyEvent struct {
 type ChatEvent struct {
 	Message string
 }
+
+// SyncEvent is emitted by Session after the items of both sides were resynced from the full state
+// of the trade, which Steam sends with every new trade version.
+type SyncEvent struct{}
@@ -0,0 +1,407 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/steamid"
+)
+
+// GetNickname returns the nickname given to a user, or an empty string. Nicknames are also set in
+// the Friends cache.
+func (s *Social) GetNickname(id steamid.SteamID) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.nicknames[id]
+}
+
+// SetNickname gives a nickname to a user, or removes it if nickname is empty. The nickname is
+// cached when Steam echoes the change.
+func (s *Social) SetNickname(ctx context.Context, id steamid.SteamID, nickname string) error {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_AMClientSetPlayerNickname, &pb.CMsgClientSetPlayerNickname{
+		Steamid:  proto.Uint64(id.Uint64()),
+		Nickname: proto.String(nickname),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	event, ok := result.(*SetNicknameEvent)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	return friendGroupsResultError("nickname change", event.Result)
+}
+
+// RemoveNickname removes the nickname given to a user.
+func (s *Social) RemoveNickname(ctx context.Context, id steamid.SteamID) error {
+	return s.SetNickname(ctx, id, "")
+}
+
+// FavoritesGroupName is the name of the friend group that holds the favorite friends. Steam has no
+// message for favorites in this protocol version, so they are kept in a regular friend group, which
+// other clients show as a tag.
+const FavoritesGroupName = "Favorites"
+
+// IsFavorite returns whether a friend is in the favorites. Favorites are also set in the Friends
+// cache.
+func (s *Social) IsFavorite(id steamid.SteamID) bool {
+	group, err := s.FriendGroups.ByName(FavoritesGroupName)
+	return err == nil && group.Members[id]
+}
+
+// SetFavorite adds a friend to the favorites, or removes it if favorite is false. The favorites
+// group is created when the first favorite is added. Favorites are cached when Steam echoes the
+// change.
+func (s *Social) SetFavorite(ctx context.Context, id steamid.SteamID, favorite bool) error {
+	group, err := s.FriendGroups.ByName(FavoritesGroupName)
+
+	if err != nil {
+		if !favorite {
+			return nil
+		}
+
+		_, err = s.CreateFriendGroup(ctx, FavoritesGroupName, id)
+
+		return err
+	}
+
+	if favorite {
+		return s.AddFriendToGroup(ctx, group.ID, id)
+	}
+
+	return s.RemoveFriendFromGroup(ctx, group.ID, id)
+}
+
+// RemoveFavorite removes a friend from the favorites.
+func (s *Social) RemoveFavorite(ctx context.Context, id steamid.SteamID) error {
+	return s.SetFavorite(ctx, id, false)
+}
+
+// CreateFriendGroup creates a friend group (tag) with the given friends, returning its ID. The
+// group is cached when Steam echoes the change.
+func (s *Social) CreateFriendGroup(ctx context.Context, name string, friends ...steamid.SteamID) (int32, error) {
+	ids := make([]uint64, len(friends))
+
+	for i, id := range friends {
+		ids[i] = id.Uint64()
+	}
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_AMClientCreateFriendsGroup, &pb.CMsgClientCreateFriendsGroup{
+		Steamid:        proto.Uint64(s.client.SteamID().Uint64()),
+		Groupname:      proto.String(name),
+		SteamidFriends: ids,
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return 0, err
+	}
+
+	event, ok := result.(*CreateFriendGroupEvent)
+
+	if !ok {
+		return 0, unexpectedResultError(result)
+	}
+
+	return event.GroupID, friendGroupsResultError("friend group creation", event.Result)
+}
+
+// DeleteFriendGroup deletes a friend group.
+func (s *Social) DeleteFriendGroup(ctx context.Context, groupID int32) error {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_AMClientDeleteFriendsGroup, &pb.CMsgClientDeleteFriendsGroup{
+		Steamid: proto.Uint64(s.client.SteamID().Uint64()),
+		Groupid: proto.Int32(groupID),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	event, ok := result.(*DeleteFriendGroupEvent)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	return friendGroupsResultError("friend group deletion", event.Result)
+}
+
+// ManageFriendGroup renames a friend group and adds or removes friends from it. The group is not
+// renamed if name is empty.
+func (s *Social) ManageFriendGroup(
+	ctx context.Context,
+	groupID int32,
+	name string,
+	added, removed []steamid.SteamID,
+) error {
+	body := &pb.CMsgClientManageFriendsGroup{Groupid: proto.Int32(groupID)}
+
+	if name != "" {
+		body.Groupname = proto.String(name)
+	}
+
+	for _, id := range added {
+		body.SteamidFriendsAdded = append(body.SteamidFriendsAdded, id.Uint64())
+	}
+
+	for _, id := range removed {
+		body.SteamidFriendsRemoved = append(body.SteamidFriendsRemoved, id.Uint64())
+	}
+
+	result, err := s.client.call(ctx, protocol.NewProtoMessage(steamlang.EMsg_AMClientManageFriendsGroup, body))
+
+	if err != nil {
+		return err
+	}
+
+	event, ok := result.(*ManageFriendGroupEvent)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	return friendGroupsResultError("friend group change", event.Result)
+}
+
+// RenameFriendGroup renames a friend group.
+func (s *Social) RenameFriendGroup(ctx context.Context, groupID int32, name string) error {
+	return s.ManageFriendGroup(ctx, groupID, name, nil, nil)
+}
+
+// AddFriendToGroup adds a friend to a friend group.
+func (s *Social) AddFriendToGroup(ctx context.Context, groupID int32, friend steamid.SteamID) error {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_AMClientAddFriendToGroup, &pb.CMsgClientAddFriendToGroup{
+		Groupid:     proto.Int32(groupID),
+		Steamiduser: proto.Uint64(friend.Uint64()),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	event, ok := result.(*AddFriendToGroupEvent)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	return friendGroupsResultError("friend group addition", event.Result)
+}
+
+// RemoveFriendFromGroup removes a friend from a friend group.
+func (s *Social) RemoveFriendFromGroup(ctx context.Context, groupID int32, friend steamid.SteamID) error {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_AMClientRemoveFriendFromGroup, &pb.CMsgClientRemoveFriendFromGroup{
+		Groupid:     proto.Int32(groupID),
+		Steamiduser: proto.Uint64(friend.Uint64()),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	event, ok := result.(*RemoveFriendFromGroupEvent)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	return friendGroupsResultError("friend group removal", event.Result)
+}
+
+func friendGroupsResultError(action string, result steamlang.EResult) error {
+	if result == steamlang.EResult_OK {
+		return nil
+	}
+
+	return fmt.Errorf("steam/social: %s failed: %v", action, result)
+}
+
+func (s *Social) handlePlayerNicknameList(packet *protocol.Packet) {
+	body := &pb.CMsgClientPlayerNicknameList{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/PlayerNicknameList: error reading message: %v", err)
+		return
+	}
+
+	changed := make(map[steamid.SteamID]string)
+
+	s.mutex.Lock()
+
+	if !body.GetIncremental() && !body.GetRemoval() {
+		for id := range s.nicknames {
+			changed[id] = ""
+		}
+
+		s.nicknames = make(map[steamid.SteamID]string)
+	}
+
+	for _, n := range body.GetNicknames() {
+		id := steamid.SteamID(n.GetSteamid())
+
+		if body.GetRemoval() || n.GetNickname() == "" {
+			delete(s.nicknames, id)
+			changed[id] = ""
+		} else {
+			s.nicknames[id] = n.GetNickname()
+			changed[id] = n.GetNickname()
+		}
+	}
+
+	s.mutex.Unlock()
+
+	for id, nickname := range changed {
+		s.Friends.SetNickname(id, nickname)
+	}
+
+	s.client.Emit(&NicknameListEvent{Incremental: body.GetIncremental() || body.GetRemoval()})
+}
+
+func (s *Social) handleSetPlayerNicknameResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientSetPlayerNicknameResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/SetPlayerNicknameResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &SetNicknameEvent{Result: steamlang.EResult(body.GetEresult())}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Social) handleFriendsGroupsList(packet *protocol.Packet) {
+	body := &pb.CMsgClientFriendsGroupsList{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/FriendsGroupsList: error reading message: %v", err)
+		return
+	}
+
+	if !body.GetBincremental() && !body.GetBremoval() {
+		s.FriendGroups.Clear()
+	}
+
+	for _, group := range body.GetFriendGroups() {
+		if body.GetBremoval() {
+			s.FriendGroups.Remove(group.GetNGroupID())
+		} else {
+			s.FriendGroups.Set(group.GetNGroupID(), group.GetStrGroupName())
+		}
+	}
+
+	for _, membership := range body.GetMemberships() {
+		id := steamid.SteamID(membership.GetUlSteamID())
+
+		if body.GetBremoval() {
+			s.FriendGroups.RemoveMember(membership.GetNGroupID(), id)
+		} else {
+			s.FriendGroups.AddMember(membership.GetNGroupID(), id)
+		}
+	}
+
+	s.syncFavorites()
+
+	s.client.Emit(&FriendGroupsListEvent{Incremental: body.GetBincremental() || body.GetBremoval()})
+}
+
+// syncFavorites sets the favorites of the Friends cache from the favorites group.
+func (s *Social) syncFavorites() {
+	group, _ := s.FriendGroups.ByName(FavoritesGroupName)
+
+	for id := range s.Friends.GetCopy() {
+		s.Friends.SetFavorite(id, group.Members[id])
+	}
+}
+
+func (s *Social) handleCreateFriendsGroupResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientCreateFriendsGroupResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/CreateFriendsGroupResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &CreateFriendGroupEvent{
+		Result:  steamlang.EResult(body.GetEresult()),
+		GroupID: body.GetGroupid(),
+	}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Social) handleDeleteFriendsGroupResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientDeleteFriendsGroupResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/DeleteFriendsGroupResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &DeleteFriendGroupEvent{Result: steamlang.EResult(body.GetEresult())}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Social) handleManageFriendsGroupResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientManageFriendsGroupResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/ManageFriendsGroupResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &ManageFriendGroupEvent{Result: steamlang.EResult(body.GetEresult())}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Social) handleAddFriendToGroupResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientAddFriendToGroupResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/AddFriendToGroupResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &AddFriendToGroupEvent{Result: steamlang.EResult(body.GetEresult())}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Social) handleRemoveFriendFromGroupResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientRemoveFriendFromGroupResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/RemoveFriendFromGroupResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &RemoveFriendFromGroupEvent{Result: steamlang.EResult(body.GetEresult())}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
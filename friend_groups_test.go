@@ -0,0 +1,341 @@
+package steam
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+func TestSocialPlayerNicknameList(t *testing.T) {
+	client := newTestClient(t)
+	client.Social.Friends.Add(socialcache.Friend{SteamID: testFriendID})
+	client.Social.Friends.Add(socialcache.Friend{SteamID: testOtherID})
+
+	nicknames := func(removal, incremental bool, pairs ...interface{}) {
+		body := &pb.CMsgClientPlayerNicknameList{Removal: proto.Bool(removal), Incremental: proto.Bool(incremental)}
+
+		for i := 0; i < len(pairs); i += 2 {
+			body.Nicknames = append(body.Nicknames, &pb.CMsgClientPlayerNicknameList_PlayerNickname{
+				Steamid:  proto.Uint64(pairs[i].(steamid.SteamID).Uint64()),
+				Nickname: proto.String(pairs[i+1].(string)),
+			})
+		}
+
+		client.handle(steamlang.EMsg_ClientPlayerNicknameList, body, 0)
+
+		if event, ok := client.event().(*NicknameListEvent); !ok || event.Incremental != (removal || incremental) {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	}
+
+	expect := func(step string, friend, other string) {
+		t.Helper()
+
+		if n := client.Social.GetNickname(testFriendID); n != friend {
+			t.Errorf("%s: expected nickname %q of friend, got %q", step, friend, n)
+		}
+
+		if n := client.Social.GetNickname(testOtherID); n != other {
+			t.Errorf("%s: expected nickname %q of other, got %q", step, other, n)
+		}
+
+		friends := client.Social.Friends.GetCopy()
+
+		if friends[testFriendID].Nickname != friend || friends[testOtherID].Nickname != other {
+			t.Errorf("%s: unexpected cached nicknames %+v", step, friends)
+		}
+	}
+
+	nicknames(false, false, testFriendID, "gabe", testOtherID, "robin")
+	expect("full", "gabe", "robin")
+
+	nicknames(false, true, testFriendID, "gaben")
+	expect("incremental", "gaben", "robin")
+
+	nicknames(false, true, testOtherID, "")
+	expect("incremental empty", "gaben", "")
+
+	nicknames(false, false, testOtherID, "robin")
+	expect("full replacement", "", "robin")
+
+	nicknames(true, false, testOtherID, "robin")
+	expect("removal", "", "")
+}
+
+func TestSocialFriendsGroupsList(t *testing.T) {
+	client := newTestClient(t)
+
+	type membership struct {
+		id    steamid.SteamID
+		group int32
+	}
+
+	groupsList := func(removal, incremental bool, groups map[int32]string, memberships ...membership) {
+		body := &pb.CMsgClientFriendsGroupsList{Bremoval: proto.Bool(removal), Bincremental: proto.Bool(incremental)}
+
+		for id, name := range groups {
+			body.FriendGroups = append(body.FriendGroups, &pb.CMsgClientFriendsGroupsList_FriendGroup{
+				NGroupID:     proto.Int32(id),
+				StrGroupName: proto.String(name),
+			})
+		}
+
+		for _, m := range memberships {
+			body.Memberships = append(body.Memberships, &pb.CMsgClientFriendsGroupsList_FriendGroupsMembership{
+				UlSteamID: proto.Uint64(m.id.Uint64()),
+				NGroupID:  proto.Int32(m.group),
+			})
+		}
+
+		client.handle(steamlang.EMsg_ClientFriendsGroupsList, body, 0)
+
+		if event, ok := client.event().(*FriendGroupsListEvent); !ok || event.Incremental != (removal || incremental) {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	}
+
+	expect := func(step string, expected map[int32]socialcache.FriendGroup) {
+		t.Helper()
+
+		if groups := client.Social.FriendGroups.GetCopy(); !reflect.DeepEqual(groups, expected) {
+			t.Errorf("%s: expected groups %+v, got %+v", step, expected, groups)
+		}
+	}
+
+	members := func(ids ...steamid.SteamID) map[steamid.SteamID]bool {
+		m := make(map[steamid.SteamID]bool)
+
+		for _, id := range ids {
+			m[id] = true
+		}
+
+		return m
+	}
+
+	groupsList(false, false, map[int32]string{1: "a", 2: "b"}, membership{testFriendID, 1}, membership{testOtherID, 2})
+	expect("full", map[int32]socialcache.FriendGroup{
+		1: {ID: 1, Name: "a", Members: members(testFriendID)},
+		2: {ID: 2, Name: "b", Members: members(testOtherID)},
+	})
+
+	groupsList(false, true, map[int32]string{1: "renamed", 3: "c"}, membership{testOtherID, 1})
+	expect("incremental", map[int32]socialcache.FriendGroup{
+		1: {ID: 1, Name: "renamed", Members: members(testFriendID, testOtherID)},
+		2: {ID: 2, Name: "b", Members: members(testOtherID)},
+		3: {ID: 3, Name: "c", Members: members()},
+	})
+
+	groupsList(true, false, map[int32]string{3: ""}, membership{testFriendID, 1})
+	expect("removal", map[int32]socialcache.FriendGroup{
+		1: {ID: 1, Name: "renamed", Members: members(testOtherID)},
+		2: {ID: 2, Name: "b", Members: members(testOtherID)},
+	})
+
+	groupsList(false, false, map[int32]string{4: "d"})
+	expect("full replacement", map[int32]socialcache.FriendGroup{
+		4: {ID: 4, Name: "d", Members: members()},
+	})
+}
+
+func TestSocialCreateFriendGroup(t *testing.T) {
+	client := newTestClient(t)
+
+	type createResult struct {
+		id  int32
+		err error
+	}
+
+	create := func() (<-chan createResult, *pb.CMsgClientCreateFriendsGroup, protocol.JobID) {
+		done := make(chan createResult, 1)
+
+		go func() {
+			id, err := client.Social.CreateFriendGroup(context.Background(), "friends", testFriendID)
+			done <- createResult{id, err}
+		}()
+
+		packet := client.next()
+		body := &pb.CMsgClientCreateFriendsGroup{}
+
+		if _, err := packet.ReadProtoMsg(body); err != nil {
+			t.Fatalf("ReadProtoMsg: %v", err)
+		}
+
+		return done, body, packet.SourceJobID()
+	}
+
+	done, body, job := create()
+
+	if body.GetGroupname() != "friends" || !reflect.DeepEqual(body.GetSteamidFriends(), []uint64{testFriendID.Uint64()}) {
+		t.Errorf("unexpected request %v", body)
+	}
+
+	client.handle(steamlang.EMsg_AMClientCreateFriendsGroupResponse, &pb.CMsgClientCreateFriendsGroupResponse{
+		Eresult: proto.Uint32(uint32(steamlang.EResult_OK)),
+		Groupid: proto.Int32(7),
+	}, job)
+
+	client.event()
+
+	if r := <-done; r.err != nil || r.id != 7 {
+		t.Errorf("unexpected result %d, %v", r.id, r.err)
+	}
+
+	done, _, job = create()
+
+	client.handle(steamlang.EMsg_AMClientCreateFriendsGroupResponse, &pb.CMsgClientCreateFriendsGroupResponse{
+		Eresult: proto.Uint32(uint32(steamlang.EResult_LimitExceeded)),
+	}, job)
+
+	client.event()
+
+	if r := <-done; r.err == nil {
+		t.Error("expected error for failed creation")
+	}
+
+	done, _, job = create()
+
+	client.jobs.resolve(job, &DeleteFriendGroupEvent{})
+
+	if r := <-done; r.err == nil {
+		t.Error("expected error for unexpected job result")
+	}
+}
+
+func TestSocialFavorites(t *testing.T) {
+	client := newTestClient(t)
+	client.Social.Friends.Add(socialcache.Friend{SteamID: testFriendID})
+	client.Social.Friends.Add(socialcache.Friend{SteamID: testOtherID})
+
+	groupsList := func(removal, incremental bool, groupID int32, name string, members ...steamid.SteamID) {
+		body := &pb.CMsgClientFriendsGroupsList{Bremoval: proto.Bool(removal), Bincremental: proto.Bool(incremental)}
+
+		// memberships only, if name is empty
+		if name != "" {
+			body.FriendGroups = append(body.FriendGroups, &pb.CMsgClientFriendsGroupsList_FriendGroup{
+				NGroupID:     proto.Int32(groupID),
+				StrGroupName: proto.String(name),
+			})
+		}
+
+		for _, id := range members {
+			body.Memberships = append(body.Memberships, &pb.CMsgClientFriendsGroupsList_FriendGroupsMembership{
+				UlSteamID: proto.Uint64(id.Uint64()),
+				NGroupID:  proto.Int32(groupID),
+			})
+		}
+
+		client.handle(steamlang.EMsg_ClientFriendsGroupsList, body, 0)
+		client.event()
+	}
+
+	expect := func(step string, friend, other bool) {
+		t.Helper()
+
+		if f, o := client.Social.IsFavorite(testFriendID), client.Social.IsFavorite(testOtherID); f != friend || o != other {
+			t.Errorf("%s: expected favorites %v/%v, got %v/%v", step, friend, other, f, o)
+		}
+
+		friends := client.Social.Friends.GetCopy()
+
+		if friends[testFriendID].Favorite != friend || friends[testOtherID].Favorite != other {
+			t.Errorf("%s: unexpected cached favorites %+v", step, friends)
+		}
+	}
+
+	// requests the change and answers it with the given response, returning the request
+	call := func(f func() error, emsg steamlang.EMsg, response proto.Message) *protocol.Packet {
+		t.Helper()
+
+		done := make(chan error, 1)
+
+		go func() { done <- f() }()
+
+		packet := client.next()
+		client.handle(emsg, response, packet.SourceJobID())
+		client.event()
+
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		return packet
+	}
+
+	// removing without a favorites group sends nothing
+	if err := client.Social.RemoveFavorite(context.Background(), testFriendID); err != nil {
+		t.Errorf("RemoveFavorite: %v", err)
+	}
+
+	packet := call(func() error {
+		return client.Social.SetFavorite(context.Background(), testFriendID, true)
+	}, steamlang.EMsg_AMClientCreateFriendsGroupResponse, &pb.CMsgClientCreateFriendsGroupResponse{
+		Eresult: proto.Uint32(uint32(steamlang.EResult_OK)),
+		Groupid: proto.Int32(9),
+	})
+
+	create := &pb.CMsgClientCreateFriendsGroup{}
+
+	if _, err := packet.ReadProtoMsg(create); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	if friends := create.GetSteamidFriends(); create.GetGroupname() != FavoritesGroupName || len(friends) != 1 ||
+		friends[0] != testFriendID.Uint64() {
+		t.Errorf("unexpected request %v", create)
+	}
+
+	// cached when echoed
+	expect("created", false, false)
+	groupsList(false, true, 9, FavoritesGroupName, testFriendID)
+	expect("echoed creation", true, false)
+
+	packet = call(func() error {
+		return client.Social.SetFavorite(context.Background(), testOtherID, true)
+	}, steamlang.EMsg_AMClientAddFriendToGroupResponse, &pb.CMsgClientAddFriendToGroupResponse{
+		Eresult: proto.Uint32(uint32(steamlang.EResult_OK)),
+	})
+
+	add := &pb.CMsgClientAddFriendToGroup{}
+
+	if _, err := packet.ReadProtoMsg(add); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	if add.GetGroupid() != 9 || add.GetSteamiduser() != testOtherID.Uint64() {
+		t.Errorf("unexpected request %v", add)
+	}
+
+	groupsList(false, true, 9, "", testOtherID)
+	expect("echoed addition", true, true)
+
+	packet = call(func() error {
+		return client.Social.RemoveFavorite(context.Background(), testFriendID)
+	}, steamlang.EMsg_AMClientRemoveFriendFromGroupResponse, &pb.CMsgClientRemoveFriendFromGroupResponse{
+		Eresult: proto.Uint32(uint32(steamlang.EResult_OK)),
+	})
+
+	remove := &pb.CMsgClientRemoveFriendFromGroup{}
+
+	if _, err := packet.ReadProtoMsg(remove); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	if remove.GetGroupid() != 9 || remove.GetSteamiduser() != testFriendID.Uint64() {
+		t.Errorf("unexpected request %v", remove)
+	}
+
+	groupsList(true, false, 9, "", testFriendID)
+	expect("echoed removal", false, true)
+
+	// other groups are not favorites
+	groupsList(false, false, 10, "Other", testFriendID, testOtherID)
+	expect("full replacement", false, false)
+}
@@ -0,0 +1,168 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// FriendInviteLinkBaseURL is the base URL of friend invite links.
+const FriendInviteLinkBaseURL = "https://s.team/p/"
+
+// FriendInviteLink is a friend invite link (`https://s.team/p/code/token`), which adds the user it
+// belongs to as a friend when redeemed.
+type FriendInviteLink struct {
+	// Quick invite code, see steamid.SteamID.QuickInviteCode
+	Code    string
+	Token   string
+	SteamID steamid.SteamID `json:",string"`
+}
+
+// NewFriendInviteLink returns the friend invite link of a token created by the given user.
+func NewFriendInviteLink(id steamid.SteamID, token string) *FriendInviteLink {
+	return &FriendInviteLink{
+		Code:    id.QuickInviteCode(),
+		Token:   token,
+		SteamID: id,
+	}
+}
+
+// ParseFriendInviteLink parses a friend invite link. The scheme is optional.
+func ParseFriendInviteLink(link string) (*FriendInviteLink, error) {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+
+	u, err := url.Parse(link)
+
+	if err != nil {
+		return nil, fmt.Errorf("steam/social: invalid friend invite link: %v", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	if u.Host != "s.team" || len(parts) != 3 || parts[0] != "p" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("steam/social: invalid friend invite link %q", link)
+	}
+
+	id, err := steamid.ParseQuickInviteCode(parts[1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendInviteLink{Code: parts[1], Token: parts[2], SteamID: id}, nil
+}
+
+// URL returns the URL of the invite link.
+func (l *FriendInviteLink) URL() string {
+	return FriendInviteLinkBaseURL + l.Code + "/" + l.Token
+}
+
+// FriendInviteToken is a friend invite token of the local user.
+type FriendInviteToken struct {
+	Token string
+	// How many times the token can be redeemed
+	Limit uint64
+	// How long the token is valid for after its creation
+	Duration time.Duration
+	Created  time.Time
+	Valid    bool
+	Link     *FriendInviteLink
+}
+
+// CreateFriendInviteToken creates a friend invite token that can be redeemed limit times within
+// validFor. Limit and validFor are left to Steam's defaults if zero.
+func (s *Social) CreateFriendInviteToken(
+	ctx context.Context,
+	limit uint32,
+	validFor time.Duration,
+	note string,
+) (*FriendInviteToken, error) {
+	req := &pbc.CUserAccount_CreateFriendInviteToken_Request{}
+
+	if limit != 0 {
+		req.InviteLimit = proto.Uint32(limit)
+	}
+
+	if validFor != 0 {
+		req.InviteDuration = proto.Uint32(uint32(validFor / time.Second))
+	}
+
+	if note != "" {
+		req.InviteNote = proto.String(note)
+	}
+
+	resp := &pbc.CUserAccount_CreateFriendInviteToken_Response{}
+
+	if err := s.client.CallService(ctx, "UserAccount.CreateFriendInviteToken#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return s.newFriendInviteToken(resp), nil
+}
+
+// GetFriendInviteTokens fetches the friend invite tokens of the local user.
+func (s *Social) GetFriendInviteTokens(ctx context.Context) ([]*FriendInviteToken, error) {
+	req := &pbc.CUserAccount_GetFriendInviteTokens_Request{}
+	resp := &pbc.CUserAccount_GetFriendInviteTokens_Response{}
+
+	if err := s.client.CallService(ctx, "UserAccount.GetFriendInviteTokens#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*FriendInviteToken, 0, len(resp.GetTokens()))
+
+	for _, t := range resp.GetTokens() {
+		tokens = append(tokens, s.newFriendInviteToken(t))
+	}
+
+	return tokens, nil
+}
+
+// RevokeFriendInviteToken revokes a friend invite token of the local user.
+func (s *Social) RevokeFriendInviteToken(ctx context.Context, token string) error {
+	req := &pbc.CUserAccount_RevokeFriendInviteToken_Request{InviteToken: proto.String(token)}
+
+	return s.client.CallService(ctx, "UserAccount.RevokeFriendInviteToken#1", req, nil)
+}
+
+// RedeemFriendInviteToken redeems a friend invite token created by the given user, adding them as a
+// friend.
+func (s *Social) RedeemFriendInviteToken(ctx context.Context, id steamid.SteamID, token string) error {
+	req := &pbc.CUserAccount_RedeemFriendInviteToken_Request{
+		Steamid:     proto.Uint64(id.Uint64()),
+		InviteToken: proto.String(token),
+	}
+
+	return s.client.CallService(ctx, "UserAccount.RedeemFriendInviteToken#1", req, nil)
+}
+
+// RedeemFriendInviteLink parses and redeems a friend invite link, see ParseFriendInviteLink.
+func (s *Social) RedeemFriendInviteLink(ctx context.Context, link string) error {
+	l, err := ParseFriendInviteLink(link)
+
+	if err != nil {
+		return err
+	}
+
+	return s.RedeemFriendInviteToken(ctx, l.SteamID, l.Token)
+}
+
+func (s *Social) newFriendInviteToken(t *pbc.CUserAccount_CreateFriendInviteToken_Response) *FriendInviteToken {
+	return &FriendInviteToken{
+		Token:    t.GetInviteToken(),
+		Limit:    t.GetInviteLimit(),
+		Duration: time.Duration(t.GetInviteDuration()) * time.Second,
+		Created:  unixTimeOrZero(t.GetTimeCreated()),
+		Valid:    t.GetValid(),
+		Link:     NewFriendInviteLink(s.client.SteamID(), t.GetInviteToken()),
+	}
+}
@@ -0,0 +1,84 @@
+package steam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+func TestParseFriendInviteLink(t *testing.T) {
+	id := steamid.AccountID(69038686).SteamID()
+
+	testCases := []struct {
+		Subject  string
+		Expected *FriendInviteLink
+		Err      string
+	}{
+		{
+			Subject:  "https://s.team/p/gct-kdhv/ABCDEFGH",
+			Expected: &FriendInviteLink{Code: "gct-kdhv", Token: "ABCDEFGH", SteamID: id},
+		},
+		{
+			Subject:  "s.team/p/gct-kdhv/ABCDEFGH/",
+			Expected: &FriendInviteLink{Code: "gct-kdhv", Token: "ABCDEFGH", SteamID: id},
+		},
+		{
+			Subject: "https://steamcommunity.com/p/gct-kdhv/ABCDEFGH",
+			Err:     `steam/social: invalid friend invite link "https://steamcommunity.com/p/gct-kdhv/ABCDEFGH"`,
+		},
+		{
+			Subject: "s.team/chat/gct-kdhv/ABCDEFGH",
+			Err:     `steam/social: invalid friend invite link "https://s.team/chat/gct-kdhv/ABCDEFGH"`,
+		},
+		{
+			Subject: "s.team/p/gct-kdhv",
+			Err:     `steam/social: invalid friend invite link "https://s.team/p/gct-kdhv"`,
+		},
+		{
+			Subject: "s.team/p//ABCDEFGH",
+			Err:     `steam/social: invalid friend invite link "https://s.team/p//ABCDEFGH"`,
+		},
+		{
+			Subject: "s.team/p/gct-kdhv//",
+			Err:     `steam/social: invalid friend invite link "https://s.team/p/gct-kdhv//"`,
+		},
+		{
+			Subject: "s.team/p/gct-kdha/ABCDEFGH",
+			Err:     `steamid: invalid quick invite code "gct-kdha"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseFriendInviteLink(testCase.Subject)
+
+		if testCase.Err != "" {
+			if err == nil || err.Error() != testCase.Err {
+				t.Errorf("%q: expected error %q, got %v", testCase.Subject, testCase.Err, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", testCase.Subject, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, testCase.Expected) {
+			t.Errorf("%q: expected %+v, got %+v", testCase.Subject, testCase.Expected, actual)
+		}
+	}
+}
+
+func TestFriendInviteLinkURL(t *testing.T) {
+	link := NewFriendInviteLink(steamid.AccountID(69038686).SteamID(), "ABCDEFGH")
+
+	if u := link.URL(); u != "https://s.team/p/gct-kdhv/ABCDEFGH" {
+		t.Errorf("unexpected URL %q", u)
+	}
+
+	if parsed, err := ParseFriendInviteLink(link.URL()); err != nil || !reflect.DeepEqual(parsed, link) {
+		t.Errorf("expected %+v, got %+v, %v", link, parsed, err)
+	}
+}
@@ -0,0 +1,384 @@
+package steam
+
+import (
+	"context"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/bbcode"
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/steamid"
+)
+
+// FriendMessages provides access to the FriendMessages service, which is used by the current Steam
+// chat for messages between friends.
+//
+// Unlike Social.SendMessage, messages sent through the service get a server timestamp and are
+// delivered to all sessions of both accounts, including the Steam client and web chat.
+type FriendMessages struct {
+	client *Client
+}
+
+var _ protocol.PacketHandler = (*FriendMessages)(nil)
+
+func NewFriendMessages(client *Client) *FriendMessages {
+	return &FriendMessages{client: client}
+}
+
+// SendMessageOptions are the options of FriendMessages.SendMessageWithOptions.
+type SendMessageOptions struct {
+	// Defaults to EChatEntryType_ChatMsg
+	EntryType steamlang.EChatEntryType
+	// Whether the message is formatted with BBCode. Otherwise Steam escapes it.
+	ContainsBBCode bool
+	// Whether the message is delivered to the other sessions of the account, as a FriendMessageEvent
+	// with Echo set.
+	EchoToSender bool
+	// Low priority messages don't trigger notifications
+	LowPriority bool
+	// Arbitrary ID, echoed back to the other sessions of the account
+	ClientMessageID string
+}
+
+// SentFriendMessage is the response to sending a message.
+type SentFriendMessage struct {
+	// The message as stored by Steam, which may have been modified (for example by the link filter)
+	ModifiedMessage      string
+	MessageWithoutBBCode string
+	ServerTimestamp      time.Time
+	// Orders messages sent at the same ServerTimestamp
+	Ordinal uint32
+}
+
+// SendMessage sends a BBCode formatted chat message to a friend, echoing it to the other sessions
+// of the account like the Steam client does.
+func (f *FriendMessages) SendMessage(
+	ctx context.Context,
+	to steamid.SteamID,
+	message string,
+) (*SentFriendMessage, error) {
+	return f.SendMessageWithOptions(ctx, to, message, &SendMessageOptions{
+		ContainsBBCode: true,
+		EchoToSender:   true,
+	})
+}
+
+// SendMessageWithOptions sends a message to a friend.
+func (f *FriendMessages) SendMessageWithOptions(
+	ctx context.Context,
+	to steamid.SteamID,
+	message string,
+	opts *SendMessageOptions,
+) (*SentFriendMessage, error) {
+	if opts == nil {
+		opts = &SendMessageOptions{}
+	}
+
+	entryType := opts.EntryType
+
+	if entryType == steamlang.EChatEntryType_Invalid {
+		entryType = steamlang.EChatEntryType_ChatMsg
+	}
+
+	req := &pbc.CFriendMessages_SendMessage_Request{
+		Steamid:        proto.Uint64(to.Uint64()),
+		ChatEntryType:  proto.Int32(int32(entryType)),
+		Message:        proto.String(message),
+		ContainsBbcode: proto.Bool(opts.ContainsBBCode),
+		EchoToSender:   proto.Bool(opts.EchoToSender),
+		LowPriority:    proto.Bool(opts.LowPriority),
+	}
+
+	if opts.ClientMessageID != "" {
+		req.ClientMessageId = proto.String(opts.ClientMessageID)
+	}
+
+	resp := &pbc.CFriendMessages_SendMessage_Response{}
+
+	if err := f.client.CallService(ctx, "FriendMessages.SendMessage#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	return &SentFriendMessage{
+		ModifiedMessage:      resp.GetModifiedMessage(),
+		MessageWithoutBBCode: resp.GetMessageWithoutBbCode(),
+		ServerTimestamp:      time.Unix(int64(resp.GetServerTimestamp()), 0),
+		Ordinal:              resp.GetOrdinal(),
+	}, nil
+}
+
+// SendTyping tells a friend that the account is typing a message.
+func (f *FriendMessages) SendTyping(ctx context.Context, to steamid.SteamID) error {
+	_, err := f.SendMessageWithOptions(ctx, to, "", &SendMessageOptions{
+		EntryType:    steamlang.EChatEntryType_Typing,
+		EchoToSender: true,
+	})
+
+	return err
+}
+
+// AckMessage marks the messages of a friend received up to the given timestamp (usually the
+// Timestamp of the last FriendMessageEvent) as read.
+func (f *FriendMessages) AckMessage(partner steamid.SteamID, timestamp time.Time) {
+	f.client.SendServiceNotification("FriendMessages.AckMessage#1", &pbc.CFriendMessages_AckMessage_Notification{
+		SteamidPartner: proto.Uint64(partner.Uint64()),
+		Timestamp:      proto.Uint32(uint32(timestamp.Unix())),
+	})
+}
+
+// RecentMessagesOptions are the options of FriendMessages.GetRecentMessages.
+type RecentMessagesOptions struct {
+	// Maximum number of messages, Steam's default if 0
+	Count uint32
+	// Only messages sent after this time, if not zero
+	StartTime time.Time
+	// Whether messages are returned with BBCode, otherwise emoticons and stickers are plain text
+	BBCodeFormat bool
+	// Only messages sent before this one, to fetch further pages
+	Before *FriendMessage
+}
+
+// FriendMessage is a message of the history of a conversation.
+type FriendMessage struct {
+	Sender    steamid.SteamID `json:",string"`
+	Timestamp time.Time
+	Ordinal   uint32
+	Message   string
+}
+
+// Parse parses the BBCode of the message.
+func (m *FriendMessage) Parse() []*bbcode.Node {
+	return bbcode.Parse(m.Message)
+}
+
+// RecentFriendMessages is a page of the history of a conversation, newest messages first.
+type RecentFriendMessages struct {
+	Messages      []*FriendMessage
+	MoreAvailable bool
+}
+
+// GetRecentMessages fetches the history of the conversation with a friend.
+func (f *FriendMessages) GetRecentMessages(
+	ctx context.Context,
+	friend steamid.SteamID,
+	opts *RecentMessagesOptions,
+) (*RecentFriendMessages, error) {
+	if opts == nil {
+		opts = &RecentMessagesOptions{}
+	}
+
+	me := f.client.SteamID()
+
+	req := &pbc.CFriendMessages_GetRecentMessages_Request{
+		Steamid1:     proto.Uint64(me.Uint64()),
+		Steamid2:     proto.Uint64(friend.Uint64()),
+		BbcodeFormat: proto.Bool(opts.BBCodeFormat),
+	}
+
+	if opts.Count != 0 {
+		req.Count = proto.Uint32(opts.Count)
+	}
+
+	if !opts.StartTime.IsZero() {
+		req.Rtime32StartTime = proto.Uint32(uint32(opts.StartTime.Unix()))
+	}
+
+	if opts.Before != nil {
+		req.TimeLast = proto.Uint32(uint32(opts.Before.Timestamp.Unix()))
+		req.OrdinalLast = proto.Uint32(opts.Before.Ordinal)
+	}
+
+	resp := &pbc.CFriendMessages_GetRecentMessages_Response{}
+
+	if err := f.client.CallService(ctx, "FriendMessages.GetRecentMessages#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	result := &RecentFriendMessages{MoreAvailable: resp.GetMoreAvailable()}
+
+	for _, m := range resp.GetMessages() {
+		sender := friend
+
+		if m.GetAccountid() == uint32(me.AccountID()) {
+			sender = me
+		}
+
+		result.Messages = append(result.Messages, &FriendMessage{
+			Sender:    sender,
+			Timestamp: time.Unix(int64(m.GetTimestamp()), 0),
+			Ordinal:   m.GetOrdinal(),
+			Message:   m.GetMessage(),
+		})
+	}
+
+	return result, nil
+}
+
+// FriendMessageSession is a conversation with a friend.
+type FriendMessageSession struct {
+	SteamID     steamid.SteamID `json:",string"`
+	LastMessage time.Time
+	// When the account last read the conversation
+	LastView    time.Time
+	UnreadCount uint32
+}
+
+// GetActiveMessageSessions fetches the conversations with a message sent after the given time, or
+// all recent conversations if it's zero.
+func (f *FriendMessages) GetActiveMessageSessions(
+	ctx context.Context,
+	since time.Time,
+) ([]*FriendMessageSession, error) {
+	req := &pbc.CFriendsMessages_GetActiveMessageSessions_Request{
+		OnlySessionsWithMessages: proto.Bool(true),
+	}
+
+	if !since.IsZero() {
+		req.LastmessageSince = proto.Uint32(uint32(since.Unix()))
+	}
+
+	resp := &pbc.CFriendsMessages_GetActiveMessageSessions_Response{}
+
+	if err := f.client.CallService(ctx, "FriendMessages.GetActiveMessageSessions#1", req, resp); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*FriendMessageSession, 0, len(resp.GetMessageSessions()))
+
+	for _, s := range resp.GetMessageSessions() {
+		sessions = append(sessions, &FriendMessageSession{
+			SteamID:     steamid.AccountID(s.GetAccountidFriend()).SteamID(),
+			LastMessage: unixTimeOrZero(s.GetLastMessage()),
+			LastView:    unixTimeOrZero(s.GetLastView()),
+			UnreadCount: s.GetUnreadMessageCount(),
+		})
+	}
+
+	return sessions, nil
+}
+
+// GetUnreadMessages fetches the messages of friends that were not read yet, usually the ones
+// received while offline, keyed by friend. The messages of each friend are sorted newest first.
+//
+// Messages are not marked as read, see AckMessage.
+func (f *FriendMessages) GetUnreadMessages(ctx context.Context) (map[steamid.SteamID][]*FriendMessage, error) {
+	sessions, err := f.GetActiveMessageSessions(ctx, time.Time{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	unread := make(map[steamid.SteamID][]*FriendMessage)
+
+	for _, session := range sessions {
+		if session.UnreadCount == 0 {
+			continue
+		}
+
+		messages, err := f.getMessagesSince(ctx, session.SteamID, session.LastView, session.UnreadCount)
+
+		if err != nil {
+			return nil, err
+		}
+
+		unread[session.SteamID] = messages
+	}
+
+	return unread, nil
+}
+
+// getMessagesSince pages through the history of a conversation, collecting up to count messages
+// sent by the friend after the given time.
+func (f *FriendMessages) getMessagesSince(
+	ctx context.Context,
+	friend steamid.SteamID,
+	since time.Time,
+	count uint32,
+) ([]*FriendMessage, error) {
+	var messages []*FriendMessage
+
+	opts := &RecentMessagesOptions{StartTime: since, BBCodeFormat: true}
+
+	for {
+		page, err := f.GetRecentMessages(ctx, friend, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range page.Messages {
+			if m.Sender == friend && m.Timestamp.After(since) {
+				messages = append(messages, m)
+			}
+
+			if uint32(len(messages)) == count {
+				return messages, nil
+			}
+		}
+
+		if !page.MoreAvailable || len(page.Messages) == 0 {
+			return messages, nil
+		}
+
+		opts.Before = page.Messages[len(page.Messages)-1]
+	}
+}
+
+func (f *FriendMessages) HandlePacket(packet *protocol.Packet) {
+	if packet.EMsg() != steamlang.EMsg_ServiceMethod {
+		return
+	}
+
+	switch packet.TargetJobName() {
+	case "FriendMessagesClient.IncomingMessage#1":
+		f.handleIncomingMessage(packet)
+	case "FriendMessagesClient.NotifyAckMessageEcho#1":
+		f.handleAckMessageEcho(packet)
+	}
+}
+
+func (f *FriendMessages) handleIncomingMessage(packet *protocol.Packet) {
+	body := &pbc.CFriendMessages_IncomingMessage_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		f.client.Errorf("friendmessages/IncomingMessage: error reading message: %v", err)
+		return
+	}
+
+	friend := steamid.SteamID(body.GetSteamidFriend())
+	entryType := steamlang.EChatEntryType(body.GetChatEntryType())
+
+	if entryType == steamlang.EChatEntryType_Typing {
+		f.client.Emit(&FriendTypingEvent{SteamID: friend, Echo: body.GetLocalEcho()})
+		return
+	}
+
+	f.client.Emit(&FriendMessageEvent{
+		SteamID:            friend,
+		EntryType:          entryType,
+		Message:            body.GetMessage(),
+		MessageNoBBCode:    body.GetMessageNoBbcode(),
+		Timestamp:          time.Unix(int64(body.GetRtime32ServerTimestamp()), 0),
+		Ordinal:            body.GetOrdinal(),
+		FromLimitedAccount: body.GetFromLimitedAccount(),
+		LowPriority:        body.GetLowPriority(),
+		Echo:               body.GetLocalEcho(),
+	})
+}
+
+func (f *FriendMessages) handleAckMessageEcho(packet *protocol.Packet) {
+	body := &pbc.CFriendMessages_AckMessage_Notification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		f.client.Errorf("friendmessages/AckMessageEcho: error reading message: %v", err)
+		return
+	}
+
+	f.client.Emit(&FriendMessageAckEvent{
+		SteamID:   steamid.SteamID(body.GetSteamidPartner()),
+		Timestamp: time.Unix(int64(body.GetTimestamp()), 0),
+	})
+}
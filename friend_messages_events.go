@@ -0,0 +1,52 @@
+package steam
+
+import (
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/bbcode"
+	"github.com/13k/go-steam/steamid"
+)
+
+// Fired when a friend sends a message through the FriendMessages service, or when a message sent
+// by another session of the account is echoed to this one.
+type FriendMessageEvent struct {
+	// The friend, also for echoed messages
+	SteamID   steamid.SteamID `json:",string"`
+	EntryType steamlang.EChatEntryType
+	// BBCode formatted message
+	Message         string
+	MessageNoBBCode string
+	Timestamp       time.Time
+	// Orders messages with the same Timestamp
+	Ordinal            uint32
+	FromLimitedAccount bool
+	LowPriority        bool
+	// Whether the message was sent by another session of the account
+	Echo bool
+}
+
+// Whether the type is ChatMsg
+func (e *FriendMessageEvent) IsMessage() bool {
+	return e.EntryType == steamlang.EChatEntryType_ChatMsg
+}
+
+// Parse parses the BBCode of the message.
+func (e *FriendMessageEvent) Parse() []*bbcode.Node {
+	return bbcode.Parse(e.Message)
+}
+
+// Fired when a friend is typing a message, or when another session of the account is typing one to
+// the friend.
+type FriendTypingEvent struct {
+	SteamID steamid.SteamID `json:",string"`
+	Echo    bool
+}
+
+// Fired when another session of the account marks the messages of a friend received up to
+// Timestamp as read.
+type FriendMessageAckEvent struct {
+	SteamID   steamid.SteamID `json:",string"`
+	Timestamp time.Time
+}
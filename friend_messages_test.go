@@ -0,0 +1,74 @@
+package steam
+
+import (
+	"testing"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const testFriendID steamid.SteamID = 76561197960287930
+
+func TestFriendMessagesIncomingMessage(t *testing.T) {
+	client := newTestClient(t)
+
+	client.notify("FriendMessagesClient.IncomingMessage#1", &pbc.CFriendMessages_IncomingMessage_Notification{
+		SteamidFriend:          proto.Uint64(testFriendID.Uint64()),
+		ChatEntryType:          proto.Int32(int32(steamlang.EChatEntryType_ChatMsg)),
+		Message:                proto.String("[b]hi[/b]"),
+		MessageNoBbcode:        proto.String("hi"),
+		Rtime32ServerTimestamp: proto.Uint32(1600000000),
+		Ordinal:                proto.Uint32(2),
+		LocalEcho:              proto.Bool(true),
+	})
+
+	event, ok := client.event().(*FriendMessageEvent)
+
+	if !ok {
+		t.Fatal("expected FriendMessageEvent")
+	}
+
+	if event.SteamID != testFriendID || !event.IsMessage() || event.Message != "[b]hi[/b]" ||
+		event.MessageNoBBCode != "hi" || event.Ordinal != 2 || !event.Echo {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if !event.Timestamp.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected timestamp %v", event.Timestamp)
+	}
+
+	client.notify("FriendMessagesClient.IncomingMessage#1", &pbc.CFriendMessages_IncomingMessage_Notification{
+		SteamidFriend: proto.Uint64(testFriendID.Uint64()),
+		ChatEntryType: proto.Int32(int32(steamlang.EChatEntryType_Typing)),
+	})
+
+	if typing, ok := client.event().(*FriendTypingEvent); !ok || typing.SteamID != testFriendID || typing.Echo {
+		t.Errorf("unexpected typing event %+v", typing)
+	}
+
+	// notifications of other services are ignored
+	client.notify("FriendMessages.IncomingMessage#1", &pbc.CFriendMessages_IncomingMessage_Notification{
+		SteamidFriend: proto.Uint64(testFriendID.Uint64()),
+	})
+
+	client.noEvent()
+}
+
+func TestFriendMessagesAckMessageEcho(t *testing.T) {
+	client := newTestClient(t)
+
+	client.notify("FriendMessagesClient.NotifyAckMessageEcho#1", &pbc.CFriendMessages_AckMessage_Notification{
+		SteamidPartner: proto.Uint64(testFriendID.Uint64()),
+		Timestamp:      proto.Uint32(1600000000),
+	})
+
+	event, ok := client.event().(*FriendMessageAckEvent)
+
+	if !ok || event.SteamID != testFriendID || !event.Timestamp.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
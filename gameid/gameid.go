@@ -0,0 +1,143 @@
+// Package gameid provides types and functions to represent and manipulate a GameID, which
+// identifies a Steam app, a mod of a Steam app or a non-Steam game (shortcut).
+//
+// https://developer.valvesoftware.com/wiki/SteamID#GameID
+package gameid
+
+import (
+	"hash/crc32"
+	"strconv"
+)
+
+const (
+	AppIDOffset uint   = 0
+	AppIDMask   uint64 = 0xFFFFFF
+	TypeOffset  uint   = 24
+	TypeMask    uint64 = 0xFF
+	ModIDOffset uint   = 32
+	ModIDMask   uint64 = 0xFFFFFFFF
+
+	// modIDFlag is always set in the mod ID of mods and shortcuts.
+	modIDFlag uint32 = 0x80000000
+)
+
+// Type is the type of a GameID.
+type Type uint8
+
+const (
+	TypeApp      Type = 0
+	TypeGameMod  Type = 1
+	TypeShortcut Type = 2
+	TypeP2P      Type = 3
+)
+
+var typeNames = map[Type]string{
+	TypeApp:      "App",
+	TypeGameMod:  "GameMod",
+	TypeShortcut: "Shortcut",
+	TypeP2P:      "P2P",
+}
+
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+
+	return "Invalid"
+}
+
+// GameID is a game identifier.
+type GameID uint64
+
+// New creates a GameID with explicit parameters.
+func New(t Type, appID uint32, modID uint32) GameID {
+	return GameID(0).
+		SetType(t).
+		SetAppID(appID).
+		SetModID(modID)
+}
+
+// NewApp creates the GameID of a Steam app.
+func NewApp(appID uint32) GameID {
+	return New(TypeApp, appID, 0)
+}
+
+// NewMod creates the GameID of a mod of a Steam app, identified by the CRC32 of its directory.
+func NewMod(appID uint32, modDir string) GameID {
+	return New(TypeGameMod, appID, crc32.ChecksumIEEE([]byte(modDir))|modIDFlag)
+}
+
+// NewShortcut creates the GameID of a non-Steam game, identified by the CRC32 of its name.
+func NewShortcut(name string) GameID {
+	return New(TypeShortcut, 0, crc32.ChecksumIEEE([]byte(name))|modIDFlag)
+}
+
+func (id GameID) get(offset uint, mask uint64) uint64 {
+	return (uint64(id) >> offset) & mask
+}
+
+func (id GameID) set(offset uint, mask, value uint64) GameID {
+	return GameID((uint64(id) & ^(mask << offset)) | (value&mask)<<offset)
+}
+
+func (id GameID) AppID() uint32 {
+	return uint32(id.get(AppIDOffset, AppIDMask))
+}
+
+func (id GameID) SetAppID(appID uint32) GameID {
+	return id.set(AppIDOffset, AppIDMask, uint64(appID))
+}
+
+func (id GameID) Type() Type {
+	return Type(id.get(TypeOffset, TypeMask))
+}
+
+func (id GameID) SetType(t Type) GameID {
+	return id.set(TypeOffset, TypeMask, uint64(t))
+}
+
+func (id GameID) ModID() uint32 {
+	return uint32(id.get(ModIDOffset, ModIDMask))
+}
+
+func (id GameID) SetModID(modID uint32) GameID {
+	return id.set(ModIDOffset, ModIDMask, uint64(modID))
+}
+
+func (id GameID) IsApp() bool {
+	return id.Type() == TypeApp
+}
+
+func (id GameID) IsMod() bool {
+	return id.Type() == TypeGameMod
+}
+
+func (id GameID) IsShortcut() bool {
+	return id.Type() == TypeShortcut
+}
+
+func (id GameID) IsP2P() bool {
+	return id.Type() == TypeP2P
+}
+
+// IsValid reports whether the GameID is well formed for its type.
+func (id GameID) IsValid() bool {
+	switch id.Type() {
+	case TypeApp:
+		return id.AppID() != 0 && id.ModID() == 0
+	case TypeGameMod:
+		return id.AppID() != 0 && id.ModID()&modIDFlag != 0
+	case TypeShortcut, TypeP2P:
+		return id.ModID()&modIDFlag != 0
+	}
+
+	return false
+}
+
+func (id GameID) Uint64() uint64 {
+	return uint64(id)
+}
+
+func (id GameID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,76 @@
+package gameid_test
+
+import (
+	"testing"
+
+	"github.com/13k/go-steam/gameid"
+)
+
+func TestGameID(t *testing.T) {
+	testCases := []struct {
+		Subject gameid.GameID
+		Type    gameid.Type
+		AppID   uint32
+		ModID   uint32
+		Valid   bool
+		String  string
+	}{
+		{
+			Subject: gameid.NewApp(440),
+			Type:    gameid.TypeApp,
+			AppID:   440,
+			Valid:   true,
+			String:  "440",
+		},
+		{
+			Subject: gameid.GameID(0),
+			Type:    gameid.TypeApp,
+			String:  "0",
+		},
+		{
+			Subject: gameid.NewMod(215, "tf"),
+			Type:    gameid.TypeGameMod,
+			AppID:   215,
+			ModID:   0xae5b6a60,
+			Valid:   true,
+			String:  "12563752546046312663",
+		},
+		{
+			Subject: gameid.NewShortcut("Half-Life 3"),
+			Type:    gameid.TypeShortcut,
+			ModID:   0xad2d9e53,
+			Valid:   true,
+			String:  "12478804221866541056",
+		},
+		{
+			Subject: gameid.New(gameid.TypeShortcut, 0, 1),
+			Type:    gameid.TypeShortcut,
+			ModID:   1,
+			String:  "4328521728",
+		},
+	}
+
+	for _, testCase := range testCases {
+		id := testCase.Subject
+
+		if id.Type() != testCase.Type {
+			t.Errorf("%d: expected type %s, got %s", id, testCase.Type, id.Type())
+		}
+
+		if id.AppID() != testCase.AppID {
+			t.Errorf("%d: expected app ID %d, got %d", id, testCase.AppID, id.AppID())
+		}
+
+		if id.ModID() != testCase.ModID {
+			t.Errorf("%d: expected mod ID %#x, got %#x", id, testCase.ModID, id.ModID())
+		}
+
+		if id.IsValid() != testCase.Valid {
+			t.Errorf("%d: expected valid %v, got %v", id, testCase.Valid, id.IsValid())
+		}
+
+		if id.String() != testCase.String {
+			t.Errorf("%d: expected string %q, got %q", id, testCase.String, id.String())
+		}
+	}
+}
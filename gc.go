@@ -2,9 +2,11 @@ package steam
 
 import (
 	"bytes"
+	"sync"
 
 	pb "github.com/13k/go-steam-resources/protobuf/steam"
 	"github.com/13k/go-steam-resources/steamlang"
+	"github.com/13k/go-steam/gameid"
 	"github.com/13k/go-steam/protocol"
 	"github.com/13k/go-steam/protocol/gc"
 	"google.golang.org/protobuf/proto"
@@ -13,6 +15,10 @@ import (
 type GameCoordinator struct {
 	client   *Client
 	handlers []gc.PacketHandler
+
+	mutex          sync.RWMutex
+	playingBlocked bool
+	playingAppID   uint32
 }
 
 var _ protocol.PacketHandler = (*GameCoordinator)(nil)
@@ -29,10 +35,15 @@ func (g *GameCoordinator) RegisterPacketHandler(handler gc.PacketHandler) {
 }
 
 func (g *GameCoordinator) HandlePacket(packet *protocol.Packet) {
-	if packet.EMsg() != steamlang.EMsg_ClientFromGC {
-		return
+	switch packet.EMsg() {
+	case steamlang.EMsg_ClientFromGC:
+		g.handleFromGC(packet)
+	case steamlang.EMsg_ClientPlayingSessionState:
+		g.handlePlayingSessionState(packet)
 	}
+}
 
+func (g *GameCoordinator) handleFromGC(packet *protocol.Packet) {
 	msg := &pb.CMsgGCClient{}
 
 	if _, err := packet.ReadProtoMsg(msg); err != nil {
@@ -76,15 +87,82 @@ func (g *GameCoordinator) Write(msg gc.Message) error {
 
 // Sets you in the given games. Specify none to quit all games.
 func (g *GameCoordinator) SetGamesPlayed(appIDs ...uint64) {
-	games := make([]*pb.CMsgClientGamesPlayed_GamePlayed, len(appIDs))
+	games := make([]GamePlayed, len(appIDs))
 
 	for i, appID := range appIDs {
-		games[i] = &pb.CMsgClientGamesPlayed_GamePlayed{
-			GameId: proto.Uint64(appID),
+		games[i] = GamePlayed{GameID: gameid.GameID(appID)}
+	}
+
+	g.SetGames(games...)
+}
+
+// GamePlayed is a game to be set as played, see SetGames.
+type GamePlayed struct {
+	GameID gameid.GameID
+	// Name shown to friends for non-Steam games
+	Name string
+}
+
+// NonSteamGame returns a non-Steam game shown to friends with the given name.
+func NonSteamGame(name string) GamePlayed {
+	return GamePlayed{GameID: gameid.NewShortcut(name), Name: name}
+}
+
+// SetGames sets you in the given games, which can be non-Steam games with custom names. Specify
+// none to quit all games.
+//
+// If the account is playing in another session, Steam blocks playing and a
+// PlayingSessionStateEvent is fired, see KickPlayingSession.
+func (g *GameCoordinator) SetGames(games ...GamePlayed) {
+	played := make([]*pb.CMsgClientGamesPlayed_GamePlayed, len(games))
+
+	for i, game := range games {
+		played[i] = &pb.CMsgClientGamesPlayed_GamePlayed{
+			GameId: proto.Uint64(game.GameID.Uint64()),
+		}
+
+		if game.Name != "" {
+			played[i].GameExtraInfo = proto.String(game.Name)
 		}
 	}
 
 	g.client.Write(protocol.NewProtoMessage(steamlang.EMsg_ClientGamesPlayed, &pb.CMsgClientGamesPlayed{
-		GamesPlayed: games,
+		GamesPlayed: played,
 	}))
 }
+
+// PlayingBlocked returns whether playing is blocked because the account is playing in another
+// session, and the app being played there.
+func (g *GameCoordinator) PlayingBlocked() (bool, uint32) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	return g.playingBlocked, g.playingAppID
+}
+
+// KickPlayingSession kicks the other session the account is playing in, so that games can be
+// played in this one. If onlyStopGame is set, the other session is not logged off, only its game
+// is stopped.
+func (g *GameCoordinator) KickPlayingSession(onlyStopGame bool) {
+	g.client.Write(protocol.NewProtoMessage(steamlang.EMsg_ClientKickPlayingSession, &pb.CMsgClientKickPlayingSession{
+		OnlyStopGame: proto.Bool(onlyStopGame),
+	}))
+}
+
+func (g *GameCoordinator) handlePlayingSessionState(packet *protocol.Packet) {
+	body := &pb.CMsgClientPlayingSessionState{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		g.client.Errorf("gc/PlayingSessionState: error reading message: %v", err)
+		return
+	}
+
+	g.mutex.Lock()
+	g.playingBlocked = body.GetPlayingBlocked()
+	g.playingAppID = body.GetPlayingApp()
+	g.mutex.Unlock()
+
+	g.client.Emit(&PlayingSessionStateEvent{
+		PlayingBlocked: body.GetPlayingBlocked(),
+		PlayingAppID:   body.GetPlayingApp(),
+	})
+}
@@ -0,0 +1,9 @@
+package steam
+
+// Fired when the account starts or stops playing in another session. While PlayingBlocked is set,
+// games set as played in this session are stopped.
+type PlayingSessionStateEvent struct {
+	PlayingBlocked bool
+	// App being played in the other session
+	PlayingAppID uint32
+}
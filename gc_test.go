@@ -0,0 +1,131 @@
+package steam
+
+import (
+	"testing"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/gameid"
+	"github.com/13k/go-steam/protocol"
+)
+
+// GameID of the non-Steam game "My Game": shortcut type, with the CRC32 of the name in the mod ID.
+const testShortcutGameID gameid.GameID = 0xf701f96402000000
+
+func readGamesPlayed(t *testing.T, client *testClient) *pb.CMsgClientGamesPlayed {
+	t.Helper()
+
+	packet := client.next()
+
+	if emsg := packet.EMsg(); emsg != steamlang.EMsg_ClientGamesPlayed {
+		t.Fatalf("unexpected EMsg %v", emsg)
+	}
+
+	body := &pb.CMsgClientGamesPlayed{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	return body
+}
+
+func TestSetGames(t *testing.T) {
+	client := newTestClient(t)
+
+	client.GC.SetGames(GamePlayed{GameID: gameid.NewApp(570)}, NonSteamGame("My Game"))
+
+	games := readGamesPlayed(t, client).GetGamesPlayed()
+
+	if len(games) != 2 {
+		t.Fatalf("expected 2 games, got %v", games)
+	}
+
+	if id := games[0].GetGameId(); id != 570 || games[0].GameExtraInfo != nil {
+		t.Errorf("unexpected Steam game %v", games[0])
+	}
+
+	if id := gameid.GameID(games[1].GetGameId()); id != testShortcutGameID || id.Type() != gameid.TypeShortcut {
+		t.Errorf("expected shortcut GameID %d, got %d", testShortcutGameID, id)
+	}
+
+	if info := games[1].GetGameExtraInfo(); info != "My Game" {
+		t.Errorf("expected game_extra_info %q, got %q", "My Game", info)
+	}
+}
+
+func TestSetGamesPlayed(t *testing.T) {
+	client := newTestClient(t)
+
+	client.GC.SetGamesPlayed(570, 730)
+
+	expected := &pb.CMsgClientGamesPlayed{
+		GamesPlayed: []*pb.CMsgClientGamesPlayed_GamePlayed{
+			{GameId: proto.Uint64(570)},
+			{GameId: proto.Uint64(730)},
+		},
+	}
+
+	if body := readGamesPlayed(t, client); !proto.Equal(body, expected) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+
+	client.GC.SetGamesPlayed()
+
+	if games := readGamesPlayed(t, client).GetGamesPlayed(); len(games) != 0 {
+		t.Errorf("expected no games, got %v", games)
+	}
+}
+
+func newPlayingSessionState(blocked bool, appID uint32) *protocol.ProtoMessage {
+	return protocol.NewProtoMessage(steamlang.EMsg_ClientPlayingSessionState, &pb.CMsgClientPlayingSessionState{
+		PlayingBlocked: proto.Bool(blocked),
+		PlayingApp:     proto.Uint32(appID),
+	})
+}
+
+func TestPlayingSessionState(t *testing.T) {
+	client := newTestClient(t)
+
+	client.handleMessage(newPlayingSessionState(true, 570))
+
+	event, ok := client.event().(*PlayingSessionStateEvent)
+
+	if !ok || !event.PlayingBlocked || event.PlayingAppID != 570 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if blocked, appID := client.GC.PlayingBlocked(); !blocked || appID != 570 {
+		t.Errorf("expected playing to be blocked by app 570, got %v, %d", blocked, appID)
+	}
+
+	client.GC.KickPlayingSession(true)
+
+	packet := client.next()
+
+	if emsg := packet.EMsg(); emsg != steamlang.EMsg_ClientKickPlayingSession {
+		t.Fatalf("unexpected EMsg %v", emsg)
+	}
+
+	body := &pb.CMsgClientKickPlayingSession{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	if !body.GetOnlyStopGame() {
+		t.Errorf("expected only_stop_game, got %v", body)
+	}
+
+	client.handleMessage(newPlayingSessionState(false, 0))
+
+	if event, ok := client.event().(*PlayingSessionStateEvent); !ok || event.PlayingBlocked {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if blocked, _ := client.GC.PlayingBlocked(); blocked {
+		t.Error("expected playing not to be blocked")
+	}
+}
@@ -0,0 +1,107 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+// ErrDisconnected is returned by awaitable calls when the client disconnects before the response
+// arrives.
+var ErrDisconnected = errors.New("steam: disconnected")
+
+// jobs tracks the messages sent with a source job ID whose responses are being awaited.
+//
+// Packets can only be read once, so the handler of a response packet resolves the job with the
+// result it decoded (usually the event it emits) instead of the packet itself.
+type jobs struct {
+	mutex   sync.Mutex
+	pending map[protocol.JobID]chan interface{}
+}
+
+func newJobs() *jobs {
+	return &jobs{pending: make(map[protocol.JobID]chan interface{})}
+}
+
+func (j *jobs) add(id protocol.JobID) <-chan interface{} {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ch := make(chan interface{}, 1)
+	j.pending[id] = ch
+
+	return ch
+}
+
+func (j *jobs) remove(id protocol.JobID) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	delete(j.pending, id)
+}
+
+// resolve delivers the result to the job with the given ID, if it is being awaited.
+func (j *jobs) resolve(id protocol.JobID, result interface{}) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ch, ok := j.pending[id]
+
+	if !ok {
+		return
+	}
+
+	delete(j.pending, id)
+	ch <- result
+}
+
+// unexpectedResultError is returned by awaitable calls when a job is resolved with a result of an
+// unexpected type.
+func unexpectedResultError(result interface{}) error {
+	return fmt.Errorf("steam: unexpected job result %T", result)
+}
+
+// cancelAll closes all pending jobs, making their callers return ErrDisconnected.
+func (j *jobs) cancelAll() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for id, ch := range j.pending {
+		delete(j.pending, id)
+		close(ch)
+	}
+}
+
+// call writes a message with a new source job ID and waits until the handler of the response
+// packet resolves the job.
+//
+// Handlers emit their events before resolving jobs and emitting blocks while the event channel is
+// full, so the awaitable methods built on call should not be used from the goroutine that polls
+// Events().
+func (c *Client) call(ctx context.Context, msg protocol.Message) (interface{}, error) {
+	id := c.NextJobID()
+	msg.SetSourceJobID(id)
+
+	ch := c.jobs.add(id)
+	defer c.jobs.remove(id)
+
+	// checked after adding the job, since disconnecting cancels all jobs added before
+	if !c.Connected() {
+		return nil, ErrDisconnected
+	}
+
+	c.Write(msg)
+
+	select {
+	case result, ok := <-ch:
+		if !ok {
+			return nil, ErrDisconnected
+		}
+
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
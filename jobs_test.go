@@ -0,0 +1,111 @@
+package steam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+func TestJobsResolve(t *testing.T) {
+	j := newJobs()
+	ch := j.add(1)
+
+	// unknown jobs are ignored
+	j.resolve(2, "ignored")
+	j.resolve(1, "result")
+
+	if result := <-ch; result != "result" {
+		t.Errorf("unexpected result %v", result)
+	}
+
+	// a job is resolved only once
+	j.resolve(1, "again")
+
+	select {
+	case result := <-ch:
+		t.Errorf("unexpected second result %v", result)
+	default:
+	}
+}
+
+func TestJobsCancelAll(t *testing.T) {
+	j := newJobs()
+	a, b := j.add(1), j.add(2)
+
+	j.cancelAll()
+
+	for _, ch := range []<-chan interface{}{a, b} {
+		if _, ok := <-ch; ok {
+			t.Error("expected canceled job channel to be closed")
+		}
+	}
+
+	if len(j.pending) != 0 {
+		t.Errorf("expected no pending jobs, got %d", len(j.pending))
+	}
+}
+
+func TestClientCall(t *testing.T) {
+	client := newTestClient(t)
+
+	type callResult struct {
+		result interface{}
+		err    error
+	}
+
+	call := func(ctx context.Context) <-chan callResult {
+		done := make(chan callResult, 1)
+
+		go func() {
+			result, err := client.call(ctx, protocol.NewProtoMessage(steamlang.EMsg_ClientHeartBeat, &pb.CMsgClientHeartBeat{}))
+			done <- callResult{result, err}
+		}()
+
+		return done
+	}
+
+	done := call(context.Background())
+	packet := client.next()
+
+	client.jobs.resolve(packet.SourceJobID(), "result")
+
+	if r := <-done; r.err != nil || r.result != "result" {
+		t.Errorf("unexpected call result %v, %v", r.result, r.err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done = call(ctx)
+	packet = client.next()
+
+	cancel()
+
+	if r := <-done; r.err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", r.err)
+	}
+
+	client.jobs.mutex.Lock()
+	_, pending := client.jobs.pending[packet.SourceJobID()]
+	client.jobs.mutex.Unlock()
+
+	if pending {
+		t.Error("expected canceled call to remove its job")
+	}
+
+	done = call(context.Background())
+	client.next()
+	client.jobs.cancelAll()
+
+	select {
+	case r := <-done:
+		if r.err != ErrDisconnected {
+			t.Errorf("expected ErrDisconnected, got %v", r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for canceled call")
+	}
+}
@@ -17,3 +17,11 @@ func (u *UintBool) UnmarshalJSON(data []byte) error {
 	*u = n != 0
 	return nil
 }
+
+// MarshalJSON encodes the value as 1 or 0, like Steam does.
+func (u UintBool) MarshalJSON() ([]byte, error) {
+	if u {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
@@ -0,0 +1,330 @@
+package steam
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/steamid"
+)
+
+const (
+	// DefaultPersonaTTL is the default time personas are cached for.
+	DefaultPersonaTTL = 5 * time.Minute
+	// DefaultPersonaBatchWindow is the default time persona requests are coalesced for.
+	DefaultPersonaBatchWindow = 100 * time.Millisecond
+
+	// maximum number of SteamIDs requested in a single message
+	personaBatchSize = 100
+
+	personaRequestFlags = steamlang.EClientPersonaStateFlag_PlayerName |
+		steamlang.EClientPersonaStateFlag_Presence |
+		steamlang.EClientPersonaStateFlag_LastSeen |
+		steamlang.EClientPersonaStateFlag_GameExtraInfo |
+		steamlang.EClientPersonaStateFlag_RichPresence
+)
+
+// Persona is the persona state of a user.
+type Persona struct {
+	SteamID    steamid.SteamID `json:",string"`
+	Name       string
+	Avatar     string
+	State      steamlang.EPersonaState
+	StateFlags steamlang.EPersonaStateFlag
+	GameAppID  uint32
+	GameID     uint64 `json:",string"`
+	GameName   string
+	LastLogOff time.Time
+	LastLogOn  time.Time
+	LastSeen   time.Time
+	// Rich presence of the game being played
+	RichPresence map[string]string
+	// When the persona was received
+	Updated time.Time
+}
+
+// Personas resolves and caches the persona state of users, friends or not.
+//
+// Requests made by GetPersonas within BatchWindow are coalesced into a single request. Cached
+// personas are kept up to date with the persona states sent by Steam until they expire, and
+// expired personas are evicted.
+type Personas struct {
+	// How long personas are cached for
+	TTL time.Duration
+	// How long requests are coalesced for
+	BatchWindow time.Duration
+
+	client    *Client
+	mutex     sync.Mutex
+	cache     map[steamid.SteamID]*Persona
+	inflight  map[steamid.SteamID]*personaRequest
+	queue     []steamid.SteamID
+	timer     *time.Timer
+	lastEvict time.Time
+}
+
+// personaRequest is a pending persona request, shared by the GetPersonas calls waiting for it.
+type personaRequest struct {
+	done    chan struct{}
+	waiters int
+}
+
+func NewPersonas(client *Client) *Personas {
+	return &Personas{
+		TTL:         DefaultPersonaTTL,
+		BatchWindow: DefaultPersonaBatchWindow,
+		client:      client,
+		cache:       make(map[steamid.SteamID]*Persona),
+		inflight:    make(map[steamid.SteamID]*personaRequest),
+	}
+}
+
+// Cached returns a copy of the cached persona of a user, if it's not expired.
+func (p *Personas) Cached(id steamid.SteamID) (*Persona, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	persona, ok := p.fresh(id)
+
+	if !ok {
+		return nil, false
+	}
+
+	c := *persona
+
+	return &c, true
+}
+
+// Invalidate removes the cached persona of a user.
+func (p *Personas) Invalidate(id steamid.SteamID) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.cache, id)
+}
+
+// GetPersonas returns the personas of the given users, requesting the ones not cached from Steam and
+// waiting for them to arrive.
+//
+// Steam doesn't respond for invalid SteamIDs, so ctx should have a deadline.
+func (p *Personas) GetPersonas(ctx context.Context, ids []steamid.SteamID) (map[steamid.SteamID]*Persona, error) {
+	waiting := make(map[steamid.SteamID]*personaRequest)
+
+	p.mutex.Lock()
+
+	for _, id := range ids {
+		if _, ok := p.fresh(id); ok {
+			continue
+		}
+
+		if _, ok := waiting[id]; ok {
+			continue
+		}
+
+		req, ok := p.inflight[id]
+
+		if !ok {
+			req = &personaRequest{done: make(chan struct{})}
+			p.inflight[id] = req
+			p.enqueue(id)
+		}
+
+		req.waiters++
+		waiting[id] = req
+	}
+
+	p.mutex.Unlock()
+
+	for _, req := range waiting {
+		select {
+		case <-req.done:
+		case <-ctx.Done():
+			p.abandon(waiting)
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	personas := make(map[steamid.SteamID]*Persona, len(ids))
+
+	for _, id := range ids {
+		if persona, ok := p.cache[id]; ok {
+			c := *persona
+			personas[id] = &c
+		}
+	}
+
+	return personas, nil
+}
+
+// GetPersona returns the persona of a user, see GetPersonas.
+func (p *Personas) GetPersona(ctx context.Context, id steamid.SteamID) (*Persona, error) {
+	personas, err := p.GetPersonas(ctx, []steamid.SteamID{id})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return personas[id], nil
+}
+
+// fresh returns the cached persona if it's not expired. The mutex must be held.
+func (p *Personas) fresh(id steamid.SteamID) (*Persona, bool) {
+	persona, ok := p.cache[id]
+
+	if !ok || p.expired(persona, time.Now()) {
+		return nil, false
+	}
+
+	return persona, true
+}
+
+func (p *Personas) expired(persona *Persona, now time.Time) bool {
+	return now.Sub(persona.Updated) > p.TTL
+}
+
+// evict removes the expired personas from the cache, at most once per TTL. The mutex must be held.
+func (p *Personas) evict(now time.Time) {
+	if now.Sub(p.lastEvict) < p.TTL {
+		return
+	}
+
+	p.lastEvict = now
+
+	for id, persona := range p.cache {
+		if p.expired(persona, now) {
+			delete(p.cache, id)
+		}
+	}
+}
+
+// abandon stops waiting for the given requests. Requests nobody waits for are forgotten, so they're
+// requested again by later calls, while the others are still resolved for their other waiters.
+func (p *Personas) abandon(waiting map[steamid.SteamID]*personaRequest) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for id, req := range waiting {
+		req.waiters--
+
+		if req.waiters == 0 && p.inflight[id] == req {
+			delete(p.inflight, id)
+		}
+	}
+}
+
+// enqueue schedules the request of a persona. The mutex must be held.
+func (p *Personas) enqueue(id steamid.SteamID) {
+	p.queue = append(p.queue, id)
+
+	if p.timer == nil {
+		p.timer = time.AfterFunc(p.BatchWindow, p.flush)
+	}
+}
+
+func (p *Personas) flush() {
+	p.mutex.Lock()
+	queue := p.queue
+	p.queue = nil
+	p.timer = nil
+	p.mutex.Unlock()
+
+	for len(queue) > 0 {
+		n := len(queue)
+
+		if n > personaBatchSize {
+			n = personaBatchSize
+		}
+
+		p.client.Social.RequestFriendListInfo(queue[:n], personaRequestFlags)
+		queue = queue[n:]
+	}
+}
+
+// update merges the persona states received by Social into the cache and resolves the requests of
+// the personas that became fresh.
+func (p *Personas) update(body *pb.CMsgClientPersonaState) {
+	flags := steamlang.EClientPersonaStateFlag(body.GetStatusFlags())
+	// only full persona states renew cached personas
+	full := flags&steamlang.EClientPersonaStateFlag_PlayerName != 0
+	now := time.Now()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.evict(now)
+
+	for _, friend := range body.GetFriends() {
+		id := steamid.SteamID(friend.GetFriendid())
+
+		if !id.AccountType().IsIndividual() {
+			continue
+		}
+
+		persona, ok := p.cache[id]
+
+		// partial updates are only merged into cached personas, since the other fields are unknown
+		if !ok && !full {
+			continue
+		}
+
+		if !ok {
+			persona = &Persona{SteamID: id}
+			p.cache[id] = persona
+		}
+
+		applyPersonaState(persona, flags, friend)
+
+		if !full {
+			continue
+		}
+
+		persona.Updated = now
+
+		if req, ok := p.inflight[id]; ok {
+			delete(p.inflight, id)
+			close(req.done)
+		}
+	}
+}
+
+func applyPersonaState(
+	persona *Persona,
+	flags steamlang.EClientPersonaStateFlag,
+	friend *pb.CMsgClientPersonaState_Friend,
+) {
+	if flags&steamlang.EClientPersonaStateFlag_PlayerName != 0 {
+		persona.Name = friend.GetPlayerName()
+	}
+
+	if flags&steamlang.EClientPersonaStateFlag_Presence != 0 {
+		if avatar := hex.EncodeToString(friend.GetAvatarHash()); protocol.ValidAvatar(avatar) {
+			persona.Avatar = avatar
+		}
+
+		persona.State = steamlang.EPersonaState(friend.GetPersonaState())
+		persona.StateFlags = steamlang.EPersonaStateFlag(friend.GetPersonaStateFlags())
+	}
+
+	if flags&steamlang.EClientPersonaStateFlag_LastSeen != 0 {
+		persona.LastLogOff = unixTimeOrZero(friend.GetLastLogoff())
+		persona.LastLogOn = unixTimeOrZero(friend.GetLastLogon())
+		persona.LastSeen = unixTimeOrZero(friend.GetLastSeenOnline())
+	}
+
+	if flags&(steamlang.EClientPersonaStateFlag_GameExtraInfo|steamlang.EClientPersonaStateFlag_GameDataBlob) != 0 {
+		persona.GameAppID = friend.GetGamePlayedAppId()
+		persona.GameID = friend.GetGameid()
+		persona.GameName = friend.GetGameName()
+	}
+
+	if flags&steamlang.EClientPersonaStateFlag_RichPresence != 0 {
+		persona.RichPresence = richPresenceValues(friend.GetRichPresence())
+	}
+}
@@ -0,0 +1,229 @@
+package steam
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const testOtherID steamid.SteamID = 76561197960287931
+
+// newPersonasTestClient returns a test client whose persona requests are only flushed by the test.
+func newPersonasTestClient(t *testing.T) *testClient {
+	client := newTestClient(t)
+	client.Personas.BatchWindow = time.Hour
+
+	return client
+}
+
+// getPersonas calls GetPersonas in the background.
+func (c *testClient) getPersonas(ctx context.Context, ids ...steamid.SteamID) <-chan map[steamid.SteamID]*Persona {
+	done := make(chan map[steamid.SteamID]*Persona, 1)
+
+	go func() {
+		personas, err := c.Personas.GetPersonas(ctx, ids)
+
+		if err != nil {
+			personas = nil
+		}
+
+		done <- personas
+	}()
+
+	return done
+}
+
+// waitPersonaWaiters waits until the request of a persona has the given number of waiters.
+func (c *testClient) waitPersonaWaiters(id steamid.SteamID, n int) {
+	c.t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		c.Personas.mutex.Lock()
+		req, ok := c.Personas.inflight[id]
+		waiters := 0
+
+		if ok {
+			waiters = req.waiters
+		}
+
+		c.Personas.mutex.Unlock()
+
+		if waiters == n {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	c.t.Fatalf("timeout waiting for %d waiters of %v", n, id)
+}
+
+// personaState makes the client handle the persona states of the given users, named after their
+// SteamIDs, and consumes the emitted events.
+func (c *testClient) personaState(flags steamlang.EClientPersonaStateFlag, ids ...steamid.SteamID) {
+	c.t.Helper()
+
+	body := &pb.CMsgClientPersonaState{StatusFlags: proto.Uint32(uint32(flags))}
+
+	for _, id := range ids {
+		body.Friends = append(body.Friends, &pb.CMsgClientPersonaState_Friend{
+			Friendid:     proto.Uint64(id.Uint64()),
+			PlayerName:   proto.String(id.String()),
+			PersonaState: proto.Uint32(uint32(steamlang.EPersonaState_Online)),
+		})
+	}
+
+	c.handle(steamlang.EMsg_ClientPersonaState, body, 0)
+
+	for range ids {
+		c.event()
+	}
+}
+
+func TestPersonasCoalesce(t *testing.T) {
+	client := newPersonasTestClient(t)
+
+	first := client.getPersonas(context.Background(), testFriendID, testOtherID)
+	second := client.getPersonas(context.Background(), testFriendID)
+
+	client.waitPersonaWaiters(testFriendID, 2)
+	client.waitPersonaWaiters(testOtherID, 1)
+	client.Personas.flush()
+
+	body := &pb.CMsgClientRequestFriendData{}
+
+	if _, err := client.next().ReadProtoMsg(body); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	requested := body.GetFriends()
+	sort.Slice(requested, func(a, b int) bool { return requested[a] < requested[b] })
+
+	if expected := []uint64{testFriendID.Uint64(), testOtherID.Uint64()}; !reflect.DeepEqual(requested, expected) {
+		t.Fatalf("expected a single request of %v, got %v", expected, requested)
+	}
+
+	client.personaState(steamlang.EClientPersonaStateFlag_PlayerName|steamlang.EClientPersonaStateFlag_Presence,
+		testFriendID, testOtherID)
+
+	personas := <-first
+
+	if len(personas) != 2 || personas[testOtherID].Name != testOtherID.String() ||
+		personas[testFriendID].State != steamlang.EPersonaState_Online {
+		t.Errorf("unexpected personas %+v", personas)
+	}
+
+	if personas := <-second; len(personas) != 1 || personas[testFriendID].Name != testFriendID.String() {
+		t.Errorf("unexpected personas %+v", personas)
+	}
+
+	// cached personas are not requested again
+	if personas := <-client.getPersonas(context.Background(), testFriendID); len(personas) != 1 {
+		t.Errorf("unexpected cached personas %+v", personas)
+	}
+}
+
+func TestPersonasTTL(t *testing.T) {
+	client := newPersonasTestClient(t)
+
+	client.personaState(steamlang.EClientPersonaStateFlag_PlayerName, testFriendID)
+
+	if persona, ok := client.Personas.Cached(testFriendID); !ok || persona.Name != testFriendID.String() {
+		t.Fatalf("expected cached persona, got %+v", persona)
+	}
+
+	client.Personas.mutex.Lock()
+	client.Personas.TTL = time.Minute
+	client.Personas.cache[testFriendID].Updated = time.Now().Add(-time.Hour)
+	client.Personas.mutex.Unlock()
+
+	if _, ok := client.Personas.Cached(testFriendID); ok {
+		t.Fatal("expected persona to expire")
+	}
+
+	done := client.getPersonas(context.Background(), testFriendID)
+	client.waitPersonaWaiters(testFriendID, 1)
+
+	// partial updates don't renew expired personas
+	client.personaState(steamlang.EClientPersonaStateFlag_Presence, testFriendID)
+
+	select {
+	case personas := <-done:
+		t.Fatalf("expected partial update not to resolve the request, got %+v", personas)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	client.personaState(steamlang.EClientPersonaStateFlag_PlayerName, testFriendID)
+
+	if personas := <-done; len(personas) != 1 {
+		t.Fatalf("unexpected personas %+v", personas)
+	}
+
+	// expired personas are evicted on the next update
+	client.Personas.mutex.Lock()
+	client.Personas.cache[testFriendID].Updated = time.Now().Add(-time.Hour)
+	client.Personas.lastEvict = time.Time{}
+	client.Personas.mutex.Unlock()
+
+	client.personaState(steamlang.EClientPersonaStateFlag_PlayerName, testOtherID)
+
+	client.Personas.mutex.Lock()
+	_, cached := client.Personas.cache[testFriendID]
+	client.Personas.mutex.Unlock()
+
+	if cached {
+		t.Error("expected expired persona to be evicted")
+	}
+}
+
+func TestPersonasCancel(t *testing.T) {
+	client := newPersonasTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := client.getPersonas(ctx, testFriendID)
+	waiting := client.getPersonas(context.Background(), testFriendID)
+
+	client.waitPersonaWaiters(testFriendID, 2)
+	cancel()
+
+	if personas := <-canceled; personas != nil {
+		t.Fatalf("expected canceled call to fail, got %+v", personas)
+	}
+
+	// the request is kept for the other waiter
+	client.waitPersonaWaiters(testFriendID, 1)
+	client.personaState(steamlang.EClientPersonaStateFlag_PlayerName, testFriendID)
+
+	select {
+	case personas := <-waiting:
+		if len(personas) != 1 {
+			t.Errorf("unexpected personas %+v", personas)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for personas")
+	}
+
+	// requests nobody waits for are forgotten
+	ctx, cancel = context.WithCancel(context.Background())
+	canceled = client.getPersonas(ctx, testOtherID)
+
+	client.waitPersonaWaiters(testOtherID, 1)
+	cancel()
+	<-canceled
+
+	client.Personas.mutex.Lock()
+	_, pending := client.Personas.inflight[testOtherID]
+	client.Personas.mutex.Unlock()
+
+	if pending {
+		t.Error("expected abandoned request to be forgotten")
+	}
+}
@@ -151,3 +151,32 @@ func (h *ProtoMessageHeader) TargetJobID() JobID {
 func (h *ProtoMessageHeader) SetTargetJobID(job JobID) {
 	h.MsgHdrProtoBuf.Proto.JobidTarget = proto.Uint64(uint64(job))
 }
+
+// TargetJobName returns the name of the service method called by (or notified with) the message,
+// in the form "Service.Method#Version".
+func (h *ProtoMessageHeader) TargetJobName() string {
+	return h.MsgHdrProtoBuf.Proto.GetTargetJobName()
+}
+
+func (h *ProtoMessageHeader) SetTargetJobName(name string) {
+	h.MsgHdrProtoBuf.Proto.TargetJobName = proto.String(name)
+}
+
+// RoutingAppID returns the app ID the message is routed to, for messages related to a game.
+func (h *ProtoMessageHeader) RoutingAppID() uint32 {
+	return h.MsgHdrProtoBuf.Proto.GetRoutingAppid()
+}
+
+func (h *ProtoMessageHeader) SetRoutingAppID(appID uint32) {
+	h.MsgHdrProtoBuf.Proto.RoutingAppid = proto.Uint32(appID)
+}
+
+// Result returns the result of a service method call response.
+func (h *ProtoMessageHeader) Result() steamlang.EResult {
+	return steamlang.EResult(h.MsgHdrProtoBuf.Proto.GetEresult())
+}
+
+// ErrorMessage returns the error message of a failed service method call response.
+func (h *ProtoMessageHeader) ErrorMessage() string {
+	return h.MsgHdrProtoBuf.Proto.GetErrorMessage()
+}
@@ -70,6 +70,24 @@ func (p *Packet) IsProto() bool        { return p.Header.IsProto() }
 func (p *Packet) SourceJobID() JobID   { return p.Header.SourceJobID() }
 func (p *Packet) TargetJobID() JobID   { return p.Header.TargetJobID() }
 
+// TargetJobName returns the service method name of protobuf packets, or an empty string.
+func (p *Packet) TargetJobName() string {
+	if header, ok := p.Header.(*ProtoMessageHeader); ok {
+		return header.TargetJobName()
+	}
+
+	return ""
+}
+
+// RoutingAppID returns the app ID protobuf packets are routed to, or 0.
+func (p *Packet) RoutingAppID() uint32 {
+	if header, ok := p.Header.(*ProtoMessageHeader); ok {
+		return header.RoutingAppID()
+	}
+
+	return 0
+}
+
 func (p *Packet) String() string {
 	return fmt.Sprintf(
 		"Packet{EMsg=%s, Proto=%v, Len=%d, TargetJobID=%d, SourceJobID=%d}",
@@ -0,0 +1,115 @@
+package steam
+
+import (
+	"bytes"
+	"sort"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/kv"
+	"github.com/13k/go-steam/protocol"
+	"github.com/13k/go-steam/steamid"
+)
+
+// richPresenceRootKey is the key of the KeyValue root node of rich presence.
+const richPresenceRootKey = "RP"
+
+// SetRichPresence sets the rich presence of the user for a game being played, see
+// https://partner.steamgames.com/doc/api/ISteamFriends#SetRichPresence. Keys that are not set are
+// removed, so an empty map clears the rich presence.
+func (s *Social) SetRichPresence(appID uint32, values map[string]string) error {
+	root := kv.NewKeyValueRoot(richPresenceRootKey)
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		root.AddString(key, values[key])
+	}
+
+	data, err := root.MarshalBinary()
+
+	if err != nil {
+		return err
+	}
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRichPresenceUpload, &pb.CMsgClientRichPresenceUpload{
+		RichPresenceKv: data,
+	})
+
+	msg.Header.SetRoutingAppID(appID)
+	s.client.Write(msg)
+
+	return nil
+}
+
+// RequestRichPresence requests the rich presence of users for a game. Steam responds with a
+// RichPresenceEvent for each user.
+func (s *Social) RequestRichPresence(appID uint32, ids ...steamid.SteamID) {
+	steamIDs := make([]uint64, len(ids))
+
+	for i, id := range ids {
+		steamIDs[i] = id.Uint64()
+	}
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRichPresenceRequest, &pb.CMsgClientRichPresenceRequest{
+		SteamidRequest: steamIDs,
+	})
+
+	msg.Header.SetRoutingAppID(appID)
+	s.client.Write(msg)
+}
+
+func (s *Social) handleRichPresenceInfo(packet *protocol.Packet) {
+	body := &pb.CMsgClientRichPresenceInfo{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/RichPresenceInfo: error reading message: %v", err)
+		return
+	}
+
+	for _, rp := range body.GetRichPresence() {
+		event := &RichPresenceEvent{
+			SteamID: steamid.SteamID(rp.GetSteamidUser()),
+			AppID:   packet.RoutingAppID(),
+			Values:  make(map[string]string),
+		}
+
+		if data := rp.GetRichPresenceKv(); len(data) > 0 {
+			root := kv.NewKeyValueEmpty()
+
+			if err := kv.NewBinaryDecoder(bytes.NewReader(data)).Decode(root); err != nil {
+				s.client.Errorf("social/RichPresenceInfo: error reading rich presence: %v", err)
+				continue
+			}
+
+			event.KeyValue = root
+
+			for _, child := range root.Children() {
+				event.Values[child.Key()] = child.Value()
+			}
+		}
+
+		s.client.Emit(event)
+	}
+}
+
+// richPresenceValues converts the rich presence of a persona state.
+func richPresenceValues(kvs []*pb.CMsgClientPersonaState_Friend_KV) map[string]string {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(kvs))
+
+	for _, pair := range kvs {
+		values[pair.GetKey()] = pair.GetValue()
+	}
+
+	return values
+}
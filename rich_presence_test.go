@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+// richPresenceKV is the binary KeyValue encoding of the rich presence in richPresenceValuesFixture.
+const richPresenceKV = "\x00RP\x00" +
+	"\x01status\x00Playing\x00" +
+	"\x01steam_display\x00#Status\x00" +
+	"\x08\x08"
+
+var richPresenceValuesFixture = map[string]string{
+	"steam_display": "#Status",
+	"status":        "Playing",
+}
+
+func TestSetRichPresence(t *testing.T) {
+	client := newTestClient(t)
+
+	if err := client.Social.SetRichPresence(570, richPresenceValuesFixture); err != nil {
+		t.Fatalf("SetRichPresence: %v", err)
+	}
+
+	packet := client.next()
+
+	if emsg := packet.EMsg(); emsg != steamlang.EMsg_ClientRichPresenceUpload {
+		t.Fatalf("unexpected EMsg %v", emsg)
+	}
+
+	if appID := packet.RoutingAppID(); appID != 570 {
+		t.Errorf("expected routing app ID 570, got %d", appID)
+	}
+
+	body := &pb.CMsgClientRichPresenceUpload{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		t.Fatalf("ReadProtoMsg: %v", err)
+	}
+
+	if data := body.GetRichPresenceKv(); !bytes.Equal(data, []byte(richPresenceKV)) {
+		t.Errorf("expected rich presence %q, got %q", richPresenceKV, data)
+	}
+}
+
+func TestRichPresenceInfo(t *testing.T) {
+	client := newTestClient(t)
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRichPresenceInfo, &pb.CMsgClientRichPresenceInfo{
+		RichPresence: []*pb.CMsgClientRichPresenceInfo_RichPresence{
+			{SteamidUser: proto.Uint64(testFriendID.Uint64()), RichPresenceKv: []byte(richPresenceKV)},
+			{SteamidUser: proto.Uint64(testOtherID.Uint64())},
+		},
+	})
+
+	msg.Header.SetRoutingAppID(570)
+	client.handleMessage(msg)
+
+	event, ok := client.event().(*RichPresenceEvent)
+
+	if !ok || event.SteamID != testFriendID || event.AppID != 570 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if !reflect.DeepEqual(event.Values, richPresenceValuesFixture) {
+		t.Errorf("expected values %v, got %v", richPresenceValuesFixture, event.Values)
+	}
+
+	if event.KeyValue == nil || event.KeyValue.Key() != richPresenceRootKey {
+		t.Errorf("unexpected KeyValue %v", event.KeyValue)
+	}
+
+	// users without rich presence have no values
+	if event, ok := client.event().(*RichPresenceEvent); !ok || event.SteamID != testOtherID ||
+		len(event.Values) != 0 || event.KeyValue != nil {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
@@ -0,0 +1,98 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+// ServiceError is returned by CallService when Steam responds to a service method call with a
+// result other than OK.
+type ServiceError struct {
+	Method  string
+	Result  steamlang.EResult
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("steam: service method %s failed: %v (%s)", e.Method, e.Result, e.Message)
+	}
+
+	return fmt.Sprintf("steam: service method %s failed: %v", e.Method, e.Result)
+}
+
+// serviceResponse is the result of service method call jobs. The body is decoded by the caller,
+// which knows its type.
+type serviceResponse struct {
+	header *protocol.ProtoMessageHeader
+	body   []byte
+}
+
+// CallService calls a unified service method, named in the form "Service.Method#Version" (for
+// example "FriendMessages.SendMessage#1"), and decodes the response body into response.
+//
+// The same restrictions of the other awaitable methods apply: it should not be called from the
+// goroutine that polls Events().
+func (c *Client) CallService(ctx context.Context, method string, request, response proto.Message) error {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ServiceMethodCallFromClient, request)
+	msg.Header.SetTargetJobName(method)
+
+	result, err := c.call(ctx, msg)
+
+	if err != nil {
+		return err
+	}
+
+	resp, ok := result.(*serviceResponse)
+
+	if !ok {
+		return unexpectedResultError(result)
+	}
+
+	if r := resp.header.Result(); r != steamlang.EResult_OK {
+		return &ServiceError{Method: method, Result: r, Message: resp.header.ErrorMessage()}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if err := proto.Unmarshal(resp.body, response); err != nil {
+		return fmt.Errorf("steam: error decoding %s response: %w", method, err)
+	}
+
+	return nil
+}
+
+// SendServiceNotification calls a unified service method without waiting for a response. It's
+// used by notification methods (for example "FriendMessages.AckMessage#1"), which have none.
+func (c *Client) SendServiceNotification(method string, request proto.Message) {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ServiceMethodCallFromClient, request)
+	msg.Header.SetTargetJobName(method)
+
+	c.Write(msg)
+}
+
+func (c *Client) handleServiceMethodResponse(packet *protocol.Packet) {
+	header, ok := packet.Header.(*protocol.ProtoMessageHeader)
+
+	if !ok {
+		c.Errorf("client/ServiceMethodResponse: invalid packet header %T", packet.Header)
+		return
+	}
+
+	body, err := ioutil.ReadAll(packet.Payload)
+
+	if err != nil {
+		c.Errorf("client/ServiceMethodResponse: error reading message: %v", err)
+		return
+	}
+
+	c.jobs.resolve(packet.TargetJobID(), &serviceResponse{header: header, body: body})
+}
@@ -0,0 +1,82 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/protocol"
+)
+
+func TestCallService(t *testing.T) {
+	client := newTestClient(t)
+
+	call := func() (<-chan error, *pbc.CFriendMessages_SendMessage_Response, *protocol.Packet) {
+		resp := &pbc.CFriendMessages_SendMessage_Response{}
+		done := make(chan error, 1)
+
+		go func() {
+			done <- client.CallService(
+				context.Background(),
+				"FriendMessages.SendMessage#1",
+				&pbc.CFriendMessages_SendMessage_Request{Message: proto.String("hi")},
+				resp,
+			)
+		}()
+
+		packet := client.next()
+
+		if emsg := packet.EMsg(); emsg != steamlang.EMsg_ServiceMethodCallFromClient {
+			t.Fatalf("unexpected EMsg %v", emsg)
+		}
+
+		if name := packet.TargetJobName(); name != "FriendMessages.SendMessage#1" {
+			t.Fatalf("unexpected target job name %q", name)
+		}
+
+		return done, resp, packet
+	}
+
+	done, resp, packet := call()
+
+	client.respond(packet, steamlang.EResult_OK, &pbc.CFriendMessages_SendMessage_Response{
+		ModifiedMessage: proto.String("hi!"),
+	})
+
+	if err := <-done; err != nil || resp.GetModifiedMessage() != "hi!" {
+		t.Errorf("unexpected response %v, %v", resp, err)
+	}
+
+	done, _, packet = call()
+
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ServiceMethodResponse, &pbc.CFriendMessages_SendMessage_Response{})
+	msg.SetTargetJobID(packet.SourceJobID())
+	msg.Header.MsgHdrProtoBuf.Proto.Eresult = proto.Int32(int32(steamlang.EResult_RateLimitExceeded))
+	msg.Header.MsgHdrProtoBuf.Proto.ErrorMessage = proto.String("slow down")
+
+	client.handleMessage(msg)
+
+	var serviceErr *ServiceError
+
+	if err := <-done; !errors.As(err, &serviceErr) {
+		t.Fatalf("expected ServiceError, got %v", err)
+	}
+
+	if serviceErr.Method != "FriendMessages.SendMessage#1" ||
+		serviceErr.Result != steamlang.EResult_RateLimitExceeded ||
+		serviceErr.Message != "slow down" {
+		t.Errorf("unexpected error %+v", serviceErr)
+	}
+
+	done, _, packet = call()
+
+	client.jobs.resolve(packet.SourceJobID(), "result")
+
+	if err := <-done; err == nil {
+		t.Error("expected error for unexpected job result")
+	}
+}
@@ -19,26 +19,43 @@ import (
 
 // Social provides access to social aspects of Steam.
 type Social struct {
-	Friends *socialcache.FriendsList
-	Groups  *socialcache.GroupsList
-	Chats   *socialcache.ChatsList
+	Friends      *socialcache.FriendsList
+	Groups       *socialcache.GroupsList
+	Chats        *socialcache.ChatsList
+	FriendGroups *socialcache.FriendGroupsList
 
 	client       *Client
 	mutex        sync.RWMutex
 	name         string
 	avatar       string
 	personaState steamlang.EPersonaState
+	nicknames    map[steamid.SteamID]string
 }
 
 func NewSocial(client *Client) *Social {
 	return &Social{
-		Friends: socialcache.NewFriendsList(),
-		Groups:  socialcache.NewGroupsList(),
-		Chats:   socialcache.NewChatsList(),
-		client:  client,
+		Friends:      socialcache.NewFriendsList(),
+		Groups:       socialcache.NewGroupsList(),
+		Chats:        socialcache.NewChatsList(),
+		FriendGroups: socialcache.NewFriendGroupsList(),
+		client:       client,
+		nicknames:    make(map[steamid.SteamID]string),
 	}
 }
 
+// Snapshot takes a snapshot of the friends, groups and chats lists, which can be persisted and
+// compared with the lists after restarting, see socialcache.Snapshot.Diff.
+func (s *Social) Snapshot() *socialcache.Snapshot {
+	return socialcache.NewSnapshot(s.Friends, s.Groups, s.Chats)
+}
+
+// Restore replaces the friends, groups and chats lists with a snapshot, so that updates sent by
+// Steam after logging on are notified as changes from the snapshot. Friends and groups removed
+// while offline are not notified, compare the snapshot with a new one to detect them.
+func (s *Social) Restore(snapshot *socialcache.Snapshot) {
+	snapshot.Restore(s.Friends, s.Groups, s.Chats)
+}
+
 // GetAvatar the local user's avatar
 func (s *Social) GetAvatar() string {
 	s.mutex.RLock()
@@ -46,6 +63,11 @@ func (s *Social) GetAvatar() string {
 	return s.avatar
 }
 
+// GetAvatarURL returns the URL of the local user's avatar image, see AvatarURL.
+func (s *Social) GetAvatarURL(size AvatarSize) string {
+	return AvatarURL(s.GetAvatar(), size)
+}
+
 // GetPersonaName the local user's persona name
 func (s *Social) GetPersonaName() string {
 	s.mutex.RLock()
@@ -158,6 +180,37 @@ func (s *Social) RequestProfileInfo(id steamid.SteamID) {
 	}))
 }
 
+// RequestOfflineMessageCount requests the number of messages received while offline. Steam
+// responds with an OfflineMessagesEvent, which is also sent unrequested after logging on.
+func (s *Social) RequestOfflineMessageCount() {
+	s.client.Write(protocol.NewProtoMessage(
+		steamlang.EMsg_ClientChatRequestOfflineMessageCount,
+		&pb.CMsgClientRequestOfflineMessageCount{},
+	))
+}
+
+// RequestOfflineMessages requests the history of the conversations with friends that sent messages
+// while the user was offline. Steam responds with a FriendMessageHistoryEvent for each friend, and an
+// OfflineMessageEvent is fired for each unread message.
+func (s *Social) RequestOfflineMessages() {
+	s.client.Write(protocol.NewProtoMessage(
+		steamlang.EMsg_ClientChatGetFriendMessageHistoryForOfflineMessages,
+		&pb.CMsgClientChatGetFriendMessageHistoryForOfflineMessages{},
+	))
+}
+
+// RequestFriendMessageHistory requests the recent history of the conversation with a friend. Steam
+// responds with a FriendMessageHistoryEvent.
+//
+// Steam only returns the most recent messages, see FriendMessages.GetRecentMessages to page through
+// the whole history.
+func (s *Social) RequestFriendMessageHistory(id steamid.SteamID) {
+	s.client.Write(protocol.NewProtoMessage(
+		steamlang.EMsg_ClientChatGetFriendMessageHistory,
+		&pb.CMsgClientChatGetFriendMessageHistory{Steamid: proto.Uint64(id.Uint64())},
+	))
+}
+
 // JoinChat attempts to join a chat room
 func (s *Social) JoinChat(id steamid.SteamID) {
 	chatID := id.ClanToChat()
@@ -253,8 +306,28 @@ func (s *Social) HandlePacket(packet *protocol.Packet) {
 		s.handleIgnoreFriendResponse(packet)
 	case steamlang.EMsg_ClientFriendProfileInfoResponse:
 		s.handleProfileInfoResponse(packet)
-		// case EMsg_ClientFSGetFriendMessageHistoryResponse:
-		// s.handleFriendMessageHistoryResponse(packet)
+	case steamlang.EMsg_ClientChatOfflineMessageNotification:
+		s.handleOfflineMessageNotification(packet)
+	case steamlang.EMsg_ClientChatGetFriendMessageHistoryResponse:
+		s.handleFriendMessageHistoryResponse(packet)
+	case steamlang.EMsg_ClientRichPresenceInfo:
+		s.handleRichPresenceInfo(packet)
+	case steamlang.EMsg_ClientPlayerNicknameList:
+		s.handlePlayerNicknameList(packet)
+	case steamlang.EMsg_AMClientSetPlayerNicknameResponse:
+		s.handleSetPlayerNicknameResponse(packet)
+	case steamlang.EMsg_ClientFriendsGroupsList:
+		s.handleFriendsGroupsList(packet)
+	case steamlang.EMsg_AMClientCreateFriendsGroupResponse:
+		s.handleCreateFriendsGroupResponse(packet)
+	case steamlang.EMsg_AMClientDeleteFriendsGroupResponse:
+		s.handleDeleteFriendsGroupResponse(packet)
+	case steamlang.EMsg_AMClientManageFriendsGroupResponse:
+		s.handleManageFriendsGroupResponse(packet)
+	case steamlang.EMsg_AMClientAddFriendToGroupResponse:
+		s.handleAddFriendToGroupResponse(packet)
+	case steamlang.EMsg_AMClientRemoveFriendFromGroupResponse:
+		s.handleRemoveFriendFromGroupResponse(packet)
 	}
 }
 
@@ -285,6 +358,8 @@ func (s *Social) handleFriendsList(packet *protocol.Packet) {
 
 			if rel == steamlang.EClanRelationship_None {
 				s.Groups.Remove(steamID)
+			} else if _, err := s.Groups.ByID(steamID); err == nil {
+				s.Groups.SetRelationship(steamID, rel)
 			} else {
 				s.Groups.Add(socialcache.Group{
 					SteamID:      steamID,
@@ -300,10 +375,15 @@ func (s *Social) handleFriendsList(packet *protocol.Packet) {
 
 			if rel == steamlang.EFriendRelationship_None {
 				s.Friends.Remove(steamID)
+				s.FriendGroups.RemoveMemberFromAll(steamID)
+			} else if _, err := s.Friends.ByID(steamID); err == nil {
+				s.Friends.SetRelationship(steamID, rel)
 			} else {
 				s.Friends.Add(socialcache.Friend{
 					SteamID:      steamID,
 					Relationship: rel,
+					Nickname:     s.GetNickname(steamID),
+					Favorite:     s.IsFavorite(steamID),
 				})
 			}
 
@@ -365,9 +445,7 @@ func (s *Social) handlePersonaState(packet *protocol.Packet) {
 				s.Friends.SetPersonaStateFlags(id, steamlang.EPersonaStateFlag(friend.GetPersonaStateFlags()))
 			}
 			if (flags & steamlang.EClientPersonaStateFlag_GameDataBlob) == steamlang.EClientPersonaStateFlag_GameDataBlob {
-				s.Friends.SetGameAppID(id, friend.GetGamePlayedAppId())
-				s.Friends.SetGameID(id, friend.GetGameid())
-				s.Friends.SetGameName(id, friend.GetGameName())
+				s.Friends.SetGame(id, friend.GetGamePlayedAppId(), friend.GetGameid(), friend.GetGameName())
 			}
 		} else if id.AccountType().IsClan() {
 			if (flags & steamlang.EClientPersonaStateFlag_PlayerName) == steamlang.EClientPersonaStateFlag_PlayerName {
@@ -405,11 +483,15 @@ func (s *Social) handlePersonaState(packet *protocol.Packet) {
 			ClanTag:                friend.GetClanTag(),
 			OnlineSessionInstances: friend.GetOnlineSessionInstances(),
 			PersonaSetByUser:       friend.GetPersonaSetByUser(),
+			RichPresence:           richPresenceValues(friend.GetRichPresence()),
 			// PublishedSessionID:     friend.GetPublishedInstanceId(),
 			// FacebookName:           friend.GetFacebookName(),
 			// FacebookID:             friend.GetFacebookId(),
 		})
 	}
+
+	// packets can only be read once, so the persona cache is updated from here
+	s.client.Personas.update(list)
 }
 
 func (s *Social) handleClanState(packet *protocol.Packet) {
@@ -479,10 +561,7 @@ func (s *Social) handleClanState(packet *protocol.Packet) {
 	*/
 
 	if body.GetUserCounts() != nil {
-		s.Groups.SetMemberTotalCount(clanid, totalCount)
-		s.Groups.SetMemberOnlineCount(clanid, onlineCount)
-		s.Groups.SetMemberChattingCount(clanid, chattingCount)
-		s.Groups.SetMemberInGameCount(clanid, ingameCount)
+		s.Groups.SetMemberCounts(clanid, totalCount, onlineCount, chattingCount, ingameCount)
 	}
 
 	s.client.Emit(&ClanStateEvent{
@@ -763,6 +842,77 @@ func (s *Social) handleProfileInfoResponse(packet *protocol.Packet) {
 	})
 }
 
+func (s *Social) handleOfflineMessageNotification(packet *protocol.Packet) {
+	body := &pb.CMsgClientOfflineMessageNotification{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/OfflineMessageNotification: error reading message: %v", err)
+		return
+	}
+
+	friends := make([]steamid.SteamID, 0, len(body.GetFriendsWithOfflineMessages()))
+
+	for _, accountID := range body.GetFriendsWithOfflineMessages() {
+		friends = append(friends, steamid.AccountID(accountID).SteamID())
+	}
+
+	s.client.Emit(&OfflineMessagesEvent{
+		Count:   body.GetOfflineMessages(),
+		Friends: friends,
+	})
+}
+
+func (s *Social) handleFriendMessageHistoryResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientChatGetFriendMessageHistoryResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("social/FriendMessageHistory: error reading message: %v", err)
+		return
+	}
+
+	friend := steamid.SteamID(body.GetSteamid())
+	friendAccountID := uint32(friend.AccountID())
+	me := s.client.SteamID()
+
+	event := &FriendMessageHistoryEvent{
+		Result:  steamlang.EResult(body.GetSuccess()),
+		SteamID: friend,
+	}
+
+	var unread []*OfflineMessageEvent
+
+	for _, m := range body.GetMessages() {
+		sender := me
+
+		if m.GetAccountid() == friendAccountID {
+			sender = friend
+		}
+
+		msg := &HistoryMessage{
+			SenderID:  sender,
+			Message:   m.GetMessage(),
+			Timestamp: time.Unix(int64(m.GetTimestamp()), 0),
+			Unread:    m.GetUnread(),
+		}
+
+		event.Messages = append(event.Messages, msg)
+
+		if msg.Unread && sender == friend {
+			unread = append(unread, &OfflineMessageEvent{
+				SteamID:   friend,
+				Message:   msg.Message,
+				Timestamp: msg.Timestamp,
+			})
+		}
+	}
+
+	s.client.Emit(event)
+
+	for _, e := range unread {
+		s.client.Emit(e)
+	}
+}
+
 func readChatMember(r io.Reader) (steamid.SteamID, steamlang.EChatPermission, steamlang.EClanPermission, error) {
 	var (
 		id         uint64
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/kv"
 	"github.com/13k/go-steam/steamid"
 )
 
@@ -52,6 +54,8 @@ type PersonaStateEvent struct {
 	PersonaSetByUser       bool
 	FacebookName           string
 	FacebookID             uint64 `json:",string"`
+	// Only set if requested with EClientPersonaStateFlag_RichPresence
+	RichPresence map[string]string
 }
 
 // Fired when a clan's state has been changed
@@ -160,3 +164,86 @@ type ProfileInfoEvent struct {
 	Headline    string
 	Summary     string
 }
+
+// Fired when the number of messages received while offline is known, after logging on or in
+// response to Social.RequestOfflineMessageCount
+type OfflineMessagesEvent struct {
+	Count uint32
+	// Friends that sent messages while offline
+	Friends []steamid.SteamID
+}
+
+// Fired in response to requesting the message history with a friend or the offline messages
+type FriendMessageHistoryEvent struct {
+	Result   steamlang.EResult
+	SteamID  steamid.SteamID `json:",string"`
+	Messages []*HistoryMessage
+}
+
+// Fired for each unread message a friend sent while offline, after the FriendMessageHistoryEvent
+// of the friend, in response to Social.RequestOfflineMessages
+type OfflineMessageEvent struct {
+	SteamID   steamid.SteamID `json:",string"`
+	Message   string
+	Timestamp time.Time
+}
+
+// A message of the history of a conversation with a friend
+type HistoryMessage struct {
+	SenderID  steamid.SteamID `json:",string"`
+	Message   string
+	Timestamp time.Time
+	Unread    bool
+}
+
+// Fired in response to requesting the rich presence of a user
+type RichPresenceEvent struct {
+	SteamID steamid.SteamID `json:",string"`
+	AppID   uint32
+	Values  map[string]string
+	// The raw rich presence, nil if it's empty
+	KeyValue kv.KeyValue `json:"-"`
+}
+
+// Fired when the nicknames given by the user are received or changed. Nicknames are cached in
+// Social.Friends.
+type NicknameListEvent struct {
+	Incremental bool
+}
+
+// Fired in response to setting or removing a nickname
+type SetNicknameEvent struct {
+	Result steamlang.EResult
+}
+
+// Fired when the friend groups (tags) of the user are received or changed. Friend groups are
+// cached in Social.FriendGroups.
+type FriendGroupsListEvent struct {
+	Incremental bool
+}
+
+// Fired in response to creating a friend group
+type CreateFriendGroupEvent struct {
+	Result  steamlang.EResult
+	GroupID int32
+}
+
+// Fired in response to deleting a friend group
+type DeleteFriendGroupEvent struct {
+	Result steamlang.EResult
+}
+
+// Fired in response to renaming a friend group or changing its friends
+type ManageFriendGroupEvent struct {
+	Result steamlang.EResult
+}
+
+// Fired in response to adding a friend to a friend group
+type AddFriendToGroupEvent struct {
+	Result steamlang.EResult
+}
+
+// Fired in response to removing a friend from a friend group
+type RemoveFriendFromGroupEvent struct {
+	Result steamlang.EResult
+}
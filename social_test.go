@@ -0,0 +1,75 @@
+package steam
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+const testMySteamID steamid.SteamID = 76561197960265729
+
+func TestSocialOfflineMessageNotification(t *testing.T) {
+	client := newTestClient(t)
+
+	client.handle(steamlang.EMsg_ClientChatOfflineMessageNotification, &pb.CMsgClientOfflineMessageNotification{
+		OfflineMessages:            proto.Uint32(3),
+		FriendsWithOfflineMessages: []uint32{uint32(testFriendID.AccountID())},
+	}, 0)
+
+	event, ok := client.event().(*OfflineMessagesEvent)
+
+	if !ok || event.Count != 3 || !reflect.DeepEqual(event.Friends, []steamid.SteamID{testFriendID}) {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestSocialFriendMessageHistoryResponse(t *testing.T) {
+	client := newTestClient(t)
+	client.setSteamID(testMySteamID)
+
+	friendAccountID := uint32(testFriendID.AccountID())
+
+	history := &pb.CMsgClientChatGetFriendMessageHistoryResponse{
+		Steamid: proto.Uint64(testFriendID.Uint64()),
+		Success: proto.Uint32(uint32(steamlang.EResult_OK)),
+		Messages: []*pb.CMsgClientChatGetFriendMessageHistoryResponse_FriendMessage{
+			{Accountid: proto.Uint32(friendAccountID), Timestamp: proto.Uint32(1600000000), Message: proto.String("read")},
+			{Accountid: proto.Uint32(uint32(testMySteamID.AccountID())), Timestamp: proto.Uint32(1600000001),
+				Message: proto.String("mine"), Unread: proto.Bool(true)},
+			{Accountid: proto.Uint32(friendAccountID), Timestamp: proto.Uint32(1600000002),
+				Message: proto.String("unread"), Unread: proto.Bool(true)},
+		},
+	}
+
+	client.handle(steamlang.EMsg_ClientChatGetFriendMessageHistoryResponse, history, 0)
+
+	event, ok := client.event().(*FriendMessageHistoryEvent)
+
+	if !ok || event.Result != steamlang.EResult_OK || event.SteamID != testFriendID || len(event.Messages) != 3 {
+		t.Fatalf("unexpected history event %+v", event)
+	}
+
+	if m := event.Messages[1]; m.SenderID != testMySteamID || m.Message != "mine" || !m.Unread {
+		t.Errorf("unexpected own message %+v", m)
+	}
+
+	if m := event.Messages[0]; m.SenderID != testFriendID || m.Unread || !m.Timestamp.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected friend message %+v", m)
+	}
+
+	// only unread messages of the friend are emitted as offline messages
+	offline, ok := client.event().(*OfflineMessageEvent)
+
+	if !ok || offline.SteamID != testFriendID || offline.Message != "unread" ||
+		!offline.Timestamp.Equal(time.Unix(1600000002, 0)) {
+		t.Errorf("unexpected offline message event %+v", offline)
+	}
+
+	client.noEvent()
+}
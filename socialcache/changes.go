@@ -0,0 +1,212 @@
+package socialcache
+
+import (
+	"sync"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// ChangeKind is the kind of a change of a list
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota + 1
+	ChangeRemoved
+	ChangeRelationship
+	ChangeName
+	ChangeAvatar
+	// Persona state or persona state flags
+	ChangePersonaState
+	// Game app ID, game ID or game name
+	ChangeGame
+	// Any of the member counts of a group
+	ChangeMemberCount
+	ChangeMemberAdded
+	ChangeMemberRemoved
+	// Chat or clan permissions of a chat member
+	ChangeMemberPermissions
+	ChangeNickname
+	ChangeFavorite
+)
+
+var changeKindNames = map[ChangeKind]string{
+	ChangeAdded:             "Added",
+	ChangeRemoved:           "Removed",
+	ChangeRelationship:      "Relationship",
+	ChangeName:              "Name",
+	ChangeAvatar:            "Avatar",
+	ChangePersonaState:      "PersonaState",
+	ChangeGame:              "Game",
+	ChangeMemberCount:       "MemberCount",
+	ChangeMemberAdded:       "MemberAdded",
+	ChangeMemberRemoved:     "MemberRemoved",
+	ChangeMemberPermissions: "MemberPermissions",
+	ChangeNickname:          "Nickname",
+	ChangeFavorite:          "Favorite",
+}
+
+func (k ChangeKind) String() string {
+	if name, ok := changeKindNames[k]; ok {
+		return name
+	}
+
+	return "Unknown"
+}
+
+// A change of a friend. Old is zero for added friends and New is zero for removed friends.
+type FriendChange struct {
+	Kind    ChangeKind
+	SteamID steamid.SteamID `json:",string"`
+	Old     Friend
+	New     Friend
+}
+
+// A change of a group. Old is zero for added groups and New is zero for removed groups.
+type GroupChange struct {
+	Kind    ChangeKind
+	SteamID steamid.SteamID `json:",string"`
+	Old     Group
+	New     Group
+}
+
+// A change of a chat or of one of its members. Member is only set for member changes.
+type ChatChange struct {
+	Kind    ChangeKind
+	SteamID steamid.SteamID `json:",string"`
+	Member  ChatMember
+}
+
+// Subscription is a feed of the changes of a list, see FriendsList.Subscribe.
+//
+// Changes are queued and delivered in order by a goroutine of the subscription, so publishing
+// never blocks the list, even if changes are not received.
+type Subscription struct {
+	c     chan interface{}
+	done  chan struct{}
+	once  sync.Once
+	feed  *feed
+	mutex sync.Mutex
+	queue []interface{}
+	// signaled when changes are queued
+	ready chan struct{}
+}
+
+// C returns the channel changes are delivered to.
+func (s *Subscription) C() <-chan interface{} {
+	return s.c
+}
+
+// Unsubscribe stops the delivery of changes, discarding the undelivered ones. The channel is not
+// closed.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.feed.remove(s)
+		close(s.done)
+	})
+}
+
+// push queues changes for delivery.
+func (s *Subscription) push(changes []interface{}) {
+	s.mutex.Lock()
+	s.queue = append(s.queue, changes...)
+	s.mutex.Unlock()
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the next queued change.
+func (s *Subscription) pop() (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil, false
+	}
+
+	change := s.queue[0]
+	s.queue[0] = nil
+	s.queue = s.queue[1:]
+
+	return change, true
+}
+
+// deliver sends the queued changes to the channel until the subscription is cancelled.
+func (s *Subscription) deliver() {
+	for {
+		select {
+		case <-s.ready:
+		case <-s.done:
+			return
+		}
+
+		for {
+			change, ok := s.pop()
+
+			if !ok {
+				break
+			}
+
+			select {
+			case s.c <- change:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+const subscriptionBuffer = 32
+
+// feed delivers changes to subscriptions. The zero value is ready to use.
+type feed struct {
+	mutex sync.Mutex
+	subs  []*Subscription
+}
+
+func (f *feed) subscribe() *Subscription {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	s := &Subscription{
+		c:     make(chan interface{}, subscriptionBuffer),
+		done:  make(chan struct{}),
+		feed:  f,
+		ready: make(chan struct{}, 1),
+	}
+
+	f.subs = append(f.subs, s)
+
+	go s.deliver()
+
+	return s
+}
+
+func (f *feed) remove(s *Subscription) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for i, sub := range f.subs {
+		if sub == s {
+			f.subs = append(f.subs[:i:i], f.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish queues changes for all subscriptions without blocking. It's called with the list mutex
+// held, so changes are queued in the order they're applied.
+func (f *feed) publish(changes ...interface{}) {
+	if len(changes) == 0 {
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, s := range f.subs {
+		s.push(changes)
+	}
+}
@@ -0,0 +1,202 @@
+package socialcache
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	pbc "github.com/13k/go-steam-resources/protobuf/steam/client"
+	"github.com/13k/go-steam/steamid"
+)
+
+// ChatRoomGroupsList is a thread safe map of the chat room groups the account is a member of,
+// keyed by chat group ID.
+// They can be iterated over like so:
+//
+//	for id, group := range client.ChatRooms.Groups.GetCopy() {
+//		log.Println(id, group.Name)
+//	}
+type ChatRoomGroupsList struct {
+	mutex sync.RWMutex
+	byID  map[uint64]*ChatRoomGroup
+}
+
+// Returns a new chat room groups list
+func NewChatRoomGroupsList() *ChatRoomGroupsList {
+	return &ChatRoomGroupsList{byID: make(map[uint64]*ChatRoomGroup)}
+}
+
+// Set adds a chat room group to the list, replacing it if it already exists
+func (list *ChatRoomGroupsList) Set(group ChatRoomGroup) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID[group.ChatGroupID] = group.copy()
+}
+
+// Removes a chat room group from the list
+func (list *ChatRoomGroupsList) Remove(id uint64) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	delete(list.byID, id)
+}
+
+// Removes all chat room groups from the list
+func (list *ChatRoomGroupsList) Clear() {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID = make(map[uint64]*ChatRoomGroup)
+}
+
+// Returns a copy of the chat room groups map
+func (list *ChatRoomGroupsList) GetCopy() map[uint64]ChatRoomGroup {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	glist := make(map[uint64]ChatRoomGroup)
+	for key, group := range list.byID {
+		glist[key] = *group.copy()
+	}
+	return glist
+}
+
+// Returns a copy of the chat room group with the given ID
+func (list *ChatRoomGroupsList) ByID(id uint64) (ChatRoomGroup, error) {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	if val, ok := list.byID[id]; ok {
+		return *val.copy(), nil
+	}
+	return ChatRoomGroup{}, errors.New("Chat room group not found")
+}
+
+// Returns the number of chat room groups
+func (list *ChatRoomGroupsList) Count() int {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	return len(list.byID)
+}
+
+// Setter methods
+func (list *ChatRoomGroupsList) SetHeader(id uint64, header ChatRoomGroupHeader) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		val.ChatRoomGroupHeader = header
+	}
+}
+
+func (list *ChatRoomGroupsList) SetChatRooms(id uint64, defaultChatID uint64, rooms []ChatRoom) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		val.DefaultChatID = defaultChatID
+		val.ChatRooms = make(map[uint64]ChatRoom, len(rooms))
+		for _, room := range rooms {
+			val.ChatRooms[room.ChatID] = room
+		}
+	}
+}
+
+func (list *ChatRoomGroupsList) SetMember(id uint64, member ChatRoomMember) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		if val.Members == nil {
+			val.Members = make(map[steamid.SteamID]ChatRoomMember)
+		}
+		val.Members[member.SteamID] = member
+	}
+}
+
+func (list *ChatRoomGroupsList) RemoveMember(id uint64, member steamid.SteamID) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		delete(val.Members, member)
+	}
+}
+
+// SetLastMessage updates the last message time of a chat room
+func (list *ChatRoomGroupsList) SetLastMessage(id uint64, chatID uint64, sender steamid.SteamID, t time.Time) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		if room, ok := val.ChatRooms[chatID]; ok {
+			room.TimeLastMessage = t
+			room.LastMessageSender = sender
+			val.ChatRooms[chatID] = room
+		}
+	}
+}
+
+// A chat room group, the current kind of Steam group chat, which has chat room channels
+type ChatRoomGroup struct {
+	ChatGroupID uint64 `json:",string"`
+	ChatRoomGroupHeader
+	DefaultChatID uint64 `json:",string"`
+	// Keyed by chat ID
+	ChatRooms map[uint64]ChatRoom
+	// Only known for joined groups whose state was fetched
+	Members map[steamid.SteamID]ChatRoomMember
+	// Rank of the account in the group
+	Rank pbc.EChatRoomGroupRank
+	// IDs of the roles of the account in the group
+	RoleIDs []uint64
+}
+
+func (g *ChatRoomGroup) copy() *ChatRoomGroup {
+	c := *g
+	c.Roles = append([]ChatRole(nil), g.Roles...)
+	c.RoleIDs = append([]uint64(nil), g.RoleIDs...)
+	c.ChatRooms = make(map[uint64]ChatRoom, len(g.ChatRooms))
+	for key, room := range g.ChatRooms {
+		c.ChatRooms[key] = room
+	}
+	if g.Members != nil {
+		c.Members = make(map[steamid.SteamID]ChatRoomMember, len(g.Members))
+		for key, member := range g.Members {
+			member.RoleIDs = append([]uint64(nil), member.RoleIDs...)
+			c.Members[key] = member
+		}
+	}
+	return &c
+}
+
+// The header state of a chat room group, updated whenever it's changed
+type ChatRoomGroupHeader struct {
+	Name    string
+	Tagline string
+	// Zero if the group is not a Steam group chat
+	ClanID        steamid.SteamID `json:",string"`
+	OwnerID       steamid.SteamID `json:",string"`
+	AppID         uint32
+	AvatarSHA     []byte
+	DefaultRoleID uint64 `json:",string"`
+	Roles         []ChatRole
+}
+
+// A chat room (channel) of a chat room group
+type ChatRoom struct {
+	ChatID            uint64 `json:",string"`
+	Name              string
+	VoiceAllowed      bool
+	SortOrder         uint32
+	TimeLastMessage   time.Time
+	LastMessageSender steamid.SteamID `json:",string"`
+}
+
+// A member of a chat room group
+type ChatRoomMember struct {
+	SteamID steamid.SteamID `json:",string"`
+	State   pbc.EChatRoomJoinState
+	Rank    pbc.EChatRoomGroupRank
+	RoleIDs []uint64
+	// When the kick expires, zero if not kicked
+	KickExpires time.Time
+}
+
+// A role of a chat room group
+type ChatRole struct {
+	RoleID  uint64 `json:",string"`
+	Name    string
+	Ordinal uint32
+}
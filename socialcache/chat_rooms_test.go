@@ -0,0 +1,85 @@
+package socialcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam/socialcache"
+)
+
+func TestChatRoomGroupsList(t *testing.T) {
+	list := socialcache.NewChatRoomGroupsList()
+
+	group := socialcache.ChatRoomGroup{
+		ChatGroupID:         1000,
+		ChatRoomGroupHeader: socialcache.ChatRoomGroupHeader{Name: "Group"},
+		ChatRooms:           map[uint64]socialcache.ChatRoom{1: {ChatID: 1, Name: "General"}},
+		RoleIDs:             []uint64{5},
+	}
+
+	list.Set(group)
+
+	// the list keeps its own copy
+	group.ChatRooms[1] = socialcache.ChatRoom{ChatID: 1, Name: "Changed"}
+	group.RoleIDs[0] = 6
+
+	cached, err := list.ByID(1000)
+
+	if err != nil {
+		t.Fatalf("ByID: %v", err)
+	}
+
+	if cached.ChatRooms[1].Name != "General" || cached.RoleIDs[0] != 5 {
+		t.Errorf("expected cached group to be a copy, got %+v", cached)
+	}
+
+	// and returns copies
+	cached.ChatRooms[2] = socialcache.ChatRoom{ChatID: 2}
+
+	if copies := list.GetCopy(); len(copies[1000].ChatRooms) != 1 {
+		t.Errorf("expected copies not to share chat rooms, got %+v", copies[1000].ChatRooms)
+	}
+
+	list.SetHeader(1000, socialcache.ChatRoomGroupHeader{Name: "Renamed"})
+	list.SetChatRooms(1000, 2, []socialcache.ChatRoom{{ChatID: 2, Name: "Lobby"}})
+	list.SetMember(1000, socialcache.ChatRoomMember{SteamID: friendID})
+	list.SetMember(1000, socialcache.ChatRoomMember{SteamID: otherID})
+	list.RemoveMember(1000, otherID)
+	list.SetLastMessage(1000, 2, friendID, time.Unix(1600000000, 0))
+
+	// updates of unknown groups and rooms are ignored
+	list.SetHeader(2000, socialcache.ChatRoomGroupHeader{Name: "Unknown"})
+	list.SetMember(2000, socialcache.ChatRoomMember{SteamID: friendID})
+	list.SetLastMessage(1000, 3, friendID, time.Unix(1600000000, 0))
+
+	cached, _ = list.ByID(1000)
+
+	if cached.Name != "Renamed" || cached.DefaultChatID != 2 || len(cached.ChatRooms) != 1 {
+		t.Errorf("unexpected group %+v", cached)
+	}
+
+	if room := cached.ChatRooms[2]; room.LastMessageSender != friendID || room.TimeLastMessage.Unix() != 1600000000 {
+		t.Errorf("unexpected chat room %+v", room)
+	}
+
+	if _, ok := cached.Members[friendID]; !ok || len(cached.Members) != 1 {
+		t.Errorf("unexpected members %+v", cached.Members)
+	}
+
+	if list.Count() != 1 {
+		t.Errorf("expected 1 group, got %d", list.Count())
+	}
+
+	list.Remove(1000)
+
+	if _, err := list.ByID(1000); err == nil {
+		t.Error("expected removed group not to be found")
+	}
+
+	list.Set(socialcache.ChatRoomGroup{ChatGroupID: 1})
+	list.Clear()
+
+	if list.Count() != 0 {
+		t.Errorf("expected cleared list, got %d groups", list.Count())
+	}
+}
@@ -13,9 +13,12 @@ import (
 // 	for id, chat := range client.Social.Chats.GetCopy() {
 // 		log.Println(id, chat.Name)
 // 	}
+//
+// Changes are delivered to subscriptions as *ChatChange values, see Subscribe.
 type ChatsList struct {
 	mutex sync.RWMutex
 	byID  map[steamid.SteamID]*Chat
+	feed  feed
 }
 
 // Returns a new chats list
@@ -23,6 +26,12 @@ func NewChatsList() *ChatsList {
 	return &ChatsList{byID: make(map[steamid.SteamID]*Chat)}
 }
 
+// Subscribe returns a subscription to the changes of the list, delivered as *ChatChange values.
+// Changes are queued for each subscription, so slow receivers don't block updates of the list.
+func (list *ChatsList) Subscribe() *Subscription {
+	return list.feed.subscribe()
+}
+
 // Adds a chat to the chat list
 func (list *ChatsList) Add(chat Chat) {
 	list.mutex.Lock()
@@ -30,6 +39,7 @@ func (list *ChatsList) Add(chat Chat) {
 	_, exists := list.byID[chat.SteamID]
 	if !exists { //make sure this doesnt already exist
 		list.byID[chat.SteamID] = &chat
+		list.feed.publish(&ChatChange{Kind: ChangeAdded, SteamID: chat.SteamID})
 	}
 }
 
@@ -37,22 +47,45 @@ func (list *ChatsList) Add(chat Chat) {
 func (list *ChatsList) Remove(id steamid.SteamID) {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
+	_, exists := list.byID[id]
 	delete(list.byID, id)
+	if exists {
+		list.feed.publish(&ChatChange{Kind: ChangeRemoved, SteamID: id})
+	}
+}
+
+// Restore replaces the chats of the list, without notifying subscriptions
+func (list *ChatsList) Restore(chats map[steamid.SteamID]Chat) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID = make(map[steamid.SteamID]*Chat, len(chats))
+	for key, chat := range chats {
+		list.byID[key] = chat.copy()
+	}
 }
 
 // Adds a chat member to a given chat
 func (list *ChatsList) AddChatMember(id steamid.SteamID, member ChatMember) {
+	var changes []interface{}
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
 	chat := list.byID[id]
 	if chat == nil { //Chat doesn't exist
 		chat = &Chat{SteamID: id}
 		list.byID[id] = chat
+		changes = append(changes, &ChatChange{Kind: ChangeAdded, SteamID: id})
 	}
 	if chat.ChatMembers == nil { //New chat
 		chat.ChatMembers = make(map[steamid.SteamID]ChatMember)
 	}
+	old, exists := chat.ChatMembers[member.SteamID]
 	chat.ChatMembers[member.SteamID] = member
+	if !exists {
+		changes = append(changes, &ChatChange{Kind: ChangeMemberAdded, SteamID: id, Member: member})
+	} else if old != member {
+		changes = append(changes, &ChatChange{Kind: ChangeMemberPermissions, SteamID: id, Member: member})
+	}
+	list.feed.publish(changes...)
 }
 
 // Removes a chat member from a given chat
@@ -63,10 +96,11 @@ func (list *ChatsList) RemoveChatMember(id steamid.SteamID, member steamid.Steam
 	if chat == nil { //Chat doesn't exist
 		return
 	}
-	if chat.ChatMembers == nil { //New chat
-		return
-	}
+	old, exists := chat.ChatMembers[member]
 	delete(chat.ChatMembers, member)
+	if exists {
+		list.feed.publish(&ChatChange{Kind: ChangeMemberRemoved, SteamID: id, Member: old})
+	}
 }
 
 // Returns a copy of the chats map
@@ -75,7 +109,7 @@ func (list *ChatsList) GetCopy() map[steamid.SteamID]Chat {
 	defer list.mutex.RUnlock()
 	glist := make(map[steamid.SteamID]Chat)
 	for key, chat := range list.byID {
-		glist[key] = *chat
+		glist[key] = *chat.copy()
 	}
 	return glist
 }
@@ -85,7 +119,7 @@ func (list *ChatsList) ByID(id steamid.SteamID) (Chat, error) {
 	list.mutex.RLock()
 	defer list.mutex.RUnlock()
 	if val, ok := list.byID[id]; ok {
-		return *val, nil
+		return *val.copy(), nil
 	}
 	return Chat{}, errors.New("Chat not found")
 }
@@ -104,6 +138,17 @@ type Chat struct {
 	ChatMembers map[steamid.SteamID]ChatMember
 }
 
+func (c *Chat) copy() *Chat {
+	cp := *c
+	if c.ChatMembers != nil {
+		cp.ChatMembers = make(map[steamid.SteamID]ChatMember, len(c.ChatMembers))
+		for key, member := range c.ChatMembers {
+			cp.ChatMembers[key] = member
+		}
+	}
+	return &cp
+}
+
 // A Chat Member
 type ChatMember struct {
 	SteamID         steamid.SteamID `json:",string"`
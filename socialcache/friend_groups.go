@@ -0,0 +1,155 @@
+package socialcache
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// FriendGroupsList is a thread safe map of the friend groups (tags) of the user, keyed by group ID.
+// They can be iterated over like so:
+//
+//	for id, group := range client.Social.FriendGroups.GetCopy() {
+//		log.Println(id, group.Name, len(group.Members))
+//	}
+type FriendGroupsList struct {
+	mutex sync.RWMutex
+	byID  map[int32]*FriendGroup
+}
+
+// Returns a new friend groups list
+func NewFriendGroupsList() *FriendGroupsList {
+	return &FriendGroupsList{byID: make(map[int32]*FriendGroup)}
+}
+
+// Set adds a friend group to the list, or renames it if it already exists
+func (list *FriendGroupsList) Set(id int32, name string) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if val, ok := list.byID[id]; ok {
+		val.Name = name
+		return
+	}
+	list.byID[id] = &FriendGroup{ID: id, Name: name, Members: make(map[steamid.SteamID]bool)}
+}
+
+// Removes a friend group from the list
+func (list *FriendGroupsList) Remove(id int32) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	delete(list.byID, id)
+}
+
+// Removes all friend groups from the list
+func (list *FriendGroupsList) Clear() {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID = make(map[int32]*FriendGroup)
+}
+
+// Adds a friend to a friend group, adding the group if it doesn't exist
+func (list *FriendGroupsList) AddMember(id int32, member steamid.SteamID) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	group := list.byID[id]
+	if group == nil {
+		group = &FriendGroup{ID: id, Members: make(map[steamid.SteamID]bool)}
+		list.byID[id] = group
+	}
+	group.Members[member] = true
+}
+
+// Removes a friend from a friend group
+func (list *FriendGroupsList) RemoveMember(id int32, member steamid.SteamID) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	if group, ok := list.byID[id]; ok {
+		delete(group.Members, member)
+	}
+}
+
+// Removes a friend from all friend groups
+func (list *FriendGroupsList) RemoveMemberFromAll(member steamid.SteamID) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	for _, group := range list.byID {
+		delete(group.Members, member)
+	}
+}
+
+// Returns a copy of the friend groups map
+func (list *FriendGroupsList) GetCopy() map[int32]FriendGroup {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	glist := make(map[int32]FriendGroup)
+	for key, group := range list.byID {
+		glist[key] = *group.copy()
+	}
+	return glist
+}
+
+// Returns a copy of the friend group with the given ID
+func (list *FriendGroupsList) ByID(id int32) (FriendGroup, error) {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	if val, ok := list.byID[id]; ok {
+		return *val.copy(), nil
+	}
+	return FriendGroup{}, errors.New("Friend group not found")
+}
+
+// Returns a copy of the friend group with the given name, the one with the lowest ID if there are
+// several
+func (list *FriendGroupsList) ByName(name string) (FriendGroup, error) {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	var found *FriendGroup
+	for _, group := range list.byID {
+		if group.Name == name && (found == nil || group.ID < found.ID) {
+			found = group
+		}
+	}
+	if found == nil {
+		return FriendGroup{}, errors.New("Friend group not found")
+	}
+	return *found.copy(), nil
+}
+
+// Returns copies of the friend groups a friend is a member of, sorted by ID
+func (list *FriendGroupsList) GroupsOf(member steamid.SteamID) []FriendGroup {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	var groups []FriendGroup
+	for _, group := range list.byID {
+		if group.Members[member] {
+			groups = append(groups, *group.copy())
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// Returns the number of friend groups
+func (list *FriendGroupsList) Count() int {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+	return len(list.byID)
+}
+
+// A friend group, which friends are tagged with
+type FriendGroup struct {
+	ID      int32
+	Name    string
+	Members map[steamid.SteamID]bool
+}
+
+func (g *FriendGroup) copy() *FriendGroup {
+	c := *g
+	c.Members = make(map[steamid.SteamID]bool, len(g.Members))
+	for key := range g.Members {
+		c.Members[key] = true
+	}
+	return &c
+}
@@ -0,0 +1,85 @@
+package socialcache_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+func TestFriendGroupsList(t *testing.T) {
+	list := socialcache.NewFriendGroupsList()
+
+	list.Set(2, "b")
+	list.AddMember(2, friendID)
+	// adds the group
+	list.AddMember(1, friendID)
+	list.AddMember(1, otherID)
+	// renames without losing members
+	list.Set(1, "a")
+
+	group, err := list.ByID(1)
+
+	if err != nil {
+		t.Fatalf("ByID: %v", err)
+	}
+
+	if group.Name != "a" || len(group.Members) != 2 {
+		t.Errorf("unexpected group %+v", group)
+	}
+
+	// copies don't share members
+	group.Members[groupID] = true
+
+	if group, _ := list.ByID(1); group.Members[groupID] {
+		t.Error("expected ByID to return a copy")
+	}
+
+	groups := list.GroupsOf(friendID)
+
+	if len(groups) != 2 || groups[0].ID != 1 || groups[1].ID != 2 {
+		t.Errorf("expected groups 1 and 2 sorted by ID, got %+v", groups)
+	}
+
+	list.RemoveMember(1, otherID)
+	list.RemoveMember(3, otherID)
+
+	if groups := list.GroupsOf(otherID); len(groups) != 0 {
+		t.Errorf("expected no groups of removed member, got %+v", groups)
+	}
+
+	list.RemoveMemberFromAll(friendID)
+
+	expected := map[int32]socialcache.FriendGroup{
+		1: {ID: 1, Name: "a", Members: map[steamid.SteamID]bool{}},
+		2: {ID: 2, Name: "b", Members: map[steamid.SteamID]bool{}},
+	}
+
+	if copies := list.GetCopy(); !reflect.DeepEqual(copies, expected) {
+		t.Errorf("expected %+v, got %+v", expected, copies)
+	}
+
+	list.Set(3, "a")
+
+	if group, err := list.ByName("a"); err != nil || group.ID != 1 {
+		t.Errorf("expected group 1 with the lowest ID, got %+v, %v", group, err)
+	}
+
+	if _, err := list.ByName("c"); err == nil {
+		t.Error("expected error for unknown name")
+	}
+
+	list.Remove(3)
+	list.Remove(2)
+
+	if _, err := list.ByID(2); err == nil || list.Count() != 1 {
+		t.Errorf("expected group 2 to be removed, got %d groups", list.Count())
+	}
+
+	list.Clear()
+
+	if list.Count() != 0 {
+		t.Errorf("expected cleared list, got %d groups", list.Count())
+	}
+}
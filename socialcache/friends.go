@@ -13,9 +13,12 @@ import (
 // 	for id, friend := range client.Social.Friends.GetCopy() {
 // 		log.Println(id, friend.Name)
 // 	}
+//
+// Changes are delivered to subscriptions as *FriendChange values, see Subscribe.
 type FriendsList struct {
 	mutex sync.RWMutex
 	byID  map[steamid.SteamID]*Friend
+	feed  feed
 }
 
 // NewFriendsList builds a new friends list
@@ -23,6 +26,12 @@ func NewFriendsList() *FriendsList {
 	return &FriendsList{byID: make(map[steamid.SteamID]*Friend)}
 }
 
+// Subscribe returns a subscription to the changes of the list, delivered as *FriendChange values.
+// Changes are queued for each subscription, so slow receivers don't block updates of the list.
+func (list *FriendsList) Subscribe() *Subscription {
+	return list.feed.subscribe()
+}
+
 // Add adds a friend to the friend list
 func (list *FriendsList) Add(friend Friend) {
 	list.mutex.Lock()
@@ -30,6 +39,7 @@ func (list *FriendsList) Add(friend Friend) {
 	_, exists := list.byID[friend.SteamID]
 	if !exists { //make sure this doesnt already exist
 		list.byID[friend.SteamID] = &friend
+		list.feed.publish(&FriendChange{Kind: ChangeAdded, SteamID: friend.SteamID, New: friend})
 	}
 }
 
@@ -37,7 +47,22 @@ func (list *FriendsList) Add(friend Friend) {
 func (list *FriendsList) Remove(id steamid.SteamID) {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
+	val, exists := list.byID[id]
 	delete(list.byID, id)
+	if exists {
+		list.feed.publish(&FriendChange{Kind: ChangeRemoved, SteamID: id, Old: *val})
+	}
+}
+
+// Restore replaces the friends of the list, without notifying subscriptions
+func (list *FriendsList) Restore(friends map[steamid.SteamID]Friend) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID = make(map[steamid.SteamID]*Friend, len(friends))
+	for key, friend := range friends {
+		friend := friend
+		list.byID[key] = &friend
+	}
 }
 
 // Returns a copy of the friends map
@@ -70,66 +95,87 @@ func (list *FriendsList) Count() int {
 
 //Setter methods
 func (list *FriendsList) SetName(id steamid.SteamID, name string) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeName, func(val *Friend) {
 		val.Name = name
-	}
+	})
+}
+
+func (list *FriendsList) SetNickname(id steamid.SteamID, nickname string) {
+	list.update(id, ChangeNickname, func(val *Friend) {
+		val.Nickname = nickname
+	})
+}
+
+func (list *FriendsList) SetFavorite(id steamid.SteamID, favorite bool) {
+	list.update(id, ChangeFavorite, func(val *Friend) {
+		val.Favorite = favorite
+	})
 }
 
 func (list *FriendsList) SetAvatar(id steamid.SteamID, hash string) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeAvatar, func(val *Friend) {
 		val.Avatar = hash
-	}
+	})
 }
 
 func (list *FriendsList) SetRelationship(id steamid.SteamID, relationship steamlang.EFriendRelationship) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeRelationship, func(val *Friend) {
 		val.Relationship = relationship
-	}
+	})
 }
 
 func (list *FriendsList) SetPersonaState(id steamid.SteamID, state steamlang.EPersonaState) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangePersonaState, func(val *Friend) {
 		val.PersonaState = state
-	}
+	})
 }
 
 func (list *FriendsList) SetPersonaStateFlags(id steamid.SteamID, flags steamlang.EPersonaStateFlag) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangePersonaState, func(val *Friend) {
 		val.PersonaStateFlags = flags
-	}
+	})
 }
 
 func (list *FriendsList) SetGameAppID(id steamid.SteamID, gameAppID uint32) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeGame, func(val *Friend) {
 		val.GameAppID = gameAppID
-	}
+	})
 }
 
 func (list *FriendsList) SetGameID(id steamid.SteamID, gameID uint64) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeGame, func(val *Friend) {
 		val.GameID = gameID
-	}
+	})
 }
 
 func (list *FriendsList) SetGameName(id steamid.SteamID, name string) {
+	list.update(id, ChangeGame, func(val *Friend) {
+		val.GameName = name
+	})
+}
+
+// SetGame sets the game a friend is playing, notifying a single change
+func (list *FriendsList) SetGame(id steamid.SteamID, gameAppID uint32, gameID uint64, name string) {
+	list.update(id, ChangeGame, func(val *Friend) {
+		val.GameAppID = gameAppID
+		val.GameID = gameID
+		val.GameName = name
+	})
+}
+
+// update applies a change to a friend, notifying subscriptions if it changed anything
+func (list *FriendsList) update(id steamid.SteamID, kind ChangeKind, fn func(*Friend)) {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
-	if val, ok := list.byID[id]; ok {
-		val.GameName = name
+	val, ok := list.byID[id]
+	if !ok {
+		return
+	}
+	old := *val
+	fn(val)
+	updated := *val
+	if updated != old {
+		list.feed.publish(&FriendChange{Kind: kind, SteamID: id, Old: old, New: updated})
 	}
 }
 
@@ -137,6 +183,8 @@ func (list *FriendsList) SetGameName(id steamid.SteamID, name string) {
 type Friend struct {
 	SteamID           steamid.SteamID `json:",string"`
 	Name              string
+	Nickname          string // given by the user, empty if not set
+	Favorite          bool   // in the favorites of the user
 	Avatar            string
 	Relationship      steamlang.EFriendRelationship
 	PersonaState      steamlang.EPersonaState
@@ -13,9 +13,12 @@ import (
 // 	for id, group := range client.Social.Groups.GetCopy() {
 // 		log.Println(id, group.Name)
 // 	}
+//
+// Changes are delivered to subscriptions as *GroupChange values, see Subscribe.
 type GroupsList struct {
 	mutex sync.RWMutex
 	byID  map[steamid.SteamID]*Group
+	feed  feed
 }
 
 // Returns a new groups list
@@ -23,6 +26,12 @@ func NewGroupsList() *GroupsList {
 	return &GroupsList{byID: make(map[steamid.SteamID]*Group)}
 }
 
+// Subscribe returns a subscription to the changes of the list, delivered as *GroupChange values.
+// Changes are queued for each subscription, so slow receivers don't block updates of the list.
+func (list *GroupsList) Subscribe() *Subscription {
+	return list.feed.subscribe()
+}
+
 // Adds a group to the group list
 func (list *GroupsList) Add(group Group) {
 	list.mutex.Lock()
@@ -30,6 +39,7 @@ func (list *GroupsList) Add(group Group) {
 	_, exists := list.byID[group.SteamID]
 	if !exists { //make sure this doesnt already exist
 		list.byID[group.SteamID] = &group
+		list.feed.publish(&GroupChange{Kind: ChangeAdded, SteamID: group.SteamID, New: group})
 	}
 }
 
@@ -37,7 +47,22 @@ func (list *GroupsList) Add(group Group) {
 func (list *GroupsList) Remove(id steamid.SteamID) {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
+	val, exists := list.byID[id]
 	delete(list.byID, id)
+	if exists {
+		list.feed.publish(&GroupChange{Kind: ChangeRemoved, SteamID: id, Old: *val})
+	}
+}
+
+// Restore replaces the groups of the list, without notifying subscriptions
+func (list *GroupsList) Restore(groups map[steamid.SteamID]Group) {
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+	list.byID = make(map[steamid.SteamID]*Group, len(groups))
+	for key, group := range groups {
+		group := group
+		list.byID[key] = &group
+	}
 }
 
 // GetCopy returns a copy of the groups map
@@ -74,65 +99,70 @@ func (list *GroupsList) Count() int {
 
 //Setter methods
 func (list *GroupsList) SetName(id steamid.SteamID, name string) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeName, func(val *Group) {
 		val.Name = name
-	}
+	})
 }
 
 func (list *GroupsList) SetAvatar(id steamid.SteamID, hash string) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeAvatar, func(val *Group) {
 		val.Avatar = hash
-	}
+	})
 }
 
 func (list *GroupsList) SetRelationship(id steamid.SteamID, relationship steamlang.EClanRelationship) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeRelationship, func(val *Group) {
 		val.Relationship = relationship
-	}
+	})
 }
 
 func (list *GroupsList) SetMemberTotalCount(id steamid.SteamID, count uint32) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeMemberCount, func(val *Group) {
 		val.MemberTotalCount = count
-	}
+	})
 }
 
 func (list *GroupsList) SetMemberOnlineCount(id steamid.SteamID, count uint32) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeMemberCount, func(val *Group) {
 		val.MemberOnlineCount = count
-	}
+	})
 }
 
 func (list *GroupsList) SetMemberChattingCount(id steamid.SteamID, count uint32) {
-	list.mutex.Lock()
-	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
+	list.update(id, ChangeMemberCount, func(val *Group) {
 		val.MemberChattingCount = count
-	}
+	})
 }
 
 func (list *GroupsList) SetMemberInGameCount(id steamid.SteamID, count uint32) {
+	list.update(id, ChangeMemberCount, func(val *Group) {
+		val.MemberInGameCount = count
+	})
+}
+
+// SetMemberCounts sets all member counts of a group, notifying a single change
+func (list *GroupsList) SetMemberCounts(id steamid.SteamID, total, online, chatting, inGame uint32) {
+	list.update(id, ChangeMemberCount, func(val *Group) {
+		val.MemberTotalCount = total
+		val.MemberOnlineCount = online
+		val.MemberChattingCount = chatting
+		val.MemberInGameCount = inGame
+	})
+}
+
+// update applies a change to a group, notifying subscriptions if it changed anything
+func (list *GroupsList) update(id steamid.SteamID, kind ChangeKind, fn func(*Group)) {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
-	// id = id.ChatToClan()
-	if val, ok := list.byID[id]; ok {
-		val.MemberInGameCount = count
+	val, ok := list.byID[id]
+	if !ok {
+		return
+	}
+	old := *val
+	fn(val)
+	updated := *val
+	if updated != old {
+		list.feed.publish(&GroupChange{Kind: kind, SteamID: id, Old: old, New: updated})
 	}
 }
 
@@ -0,0 +1,204 @@
+package socialcache
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+// Snapshot is a copy of the friends, groups and chats lists at some point in time, which can be
+// persisted and later compared with the current lists, for example after restarting.
+type Snapshot struct {
+	Time    time.Time
+	Friends map[steamid.SteamID]Friend
+	Groups  map[steamid.SteamID]Group
+	Chats   map[steamid.SteamID]Chat
+}
+
+// NewSnapshot takes a snapshot of the given lists, any of which may be nil.
+func NewSnapshot(friends *FriendsList, groups *GroupsList, chats *ChatsList) *Snapshot {
+	s := &Snapshot{
+		Time:    time.Now(),
+		Friends: make(map[steamid.SteamID]Friend),
+		Groups:  make(map[steamid.SteamID]Group),
+		Chats:   make(map[steamid.SteamID]Chat),
+	}
+
+	if friends != nil {
+		s.Friends = friends.GetCopy()
+	}
+
+	if groups != nil {
+		s.Groups = groups.GetCopy()
+	}
+
+	if chats != nil {
+		s.Chats = chats.GetCopy()
+	}
+
+	return s
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	s := &Snapshot{}
+
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Write writes the snapshot as JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Restore replaces the contents of the given lists, any of which may be nil, with the snapshot,
+// without notifying subscriptions.
+func (s *Snapshot) Restore(friends *FriendsList, groups *GroupsList, chats *ChatsList) {
+	if friends != nil {
+		friends.Restore(s.Friends)
+	}
+
+	if groups != nil {
+		groups.Restore(s.Groups)
+	}
+
+	if chats != nil {
+		chats.Restore(s.Chats)
+	}
+}
+
+// Diff returns the changes from the snapshot to a newer one, as the *FriendChange, *GroupChange and
+// *ChatChange values delivered by subscriptions. Changes are sorted by list and unordered within
+// each list.
+func (s *Snapshot) Diff(newer *Snapshot) []interface{} {
+	var changes []interface{}
+
+	for id, old := range s.Friends {
+		if _, ok := newer.Friends[id]; !ok {
+			changes = append(changes, &FriendChange{Kind: ChangeRemoved, SteamID: id, Old: old})
+		}
+	}
+
+	for id, friend := range newer.Friends {
+		old, ok := s.Friends[id]
+
+		if !ok {
+			changes = append(changes, &FriendChange{Kind: ChangeAdded, SteamID: id, New: friend})
+			continue
+		}
+
+		for _, kind := range diffFriend(old, friend) {
+			changes = append(changes, &FriendChange{Kind: kind, SteamID: id, Old: old, New: friend})
+		}
+	}
+
+	for id, old := range s.Groups {
+		if _, ok := newer.Groups[id]; !ok {
+			changes = append(changes, &GroupChange{Kind: ChangeRemoved, SteamID: id, Old: old})
+		}
+	}
+
+	for id, group := range newer.Groups {
+		old, ok := s.Groups[id]
+
+		if !ok {
+			changes = append(changes, &GroupChange{Kind: ChangeAdded, SteamID: id, New: group})
+			continue
+		}
+
+		for _, kind := range diffGroup(old, group) {
+			changes = append(changes, &GroupChange{Kind: kind, SteamID: id, Old: old, New: group})
+		}
+	}
+
+	for id := range s.Chats {
+		if _, ok := newer.Chats[id]; !ok {
+			changes = append(changes, &ChatChange{Kind: ChangeRemoved, SteamID: id})
+		}
+	}
+
+	for id, chat := range newer.Chats {
+		old, ok := s.Chats[id]
+
+		if !ok {
+			changes = append(changes, &ChatChange{Kind: ChangeAdded, SteamID: id})
+		}
+
+		for memberID, member := range old.ChatMembers {
+			if _, ok := chat.ChatMembers[memberID]; !ok {
+				changes = append(changes, &ChatChange{Kind: ChangeMemberRemoved, SteamID: id, Member: member})
+			}
+		}
+
+		for memberID, member := range chat.ChatMembers {
+			if oldMember, ok := old.ChatMembers[memberID]; !ok {
+				changes = append(changes, &ChatChange{Kind: ChangeMemberAdded, SteamID: id, Member: member})
+			} else if oldMember != member {
+				changes = append(changes, &ChatChange{Kind: ChangeMemberPermissions, SteamID: id, Member: member})
+			}
+		}
+	}
+
+	return changes
+}
+
+func diffFriend(old, friend Friend) []ChangeKind {
+	var kinds []ChangeKind
+
+	if old.Relationship != friend.Relationship {
+		kinds = append(kinds, ChangeRelationship)
+	}
+
+	if old.Name != friend.Name {
+		kinds = append(kinds, ChangeName)
+	}
+
+	if old.Nickname != friend.Nickname {
+		kinds = append(kinds, ChangeNickname)
+	}
+
+	if old.Avatar != friend.Avatar {
+		kinds = append(kinds, ChangeAvatar)
+	}
+
+	if old.PersonaState != friend.PersonaState || old.PersonaStateFlags != friend.PersonaStateFlags {
+		kinds = append(kinds, ChangePersonaState)
+	}
+
+	if old.GameAppID != friend.GameAppID || old.GameID != friend.GameID || old.GameName != friend.GameName {
+		kinds = append(kinds, ChangeGame)
+	}
+
+	return kinds
+}
+
+func diffGroup(old, group Group) []ChangeKind {
+	var kinds []ChangeKind
+
+	if old.Relationship != group.Relationship {
+		kinds = append(kinds, ChangeRelationship)
+	}
+
+	if old.Name != group.Name {
+		kinds = append(kinds, ChangeName)
+	}
+
+	if old.Avatar != group.Avatar {
+		kinds = append(kinds, ChangeAvatar)
+	}
+
+	if old.MemberTotalCount != group.MemberTotalCount ||
+		old.MemberOnlineCount != group.MemberOnlineCount ||
+		old.MemberChattingCount != group.MemberChattingCount ||
+		old.MemberInGameCount != group.MemberInGameCount {
+		kinds = append(kinds, ChangeMemberCount)
+	}
+
+	return kinds
+}
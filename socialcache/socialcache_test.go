@@ -0,0 +1,202 @@
+package socialcache_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/13k/go-steam-resources/steamlang"
+
+	"github.com/13k/go-steam/socialcache"
+	"github.com/13k/go-steam/steamid"
+)
+
+const (
+	friendID steamid.SteamID = 76561197960287930
+	otherID  steamid.SteamID = 76561197960287931
+	groupID  steamid.SteamID = 103582791429521412
+)
+
+func TestFriendsListSubscribe(t *testing.T) {
+	list := socialcache.NewFriendsList()
+	sub := list.Subscribe()
+	defer sub.Unsubscribe()
+
+	go func() {
+		list.Add(socialcache.Friend{SteamID: friendID, Relationship: steamlang.EFriendRelationship_RequestRecipient})
+		list.Add(socialcache.Friend{SteamID: friendID})
+		list.SetRelationship(friendID, steamlang.EFriendRelationship_Friend)
+		list.SetName(friendID, "gabe")
+		list.SetName(friendID, "gabe")
+		list.SetGame(friendID, 570, 570, "Dota 2")
+		list.SetFavorite(friendID, true)
+		list.Remove(friendID)
+	}()
+
+	expected := []socialcache.ChangeKind{
+		socialcache.ChangeAdded,
+		socialcache.ChangeRelationship,
+		socialcache.ChangeName,
+		socialcache.ChangeGame,
+		socialcache.ChangeFavorite,
+		socialcache.ChangeRemoved,
+	}
+
+	for _, kind := range expected {
+		change := (<-sub.C()).(*socialcache.FriendChange)
+
+		if change.Kind != kind || change.SteamID != friendID {
+			t.Fatalf("expected %s change of %d, got %s change of %d", kind, friendID, change.Kind, change.SteamID)
+		}
+
+		if kind == socialcache.ChangeGame && (change.Old.GameAppID != 0 || change.New.GameAppID != 570) {
+			t.Errorf("unexpected game change %+v", change)
+		}
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	list := socialcache.NewGroupsList()
+	sub := list.Subscribe()
+	sub.Unsubscribe()
+
+	// must not block, even though the buffer is exceeded
+	for i := 0; i < 100; i++ {
+		list.Add(socialcache.Group{SteamID: steamid.SteamID(i + 1)})
+	}
+
+	if list.Count() != 100 {
+		t.Errorf("expected 100 groups, got %d", list.Count())
+	}
+}
+
+func TestSubscriptionQueue(t *testing.T) {
+	list := socialcache.NewGroupsList()
+	sub := list.Subscribe()
+	defer sub.Unsubscribe()
+
+	// must not block, even though nothing is received and the buffer is exceeded
+	for i := 0; i < 100; i++ {
+		list.Add(socialcache.Group{SteamID: steamid.SteamID(i + 1)})
+	}
+
+	for i := 0; i < 100; i++ {
+		select {
+		case c := <-sub.C():
+			if change := c.(*socialcache.GroupChange); change.SteamID != steamid.SteamID(i+1) {
+				t.Fatalf("expected change %d of group %d, got %+v", i, i+1, change)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for change %d", i)
+		}
+	}
+}
+
+func TestSubscriptionOrder(t *testing.T) {
+	list := socialcache.NewFriendsList()
+	list.Add(socialcache.Friend{SteamID: friendID})
+
+	sub := list.Subscribe()
+	defer sub.Unsubscribe()
+
+	const updates = 200
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < updates/4; j++ {
+				list.SetName(friendID, fmt.Sprintf("%d-%d", i, j))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// changes are delivered in the order they were applied, so each one follows the previous
+	last := ""
+
+	for i := 0; i < updates; i++ {
+		change := (<-sub.C()).(*socialcache.FriendChange)
+
+		if change.Old.Name != last {
+			t.Fatalf("change %d: expected old name %q, got %q", i, last, change.Old.Name)
+		}
+
+		last = change.New.Name
+	}
+
+	if name := list.GetCopy()[friendID].Name; name != last {
+		t.Errorf("expected last change to be %q, got %q", name, last)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	friends := socialcache.NewFriendsList()
+	groups := socialcache.NewGroupsList()
+	chats := socialcache.NewChatsList()
+
+	friends.Add(socialcache.Friend{SteamID: friendID, Relationship: steamlang.EFriendRelationship_Friend, Name: "gabe"})
+	friends.Add(socialcache.Friend{SteamID: otherID, Relationship: steamlang.EFriendRelationship_Friend})
+	groups.Add(socialcache.Group{SteamID: groupID, MemberTotalCount: 10})
+	chats.AddChatMember(groupID, socialcache.ChatMember{SteamID: friendID})
+
+	buf := &bytes.Buffer{}
+
+	if err := socialcache.NewSnapshot(friends, groups, chats).Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := socialcache.ReadSnapshot(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(old.Friends, friends.GetCopy()) || !reflect.DeepEqual(old.Chats, chats.GetCopy()) {
+		t.Fatalf("snapshot was not restored: %+v", old)
+	}
+
+	friends.SetName(friendID, "gaben")
+	friends.Remove(otherID)
+	groups.SetMemberTotalCount(groupID, 11)
+	chats.RemoveChatMember(groupID, friendID)
+
+	changes := map[string]bool{}
+
+	for _, change := range old.Diff(socialcache.NewSnapshot(friends, groups, chats)) {
+		switch c := change.(type) {
+		case *socialcache.FriendChange:
+			changes["friend "+c.Kind.String()+" "+c.SteamID.String()] = true
+		case *socialcache.GroupChange:
+			changes["group "+c.Kind.String()+" "+c.SteamID.String()] = true
+		case *socialcache.ChatChange:
+			changes["chat "+c.Kind.String()+" "+c.Member.SteamID.String()] = true
+		}
+	}
+
+	expected := map[string]bool{
+		"friend Name " + friendID.String():        true,
+		"friend Removed " + otherID.String():      true,
+		"group MemberCount " + groupID.String():   true,
+		"chat MemberRemoved " + friendID.String(): true,
+	}
+
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %v, got %v", expected, changes)
+	}
+
+	restored := socialcache.NewFriendsList()
+	old.Restore(restored, nil, nil)
+
+	if !reflect.DeepEqual(restored.GetCopy(), old.Friends) {
+		t.Errorf("expected restored friends %v, got %v", old.Friends, restored.GetCopy())
+	}
+}
@@ -0,0 +1,56 @@
+package steamid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// quickInviteAlphabet replaces the hex digits of account IDs in quick invite codes.
+const quickInviteAlphabet = "bcdfghjkmnpqrtvw"
+
+// QuickInviteCode returns the quick invite code of the account, that is, the `code` in friend invite
+// links (`https://s.team/p/code/token`).
+//
+// The code is the hexadecimal AccountID with its digits replaced by letters, split in two halves by
+// a dash.
+func (id SteamID) QuickInviteCode() string {
+	hex := strconv.FormatUint(uint64(id.AccountID()), 16)
+	code := make([]byte, len(hex))
+
+	for i := range hex {
+		code[i] = quickInviteAlphabet[strings.IndexByte("0123456789abcdef", hex[i])]
+	}
+
+	half := len(code) / 2
+
+	if half == 0 {
+		return string(code)
+	}
+
+	return string(code[:half]) + "-" + string(code[half:])
+}
+
+// ParseQuickInviteCode parses a quick invite code, see SteamID.QuickInviteCode, returning the
+// SteamID of the individual account it belongs to. Dashes are ignored.
+func ParseQuickInviteCode(code string) (SteamID, error) {
+	letters := strings.ReplaceAll(code, "-", "")
+
+	if letters == "" || len(letters) > 8 {
+		return 0, fmt.Errorf("steamid: invalid quick invite code %q", code)
+	}
+
+	var accountID uint32
+
+	for i := range letters {
+		digit := strings.IndexByte(quickInviteAlphabet, letters[i])
+
+		if digit < 0 {
+			return 0, fmt.Errorf("steamid: invalid quick invite code %q", code)
+		}
+
+		accountID = accountID<<4 | uint32(digit)
+	}
+
+	return AccountID(accountID).SteamID(), nil
+}
@@ -0,0 +1,85 @@
+package steamid_test
+
+import (
+	"testing"
+
+	"github.com/13k/go-steam/steamid"
+)
+
+func TestSteamID_QuickInviteCode(t *testing.T) {
+	testCases := []struct {
+		Subject  steamid.SteamID
+		Expected string
+	}{
+		{
+			Subject:  steamid.AccountID(69038686).SteamID(),
+			Expected: "gct-kdhv",
+		},
+		{
+			Subject:  steamid.AccountID(0xffffffff).SteamID(),
+			Expected: "wwww-wwww",
+		},
+		{
+			Subject:  steamid.AccountID(1).SteamID(),
+			Expected: "c",
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := testCase.Subject.QuickInviteCode()
+
+		if actual != testCase.Expected {
+			t.Errorf("%d: expected %q, got %q", testCase.Subject, testCase.Expected, actual)
+		}
+	}
+}
+
+func TestParseQuickInviteCode(t *testing.T) {
+	testCases := []struct {
+		Subject  string
+		Expected steamid.SteamID
+		Err      string
+	}{
+		{
+			Subject:  "gct-kdhv",
+			Expected: steamid.AccountID(69038686).SteamID(),
+		},
+		{
+			Subject:  "gctkdhv",
+			Expected: steamid.AccountID(69038686).SteamID(),
+		},
+		{
+			Subject: "",
+			Err:     `steamid: invalid quick invite code ""`,
+		},
+		{
+			Subject: "gct-kdha",
+			Err:     `steamid: invalid quick invite code "gct-kdha"`,
+		},
+		{
+			Subject: "bbbbb-bbbbc",
+			Err:     `steamid: invalid quick invite code "bbbbb-bbbbc"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := steamid.ParseQuickInviteCode(testCase.Subject)
+
+		if testCase.Err != "" {
+			if err == nil || err.Error() != testCase.Err {
+				t.Errorf("%q: expected error %q, got %v", testCase.Subject, testCase.Err, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", testCase.Subject, err)
+			continue
+		}
+
+		if actual != testCase.Expected {
+			t.Errorf("%q: expected %d, got %d", testCase.Subject, testCase.Expected, actual)
+		}
+	}
+}
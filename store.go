@@ -0,0 +1,564 @@
+package steam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/13k/go-steam/kv"
+	"github.com/13k/go-steam/protocol"
+)
+
+// Store provides access to the account's licenses, product key activation, free licenses and
+// wallet.
+//
+// Steam sends the license list and the wallet info right after logging on and again whenever they
+// change. The latest ones of the current session are cached and can be awaited with GetLicenses and
+// GetWallet, which wait for the next session's ones after disconnecting.
+type Store struct {
+	client *Client
+
+	mutex         sync.RWMutex
+	licenses      []*License
+	licensesReady chan struct{}
+	wallet        *WalletInfoEvent
+	walletReady   chan struct{}
+}
+
+var _ protocol.PacketHandler = (*Store)(nil)
+
+func NewStore(client *Client) *Store {
+	return &Store{
+		client:        client,
+		licensesReady: make(chan struct{}),
+		walletReady:   make(chan struct{}),
+	}
+}
+
+// PurchaseError is returned by RedeemKey when Steam rejects the purchase.
+type PurchaseError struct {
+	Result steamlang.EResult
+	Detail steamlang.EPurchaseResultDetail
+}
+
+func (e *PurchaseError) Error() string {
+	return fmt.Sprintf("steam/store: purchase failed: %v (%v)", e.Result, e.Detail)
+}
+
+// GetLicenses returns the licenses owned by the account, waiting for Steam to send them if they
+// haven't been received yet.
+func (s *Store) GetLicenses(ctx context.Context) ([]*License, error) {
+	s.mutex.RLock()
+	ready := s.licensesReady
+	s.mutex.RUnlock()
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.licenses, nil
+}
+
+// GetWallet returns the wallet info of the account, waiting for Steam to send it if it hasn't been
+// received yet.
+func (s *Store) GetWallet(ctx context.Context) (*WalletInfoEvent, error) {
+	s.mutex.RLock()
+	ready := s.walletReady
+	s.mutex.RUnlock()
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.wallet, nil
+}
+
+// RedeemKey activates a product key on the account. A PurchaseResponseEvent is also emitted.
+//
+// If Steam doesn't accept the key, the response is returned along with a *PurchaseError.
+func (s *Store) RedeemKey(ctx context.Context, key string) (*PurchaseResponseEvent, error) {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRegisterKey, &pb.CMsgClientRegisterKey{
+		Key: proto.String(key),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := result.(*PurchaseResponseEvent)
+
+	if !ok {
+		return nil, unexpectedResultError(result)
+	}
+
+	if event.Result != steamlang.EResult_OK {
+		return event, &PurchaseError{Result: event.Result, Detail: event.PurchaseResultDetail}
+	}
+
+	return event, nil
+}
+
+// RequestFreeLicense requests licenses for the given free apps. A FreeLicenseEvent is also emitted.
+//
+// Apps that are not free or already owned are not granted, without that being an error.
+func (s *Store) RequestFreeLicense(ctx context.Context, appIDs ...uint32) (*FreeLicenseEvent, error) {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRequestFreeLicense, &pb.CMsgClientRequestFreeLicense{
+		Appids: appIDs,
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := result.(*FreeLicenseEvent)
+
+	if !ok {
+		return nil, unexpectedResultError(result)
+	}
+
+	if event.Result != steamlang.EResult_OK {
+		return event, fmt.Errorf("steam/store: free license request failed: %v", event.Result)
+	}
+
+	return event, nil
+}
+
+// AckGuestPass acknowledges a guest pass (a Steam gift) sent to the account, which moves it to the
+// account's gift inventory. An AckGuestPassEvent is also emitted.
+//
+// The response is returned along with an error if Steam doesn't accept the acknowledgment.
+func (s *Store) AckGuestPass(ctx context.Context, guestPassID uint64) (*AckGuestPassEvent, error) {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientAckGuestPass, newAckGuestPass(guestPassID))
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := result.(*AckGuestPassEvent)
+
+	if !ok {
+		return nil, unexpectedResultError(result)
+	}
+
+	if event.Result != steamlang.EResult_OK {
+		return event, fmt.Errorf("steam/store: guest pass acknowledgment failed: %v", event.Result)
+	}
+
+	return event, nil
+}
+
+// RedeemGuestPass activates a guest pass (a Steam gift) on the account. A RedeemGuestPassEvent is
+// also emitted.
+//
+// The response is returned along with an error if Steam doesn't redeem the guest pass, for example
+// when the account doesn't own the app required by the package (see MustOwnAppID).
+func (s *Store) RedeemGuestPass(ctx context.Context, guestPassID uint64) (*RedeemGuestPassEvent, error) {
+	msg := protocol.NewProtoMessage(steamlang.EMsg_ClientRedeemGuestPass, &pb.CMsgClientRedeemGuestPass{
+		GuestPassId: proto.Uint64(guestPassID),
+	})
+
+	result, err := s.client.call(ctx, msg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := result.(*RedeemGuestPassEvent)
+
+	if !ok {
+		return nil, unexpectedResultError(result)
+	}
+
+	if event.Result != steamlang.EResult_OK {
+		return event, fmt.Errorf("steam/store: guest pass redemption failed: %v", event.Result)
+	}
+
+	return event, nil
+}
+
+func (s *Store) HandlePacket(packet *protocol.Packet) {
+	switch packet.EMsg() {
+	case steamlang.EMsg_ClientLogOnResponse:
+		s.reset()
+	case steamlang.EMsg_ClientLicenseList:
+		s.handleLicenseList(packet)
+	case steamlang.EMsg_ClientPurchaseResponse:
+		s.handlePurchaseResponse(packet)
+	case steamlang.EMsg_ClientRequestFreeLicenseResponse:
+		s.handleFreeLicenseResponse(packet)
+	case steamlang.EMsg_ClientWalletInfoUpdate:
+		s.handleWalletInfoUpdate(packet)
+	case steamlang.EMsg_ClientUpdateGuestPassesList:
+		s.handleUpdateGuestPassesList(packet)
+	case steamlang.EMsg_ClientAckGuestPassResponse:
+		s.handleAckGuestPassResponse(packet)
+	case steamlang.EMsg_ClientRedeemGuestPassResponse:
+		s.handleRedeemGuestPassResponse(packet)
+	}
+}
+
+// reset forgets the licenses and wallet info of the previous session. Waiters that haven't received
+// them yet keep waiting for the next ones.
+func (s *Store) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.licenses = nil
+	s.wallet = nil
+
+	select {
+	case <-s.licensesReady:
+		s.licensesReady = make(chan struct{})
+	default:
+	}
+
+	select {
+	case <-s.walletReady:
+		s.walletReady = make(chan struct{})
+	default:
+	}
+}
+
+func (s *Store) handleLicenseList(packet *protocol.Packet) {
+	body := &pb.CMsgClientLicenseList{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/LicenseList: error reading message: %v", err)
+		return
+	}
+
+	licenses := make([]*License, len(body.GetLicenses()))
+
+	for i, l := range body.GetLicenses() {
+		licenses[i] = &License{
+			PackageID:           l.GetPackageId(),
+			TimeCreated:         time.Unix(int64(l.GetTimeCreated()), 0),
+			TimeNextProcess:     time.Unix(int64(l.GetTimeNextProcess()), 0),
+			MinuteLimit:         l.GetMinuteLimit(),
+			MinutesUsed:         l.GetMinutesUsed(),
+			PaymentMethod:       steamlang.EPaymentMethod(l.GetPaymentMethod()),
+			Flags:               steamlang.ELicenseFlags(l.GetFlags()),
+			PurchaseCountryCode: l.GetPurchaseCountryCode(),
+			LicenseType:         steamlang.ELicenseType(l.GetLicenseType()),
+			TerritoryCode:       l.GetTerritoryCode(),
+			ChangeNumber:        l.GetChangeNumber(),
+			OwnerID:             l.GetOwnerId(),
+			AccessToken:         l.GetAccessToken(),
+			MasterPackageID:     l.GetMasterPackageId(),
+		}
+	}
+
+	result := steamlang.EResult(body.GetEresult())
+
+	if result == steamlang.EResult_OK {
+		s.mutex.Lock()
+
+		s.licenses = licenses
+
+		select {
+		case <-s.licensesReady:
+		default:
+			close(s.licensesReady)
+		}
+
+		s.mutex.Unlock()
+	}
+
+	s.client.Emit(&LicenseListEvent{Result: result, Licenses: licenses})
+}
+
+func (s *Store) handlePurchaseResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientPurchaseResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/PurchaseResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &PurchaseResponseEvent{
+		Result:               steamlang.EResult(body.GetEresult()),
+		PurchaseResultDetail: steamlang.EPurchaseResultDetail(body.GetPurchaseResultDetails()),
+	}
+
+	if len(body.GetPurchaseReceiptInfo()) > 0 {
+		receipt, err := newPurchaseReceipt(body.GetPurchaseReceiptInfo())
+
+		if err != nil {
+			s.client.Errorf("store/PurchaseResponse: error reading receipt: %v", err)
+		}
+
+		event.Receipt = receipt
+	}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Store) handleFreeLicenseResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientRequestFreeLicenseResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/RequestFreeLicenseResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &FreeLicenseEvent{
+		Result:            steamlang.EResult(body.GetEresult()),
+		GrantedPackageIDs: body.GetGrantedPackageids(),
+		GrantedAppIDs:     body.GetGrantedAppids(),
+	}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Store) handleWalletInfoUpdate(packet *protocol.Packet) {
+	body := &pb.CMsgClientWalletInfoUpdate{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/WalletInfoUpdate: error reading message: %v", err)
+		return
+	}
+
+	event := &WalletInfoEvent{
+		HasWallet:      body.GetHasWallet(),
+		Currency:       steamlang.ECurrencyCode(body.GetCurrency()),
+		Balance:        body.GetBalance64(),
+		BalanceDelayed: body.GetBalance64Delayed(),
+	}
+
+	// older servers only send the 32 bit balances
+	if body.Balance64 == nil {
+		event.Balance = int64(body.GetBalance())
+		event.BalanceDelayed = int64(body.GetBalanceDelayed())
+	}
+
+	s.mutex.Lock()
+
+	s.wallet = event
+
+	select {
+	case <-s.walletReady:
+	default:
+		close(s.walletReady)
+	}
+
+	s.mutex.Unlock()
+
+	s.client.Emit(event)
+}
+
+func (s *Store) handleUpdateGuestPassesList(packet *protocol.Packet) {
+	body := steamlang.NewMsgClientUpdateGuestPassesList()
+	msg, err := packet.ReadClientMsg(body)
+
+	if err != nil {
+		s.client.Errorf("store/UpdateGuestPassesList: error reading message: %v", err)
+		return
+	}
+
+	event := &GuestPassListEvent{Result: body.Result}
+
+	// the guest passes to give are followed by the ones to redeem, each a "MessageObject" binary
+	// KeyValue
+	dec := kv.NewBinaryDecoder(bytes.NewReader(msg.Payload))
+	count := int(body.CountGuestPassesToGive + body.CountGuestPassesToRedeem)
+
+	for i := 0; i < count; i++ {
+		root := kv.NewKeyValueEmpty()
+
+		if err := dec.Decode(root); err != nil {
+			s.client.Errorf("store/UpdateGuestPassesList: error reading guest pass: %v", err)
+			break
+		}
+
+		pass := newGuestPass(root)
+
+		if i < int(body.CountGuestPassesToGive) {
+			event.ToGive = append(event.ToGive, pass)
+		} else {
+			event.ToRedeem = append(event.ToRedeem, pass)
+		}
+	}
+
+	s.client.Emit(event)
+}
+
+func (s *Store) handleAckGuestPassResponse(packet *protocol.Packet) {
+	body := newAckGuestPassResponse()
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/AckGuestPassResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &AckGuestPassEvent{Result: steamlang.EResult(ackGuestPassResult(body))}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+func (s *Store) handleRedeemGuestPassResponse(packet *protocol.Packet) {
+	body := &pb.CMsgClientRedeemGuestPassResponse{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil {
+		s.client.Errorf("store/RedeemGuestPassResponse: error reading message: %v", err)
+		return
+	}
+
+	event := &RedeemGuestPassEvent{
+		Result:       steamlang.EResult(body.GetEresult()),
+		PackageID:    body.GetPackageId(),
+		MustOwnAppID: body.GetMustOwnAppid(),
+	}
+
+	s.client.Emit(event)
+	s.client.jobs.resolve(packet.TargetJobID(), event)
+}
+
+// GuestPass is a guest pass or Steam gift sent by or to the account.
+type GuestPass struct {
+	GuestPassID      uint64 `json:",string"`
+	PackageID        uint32
+	TimeCreated      time.Time
+	TimeExpiration   time.Time
+	TimeSent         time.Time
+	TimeAcked        time.Time
+	TimeRedeemed     time.Time
+	RecipientAddress string
+	SenderAddress    string
+	SenderName       string
+}
+
+func newGuestPass(root kv.KeyValue) *GuestPass {
+	unix := func(key string) time.Time {
+		if t := kvUint64(root, key); t != 0 {
+			return time.Unix(int64(t), 0)
+		}
+
+		return time.Time{}
+	}
+
+	return &GuestPass{
+		GuestPassID:      kvUint64(root, "gid"),
+		PackageID:        uint32(kvUint64(root, "packageid")),
+		TimeCreated:      unix("TimeCreated"),
+		TimeExpiration:   unix("TimeExpiration"),
+		TimeSent:         unix("TimeSent"),
+		TimeAcked:        unix("TimeAcked"),
+		TimeRedeemed:     unix("TimeRedeemed"),
+		RecipientAddress: kvString(root, "RecipientAddress"),
+		SenderAddress:    kvString(root, "SenderAddress"),
+		SenderName:       kvString(root, "SenderName"),
+	}
+}
+
+// License is a license (package) owned by the account.
+type License struct {
+	PackageID           uint32
+	TimeCreated         time.Time
+	TimeNextProcess     time.Time
+	MinuteLimit         int32
+	MinutesUsed         int32
+	PaymentMethod       steamlang.EPaymentMethod
+	Flags               steamlang.ELicenseFlags
+	PurchaseCountryCode string
+	LicenseType         steamlang.ELicenseType
+	TerritoryCode       int32
+	ChangeNumber        int32
+	// Account ID of the owner, for licenses borrowed through family sharing.
+	OwnerID         uint32
+	AccessToken     uint64 `json:",string"`
+	MasterPackageID uint32
+}
+
+// PurchaseReceipt is the receipt of a successful key activation.
+type PurchaseReceipt struct {
+	TransactionID uint64 `json:",string"`
+	PackageID     uint32
+	LineItems     []*PurchaseLineItem
+	// The whole receipt, a "MessageObject" binary KeyValue.
+	KeyValue kv.KeyValue `json:"-"`
+}
+
+// PurchaseLineItem is a package included in a PurchaseReceipt.
+type PurchaseLineItem struct {
+	PackageID   uint32
+	AppID       uint32
+	Description string
+}
+
+func newPurchaseReceipt(data []byte) (*PurchaseReceipt, error) {
+	root := kv.NewKeyValueEmpty()
+
+	if err := root.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	receipt := &PurchaseReceipt{
+		TransactionID: kvUint64(root, "transactionid"),
+		PackageID:     uint32(kvUint64(root, "packageid")),
+		KeyValue:      root,
+	}
+
+	if items := kvChild(root, "lineitems"); items != nil {
+		for _, item := range items.Children() {
+			receipt.LineItems = append(receipt.LineItems, &PurchaseLineItem{
+				PackageID:   uint32(kvUint64(item, "packageid")),
+				AppID:       uint32(kvUint64(item, "appid")),
+				Description: kvString(item, "itemdescription"),
+			})
+		}
+	}
+
+	return receipt, nil
+}
+
+// kvChild finds a child node by its key, ignoring case. The casing of receipt keys is not
+// consistent.
+func kvChild(node kv.KeyValue, key string) kv.KeyValue {
+	for _, child := range node.Children() {
+		if strings.EqualFold(child.Key(), key) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func kvString(node kv.KeyValue, key string) string {
+	if child := kvChild(node, key); child != nil {
+		return child.Value()
+	}
+
+	return ""
+}
+
+func kvUint64(node kv.KeyValue, key string) uint64 {
+	n, _ := strconv.ParseUint(kvString(node, key), 10, 64)
+	return n
+}
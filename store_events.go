@@ -0,0 +1,60 @@
+package steam
+
+import (
+	"github.com/13k/go-steam-resources/steamlang"
+)
+
+// Fired when Steam sends the list of licenses owned by the account, after logging on and whenever
+// it changes.
+type LicenseListEvent struct {
+	Result   steamlang.EResult
+	Licenses []*License
+}
+
+// Fired in response to activating a product key
+type PurchaseResponseEvent struct {
+	Result               steamlang.EResult
+	PurchaseResultDetail steamlang.EPurchaseResultDetail
+	// nil if Steam didn't send a receipt
+	Receipt *PurchaseReceipt
+}
+
+// Fired in response to requesting free licenses
+type FreeLicenseEvent struct {
+	Result            steamlang.EResult
+	GrantedPackageIDs []uint32
+	GrantedAppIDs     []uint32
+}
+
+// Fired when Steam sends the wallet info, after logging on and whenever the balance changes.
+//
+// Balances are in cents of the wallet currency.
+type WalletInfoEvent struct {
+	HasWallet      bool
+	Currency       steamlang.ECurrencyCode
+	Balance        int64
+	BalanceDelayed int64
+}
+
+// Fired when Steam sends the guest passes (Steam gifts) of the account, after logging on and
+// whenever they change.
+type GuestPassListEvent struct {
+	Result steamlang.EResult
+	// Guest passes owned by the account that can be sent to others
+	ToGive []*GuestPass
+	// Guest passes sent to the account, waiting to be acknowledged or redeemed
+	ToRedeem []*GuestPass
+}
+
+// Fired in response to acknowledging a guest pass
+type AckGuestPassEvent struct {
+	Result steamlang.EResult
+}
+
+// Fired in response to redeeming a guest pass
+type RedeemGuestPassEvent struct {
+	Result    steamlang.EResult
+	PackageID uint32
+	// App that must be owned to redeem the guest pass, if that's why it failed
+	MustOwnAppID uint32
+}
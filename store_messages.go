@@ -0,0 +1,79 @@
+package steam
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// The guest pass acknowledgment messages are not included in the protobufs of go-steam-resources, so
+// they are described here, as found in steammessages_clientserver_2.proto, and built with dynamicpb:
+//
+//	message CMsgClientAckGuestPass {
+//		optional fixed64 guest_pass_id = 1;
+//	}
+//
+//	message CMsgClientAckGuestPassResponse {
+//		optional uint32 eresult = 1 [default = 2];
+//	}
+var (
+	ackGuestPassDesc         protoreflect.MessageDescriptor
+	ackGuestPassResponseDesc protoreflect.MessageDescriptor
+)
+
+func init() {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("go-steam/store_messages.proto"),
+		Package: proto.String("gosteam"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("CMsgClientAckGuestPass"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:     proto.String("guest_pass_id"),
+					JsonName: proto.String("guestPassId"),
+					Number:   proto.Int32(1),
+					Label:    optional,
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_FIXED64.Enum(),
+				}},
+			},
+			{
+				Name: proto.String("CMsgClientAckGuestPassResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:         proto.String("eresult"),
+					JsonName:     proto.String("eresult"),
+					Number:       proto.Int32(1),
+					Label:        optional,
+					Type:         descriptorpb.FieldDescriptorProto_TYPE_UINT32.Enum(),
+					DefaultValue: proto.String("2"),
+				}},
+			},
+		},
+	}, new(protoregistry.Files))
+
+	if err != nil {
+		panic(err)
+	}
+
+	ackGuestPassDesc = file.Messages().ByName("CMsgClientAckGuestPass")
+	ackGuestPassResponseDesc = file.Messages().ByName("CMsgClientAckGuestPassResponse")
+}
+
+func newAckGuestPass(guestPassID uint64) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(ackGuestPassDesc)
+	msg.Set(ackGuestPassDesc.Fields().ByNumber(1), protoreflect.ValueOfUint64(guestPassID))
+
+	return msg
+}
+
+func newAckGuestPassResponse() *dynamicpb.Message {
+	return dynamicpb.NewMessage(ackGuestPassResponseDesc)
+}
+
+func ackGuestPassResult(msg *dynamicpb.Message) uint32 {
+	return uint32(msg.Get(ackGuestPassResponseDesc.Fields().ByNumber(1)).Uint())
+}
@@ -0,0 +1,191 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/13k/go-steam-resources/protobuf/steam"
+	"github.com/13k/go-steam-resources/steamlang"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStoreLicensesReset(t *testing.T) {
+	client := newTestClient(t)
+
+	client.handle(steamlang.EMsg_ClientLicenseList, &pb.CMsgClientLicenseList{
+		Eresult:  proto.Int32(int32(steamlang.EResult_OK)),
+		Licenses: []*pb.CMsgClientLicenseList_License{{PackageId: proto.Uint32(1)}},
+	}, 0)
+
+	if _, ok := client.event().(*LicenseListEvent); !ok {
+		t.Fatal("expected LicenseListEvent")
+	}
+
+	licenses, err := client.Store.GetLicenses(context.Background())
+
+	if err != nil || len(licenses) != 1 || licenses[0].PackageID != 1 {
+		t.Fatalf("unexpected licenses %v, %v", licenses, err)
+	}
+
+	client.Disconnect()
+
+	if _, ok := client.event().(*DisconnectedEvent); !ok {
+		t.Fatal("expected DisconnectedEvent")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Store.GetLicenses(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected GetLicenses to wait for the next session, got %v", err)
+	}
+
+	// a waiter of the next session receives its license list
+	result := make(chan []*License, 1)
+
+	go func() {
+		licenses, _ := client.Store.GetLicenses(context.Background())
+		result <- licenses
+	}()
+
+	client.handle(steamlang.EMsg_ClientLicenseList, &pb.CMsgClientLicenseList{
+		Eresult:  proto.Int32(int32(steamlang.EResult_OK)),
+		Licenses: []*pb.CMsgClientLicenseList_License{{PackageId: proto.Uint32(2)}},
+	}, 0)
+
+	client.event()
+
+	select {
+	case licenses := <-result:
+		if len(licenses) != 1 || licenses[0].PackageID != 2 {
+			t.Errorf("unexpected licenses %v", licenses)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for licenses")
+	}
+}
+
+func TestStoreWallet(t *testing.T) {
+	client := newTestClient(t)
+
+	client.handle(steamlang.EMsg_ClientWalletInfoUpdate, &pb.CMsgClientWalletInfoUpdate{
+		HasWallet:      proto.Bool(true),
+		Balance:        proto.Int32(100),
+		BalanceDelayed: proto.Int32(5),
+		Currency:       proto.Int32(int32(steamlang.ECurrencyCode_EUR)),
+	}, 0)
+
+	client.event()
+
+	wallet, err := client.Store.GetWallet(context.Background())
+
+	if err != nil || !wallet.HasWallet || wallet.Balance != 100 || wallet.BalanceDelayed != 5 {
+		t.Fatalf("unexpected wallet %+v, %v", wallet, err)
+	}
+
+	// logging on again starts a new session
+	client.handle(steamlang.EMsg_ClientLogOnResponse, &pb.CMsgClientLogonResponse{
+		Eresult: proto.Int32(int32(steamlang.EResult_Fail)),
+	}, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Store.GetWallet(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected GetWallet to wait for the next session, got %v", err)
+	}
+}
+
+func TestStoreRedeemKey(t *testing.T) {
+	client := newTestClient(t)
+
+	type result struct {
+		event *PurchaseResponseEvent
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		event, err := client.Store.RedeemKey(context.Background(), "AAAAA-BBBBB-CCCCC")
+		done <- result{event, err}
+	}()
+
+	packet := client.next()
+	body := &pb.CMsgClientRegisterKey{}
+
+	if _, err := packet.ReadProtoMsg(body); err != nil || packet.EMsg() != steamlang.EMsg_ClientRegisterKey {
+		t.Fatalf("unexpected message %v, %v", packet, err)
+	}
+
+	if body.GetKey() != "AAAAA-BBBBB-CCCCC" {
+		t.Errorf("unexpected key %q", body.GetKey())
+	}
+
+	client.handle(steamlang.EMsg_ClientPurchaseResponse, &pb.CMsgClientPurchaseResponse{
+		Eresult:               proto.Int32(int32(steamlang.EResult_Fail)),
+		PurchaseResultDetails: proto.Int32(int32(steamlang.EPurchaseResultDetail_AlreadyPurchased)),
+	}, packet.SourceJobID())
+
+	client.event()
+
+	r := <-done
+
+	var perr *PurchaseError
+
+	if !errors.As(r.err, &perr) || perr.Detail != steamlang.EPurchaseResultDetail_AlreadyPurchased {
+		t.Fatalf("expected PurchaseError, got %v", r.err)
+	}
+
+	if r.event == nil || r.event.Result != steamlang.EResult_Fail {
+		t.Errorf("expected the response along with the error, got %+v", r.event)
+	}
+}
+
+func TestStoreAckGuestPass(t *testing.T) {
+	client := newTestClient(t)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := client.Store.AckGuestPass(context.Background(), 1234)
+		done <- err
+	}()
+
+	packet := client.next()
+	body := newAckGuestPass(0)
+
+	if _, err := packet.ReadProtoMsg(body); err != nil || packet.EMsg() != steamlang.EMsg_ClientAckGuestPass {
+		t.Fatalf("unexpected message %v, %v", packet, err)
+	}
+
+	if id := body.Get(ackGuestPassDesc.Fields().ByNumber(1)).Uint(); id != 1234 {
+		t.Errorf("unexpected guest pass ID %d", id)
+	}
+
+	// eresult defaults to EResult_Fail
+	client.handle(steamlang.EMsg_ClientAckGuestPassResponse, newAckGuestPassResponse(), packet.SourceJobID())
+
+	if event, ok := client.event().(*AckGuestPassEvent); !ok || event.Result != steamlang.EResult_Fail {
+		t.Fatalf("unexpected event %#v", event)
+	}
+
+	if err := <-done; err == nil {
+		t.Fatal("expected error")
+	}
+
+	// a job resolved with a result of another type
+	go func() {
+		_, err := client.Store.AckGuestPass(context.Background(), 1234)
+		done <- err
+	}()
+
+	packet = client.next()
+	client.jobs.resolve(packet.SourceJobID(), &RedeemGuestPassEvent{})
+
+	if err := <-done; err == nil || err.Error() != "steam: unexpected job result *steam.RedeemGuestPassEvent" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}